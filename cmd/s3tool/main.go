@@ -5,20 +5,38 @@ import (
 	"os"
 
 	"github.com/schidstorm/s3tool/internal/cli"
+	"github.com/schidstorm/s3tool/internal/commands"
 	"github.com/schidstorm/s3tool/internal/s3lib"
 	"github.com/schidstorm/s3tool/internal/terminal"
 )
 
 func main() {
-	runApp, err := cli.ParseAndShouldRun(os.Args[1:])
+	runApp, err := cli.ParseAndShouldRun(os.Args[1:], commands.Commands(loaders, transferOptions)...)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error parsing CLI arguments: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 	if !runApp {
 		return
 	}
 
+	theme, err := terminal.LoadTheme(cli.Config.Theme)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		theme = terminal.NoColorTheme()
+	}
+	terminal.SetTheme(theme)
+
+	keymap, err := terminal.LoadKeymap(cli.Config.Keymap)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	terminal.ActiveKeymap = keymap
+
 	app := terminal.NewApp(nil, loaders()...)
 	err = app.Run()
 	if err != nil {
@@ -41,3 +59,12 @@ func loaders() []s3lib.ConnectorLoader {
 	}
 	return loaders
 }
+
+// transferOptions returns the part size and concurrency of the transfer
+// settings of the config.
+func transferOptions() []func(*s3lib.TransferOptions) {
+	return []func(*s3lib.TransferOptions){
+		s3lib.WithPartSize(int64(cli.Config.Transfers.PartSize)),
+		s3lib.WithConcurrency(cli.Config.Transfers.PartConcurrency),
+	}
+}
@@ -1,17 +1,45 @@
 package cli
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
 )
 
 var Config = DefaultConfig()
 
+// DefaultConfigFile is read when neither --config nor S3TOOL_CONFIG name a
+// config file. It may not exist.
+const DefaultConfigFile = "~/.config/s3tool/config.yaml"
+
 type S3ToolCliConfig struct {
-	ProfilesDirectory string
+	ProfilesDirectory string                `yaml:"profiles_directory"`
 	Loaders           S3ToolCliConfigLoader `yaml:"loaders"`
+	// Editor and Viewer are the commands that edit and show objects. Empty
+	// commands fall back to the defaults of the terminal UI.
+	Editor string `yaml:"editor"`
+	Viewer string `yaml:"viewer"`
+	// Editors and Viewers override the editor and viewer for files with an
+	// extension like ".parquet" or a content type like "image/*".
+	Editors map[string]string `yaml:"editors,omitempty"`
+	Viewers map[string]string `yaml:"viewers,omitempty"`
+	// Theme is the name of a built-in theme or the path of a YAML theme.
+	Theme string `yaml:"theme"`
+	// Keymap is the path of the YAML file that binds the keys of the
+	// terminal UI.
+	Keymap    string                   `yaml:"keymap"`
+	Transfers S3ToolCliConfigTransfers `yaml:"transfers"`
+	// File is the config file that was read, empty if there was none.
+	File string `yaml:"-"`
 }
 
 type S3ToolCliConfigLoader struct {
@@ -20,6 +48,16 @@ type S3ToolCliConfigLoader struct {
 	Memory bool `yaml:"memory,omitempty"`
 }
 
+type S3ToolCliConfigTransfers struct {
+	// Concurrency is the number of transfers of the terminal UI running at
+	// the same time.
+	Concurrency int `yaml:"concurrency"`
+	// PartSize and PartConcurrency are the size of the parts of multipart
+	// transfers and the number of parts transferred in parallel.
+	PartSize        ByteSize `yaml:"part_size"`
+	PartConcurrency int      `yaml:"part_concurrency"`
+}
+
 func DefaultConfig() *S3ToolCliConfig {
 	return &S3ToolCliConfig{
 		ProfilesDirectory: "~/.s3tool",
@@ -28,60 +66,220 @@ func DefaultConfig() *S3ToolCliConfig {
 			S3Tool: true,
 			Memory: false,
 		},
+		Theme:  "dark",
+		Keymap: "~/.config/s3tool/keymap.yaml",
+		Transfers: S3ToolCliConfigTransfers{
+			Concurrency:     2,
+			PartSize:        8 << 20,
+			PartConcurrency: 4,
+		},
+	}
+}
+
+// setting is a config value that is set by its flag and by the environment
+// variable named after the flag, like S3TOOL_LOADERS_AWS for
+// --loaders.aws.
+type setting struct {
+	flag string
+	set  func(cfg *S3ToolCliConfig, value string) error
+}
+
+var settings = []setting{
+	{"profiles", setString(func(cfg *S3ToolCliConfig) *string { return &cfg.ProfilesDirectory })},
+	{"loaders.aws", setBool(func(cfg *S3ToolCliConfig) *bool { return &cfg.Loaders.Aws })},
+	{"loaders.s3tool", setBool(func(cfg *S3ToolCliConfig) *bool { return &cfg.Loaders.S3Tool })},
+	{"loaders.memory", setBool(func(cfg *S3ToolCliConfig) *bool { return &cfg.Loaders.Memory })},
+	{"editor", setString(func(cfg *S3ToolCliConfig) *string { return &cfg.Editor })},
+	{"viewer", setString(func(cfg *S3ToolCliConfig) *string { return &cfg.Viewer })},
+	{"theme", setString(func(cfg *S3ToolCliConfig) *string { return &cfg.Theme })},
+	{"keymap", setString(func(cfg *S3ToolCliConfig) *string { return &cfg.Keymap })},
+	{"transfers.concurrency", setInt(func(cfg *S3ToolCliConfig) *int { return &cfg.Transfers.Concurrency })},
+	{"transfers.part-size", func(cfg *S3ToolCliConfig, value string) error {
+		size, err := ParseByteSize(value)
+		cfg.Transfers.PartSize = size
+		return err
+	}},
+	{"transfers.part-concurrency", setInt(func(cfg *S3ToolCliConfig) *int { return &cfg.Transfers.PartConcurrency })},
+}
+
+func setString(field func(cfg *S3ToolCliConfig) *string) func(cfg *S3ToolCliConfig, value string) error {
+	return func(cfg *S3ToolCliConfig, value string) error {
+		*field(cfg) = value
+		return nil
+	}
+}
+
+func setBool(field func(cfg *S3ToolCliConfig) *bool) func(cfg *S3ToolCliConfig, value string) error {
+	return func(cfg *S3ToolCliConfig, value string) error {
+		b, err := strconv.ParseBool(value)
+		*field(cfg) = b
+		return err
+	}
+}
+
+func setInt(field func(cfg *S3ToolCliConfig) *int) func(cfg *S3ToolCliConfig, value string) error {
+	return func(cfg *S3ToolCliConfig, value string) error {
+		i, err := strconv.Atoi(value)
+		*field(cfg) = i
+		return err
 	}
 }
 
-func ParseAndShouldRun(args []string) (bool, error) {
-	var cfg S3ToolCliConfig
-	cmd := rootCmd(&cfg)
+// envName returns the environment variable of a flag.
+func envName(flag string) string {
+	return "S3TOOL_" + strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(flag))
+}
+
+// ParseAndShouldRun parses args and runs the selected subcommand. It reports
+// whether the root command was selected and the terminal UI should be started.
+// commands are added as subcommands and see the parsed Config when they run.
+func ParseAndShouldRun(args []string, commands ...*cobra.Command) (bool, error) {
+	var flags S3ToolCliConfig
+	var configFile string
+	cmd := rootCmd(&flags, &configFile)
 	runRoot := false
+	cmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig(configFile, cmd.Flags().Changed("config"), os.LookupEnv, cmd.Flags())
+		if err != nil {
+			return err
+		}
+		cleaned := cleanup(cfg)
+		Config = &cleaned
+		return nil
+	}
 	cmd.Run = func(cmd *cobra.Command, args []string) {
 		runRoot = true
 	}
 
 	cmd.AddCommand(completionCmd())
+	cmd.AddCommand(configCmd())
+	cmd.AddCommand(commands...)
 
 	cmd.SetArgs(args)
 	if err := cmd.Execute(); err != nil {
 		return false, err
 	}
 
-	if !runRoot {
-		return false, nil
+	return runRoot, nil
+}
+
+// loadConfig layers the defaults, the config file, the environment and the
+// changed flags, each overriding the ones before. The config file may only
+// be missing if it was not named explicitly by the flag or the environment.
+func loadConfig(file string, explicit bool, lookupEnv func(string) (string, bool), flags *pflag.FlagSet) (S3ToolCliConfig, error) {
+	if !explicit {
+		if envFile, ok := lookupEnv("S3TOOL_CONFIG"); ok && envFile != "" {
+			file, explicit = envFile, true
+		}
+	}
+
+	cfg := *DefaultConfig()
+	path := expandHome(file)
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&cfg); err != nil && err != io.EOF {
+			return cfg, fmt.Errorf("reading config %s: %w", path, err)
+		}
+		cfg.File = path
+	case !explicit && errors.Is(err, fs.ErrNotExist):
+		// the default config file is optional
+	default:
+		return cfg, err
 	}
 
-	cfg = cleanup(cfg)
-	Config = &cfg
+	for _, s := range settings {
+		if value, ok := lookupEnv(envName(s.flag)); ok {
+			if err := s.set(&cfg, value); err != nil {
+				return cfg, fmt.Errorf("%s: %w", envName(s.flag), err)
+			}
+		}
+	}
 
-	return true, nil
+	var flagErr error
+	flags.Visit(func(f *pflag.Flag) {
+		for _, s := range settings {
+			if s.flag == f.Name && flagErr == nil {
+				flagErr = s.set(&cfg, f.Value.String())
+			}
+		}
+	})
+	return cfg, flagErr
 }
 
 func cleanup(cfg S3ToolCliConfig) S3ToolCliConfig {
 	result := cfg
-	if strings.HasPrefix(result.ProfilesDirectory, "~") {
+	result.ProfilesDirectory = expandHome(result.ProfilesDirectory)
+	result.Keymap = expandHome(result.Keymap)
+	result.Theme = expandHome(result.Theme)
+
+	return result
+}
+
+// expandHome replaces a leading "~" of path with the home directory.
+func expandHome(path string) string {
+	if strings.HasPrefix(path, "~") {
 		home, err := os.UserHomeDir()
 		if err == nil {
-			result.ProfilesDirectory = strings.TrimPrefix(result.ProfilesDirectory, "~")
-			result.ProfilesDirectory = home + result.ProfilesDirectory
+			return home + strings.TrimPrefix(path, "~")
 		}
 	}
-
-	return result
+	return path
 }
 
-func rootCmd(cfg *S3ToolCliConfig) *cobra.Command {
+func rootCmd(cfg *S3ToolCliConfig, configFile *string) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "s3tool",
 		Short: "s3tool is a terminal based S3 client",
+		// errors are reported by the caller
+		SilenceErrors: true,
 	}
 
-	flag := cmd.Flags()
-	flag.StringVarP(&cfg.ProfilesDirectory, "profiles", "p", Config.ProfilesDirectory, "Path to a directory containing profile yaml files")
-	flag.BoolVar(&cfg.Loaders.Aws, "loaders.aws", Config.Loaders.Aws, "Enable AWS loader")
-	flag.BoolVar(&cfg.Loaders.S3Tool, "loaders.s3tool", Config.Loaders.S3Tool, "Enable S3Tool loader")
-	flag.BoolVar(&cfg.Loaders.Memory, "loaders.memory", Config.Loaders.Memory, "Enable Memory loader (for testing purposes)")
+	defaults := DefaultConfig()
+	flag := cmd.PersistentFlags()
+	flag.StringVar(configFile, "config", DefaultConfigFile, "Path to the config file")
+	flag.StringVarP(&cfg.ProfilesDirectory, "profiles", "p", defaults.ProfilesDirectory, "Path to a directory containing profile yaml files")
+	flag.BoolVar(&cfg.Loaders.Aws, "loaders.aws", defaults.Loaders.Aws, "Enable AWS loader")
+	flag.BoolVar(&cfg.Loaders.S3Tool, "loaders.s3tool", defaults.Loaders.S3Tool, "Enable S3Tool loader")
+	flag.BoolVar(&cfg.Loaders.Memory, "loaders.memory", defaults.Loaders.Memory, "Enable Memory loader (for testing purposes)")
 	_ = flag.MarkHidden("loaders.memory")
+	flag.StringVar(&cfg.Editor, "editor", defaults.Editor, "Command that edits objects")
+	flag.StringVar(&cfg.Viewer, "viewer", defaults.Viewer, "Command that shows objects")
+	flag.StringVar(&cfg.Theme, "theme", defaults.Theme, "Built-in theme (dark, light, high-contrast, solarized) or path to a yaml theme")
+	flag.StringVar(&cfg.Keymap, "keymap", defaults.Keymap, "Path to a yaml file with the key bindings of the terminal UI")
+	flag.IntVar(&cfg.Transfers.Concurrency, "transfers.concurrency", defaults.Transfers.Concurrency, "Number of transfers of the terminal UI running at the same time")
+	flag.String("transfers.part-size", defaults.Transfers.PartSize.String(), "Size of the parts of multipart transfers, like 16MiB")
+	flag.IntVar(&cfg.Transfers.PartConcurrency, "transfers.part-concurrency", defaults.Transfers.PartConcurrency, "Number of parts transferred in parallel")
+
+	return cmd
+}
 
+func configCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect the configuration",
+	}
+	cmd.AddCommand(&cobra.Command{
+		Use:   "show",
+		Short: "Print the effective configuration",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
+			if Config.File != "" {
+				fmt.Fprintf(out, "# config file: %s\n", Config.File)
+			} else {
+				fmt.Fprintln(out, "# no config file")
+			}
+			encoder := yaml.NewEncoder(out)
+			encoder.SetIndent(2)
+			if err := encoder.Encode(Config); err != nil {
+				return err
+			}
+			return encoder.Close()
+		},
+	})
 	return cmd
 }
 
@@ -1,9 +1,13 @@
 package cli
 
 import (
+	"bytes"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/spf13/pflag"
 )
 
 func TestDefaultConfig(t *testing.T) {
@@ -39,14 +43,15 @@ func TestCleanupKeepsAbsolutePath(t *testing.T) {
 
 func TestRootCmdFlags(t *testing.T) {
 	cfg := S3ToolCliConfig{}
-	cmd := rootCmd(&cfg)
+	var configFile string
+	cmd := rootCmd(&cfg, &configFile)
 
-	profilesFlag := cmd.Flags().Lookup("profiles")
+	profilesFlag := cmd.PersistentFlags().Lookup("profiles")
 	if profilesFlag == nil {
 		t.Fatal("profiles flag not registered")
 	}
 
-	memoryFlag := cmd.Flags().Lookup("loaders.memory")
+	memoryFlag := cmd.PersistentFlags().Lookup("loaders.memory")
 	if memoryFlag == nil {
 		t.Fatal("loaders.memory flag not registered")
 	}
@@ -107,3 +112,127 @@ func TestParseCompletionReturnsNoRun(t *testing.T) {
 		t.Fatal("expected runApp false for completion subcommand")
 	}
 }
+
+func TestCleanupExpandsKeymap(t *testing.T) {
+	cfg := cleanup(S3ToolCliConfig{Keymap: "~/.config/s3tool/keymap.yaml"})
+	if strings.HasPrefix(cfg.Keymap, "~") || !strings.HasSuffix(cfg.Keymap, "/.config/s3tool/keymap.yaml") {
+		t.Fatalf("expected expanded keymap path, got %q", cfg.Keymap)
+	}
+}
+
+func TestLoadConfigPrecedence(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(file, []byte(`profiles_directory: /file/profiles
+editor: vim
+theme: light
+loaders:
+  aws: false
+transfers:
+  part_size: 16MiB
+  concurrency: 3
+`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env := map[string]string{
+		"S3TOOL_THEME":                 "solarized",
+		"S3TOOL_TRANSFERS_CONCURRENCY": "5",
+	}
+	lookupEnv := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+
+	var cfg S3ToolCliConfig
+	var configFile string
+	flags := rootCmd(&cfg, &configFile).PersistentFlags()
+	if err := flags.Parse([]string{"--transfers.concurrency", "7", "--keymap", "/flag/keymap.yaml"}); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := loadConfig(file, true, lookupEnv, flags)
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+
+	if loaded.File != file || loaded.ProfilesDirectory != "/file/profiles" || loaded.Editor != "vim" || loaded.Loaders.Aws {
+		t.Fatalf("expected the values of the file, got %#v", loaded)
+	}
+	if !loaded.Loaders.S3Tool || loaded.Transfers.PartConcurrency != 4 {
+		t.Fatalf("expected defaults for values missing in the file, got %#v", loaded)
+	}
+	if loaded.Transfers.PartSize != 16<<20 {
+		t.Fatalf("expected part size 16MiB, got %d", loaded.Transfers.PartSize)
+	}
+	if loaded.Theme != "solarized" {
+		t.Fatalf("expected env to override the file, got theme %q", loaded.Theme)
+	}
+	if loaded.Transfers.Concurrency != 7 || loaded.Keymap != "/flag/keymap.yaml" {
+		t.Fatalf("expected flags to override env and file, got %#v", loaded)
+	}
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	noEnv := func(string) (string, bool) { return "", false }
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	missing := filepath.Join(t.TempDir(), "missing.yaml")
+
+	cfg, err := loadConfig(missing, false, noEnv, flags)
+	if err != nil || cfg.File != "" || cfg.Theme != "dark" {
+		t.Fatalf("expected defaults without the default config file, got %#v, %v", cfg, err)
+	}
+
+	if _, err := loadConfig(missing, true, noEnv, flags); err == nil {
+		t.Fatal("expected error for missing explicit config file")
+	}
+
+	env := func(name string) (string, bool) { return missing, name == "S3TOOL_CONFIG" }
+	if _, err := loadConfig(DefaultConfigFile, false, env, flags); err == nil {
+		t.Fatal("expected error for missing config file of S3TOOL_CONFIG")
+	}
+
+	invalid := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(invalid, []byte("themes: light\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadConfig(invalid, true, noEnv, flags); err == nil {
+		t.Fatal("expected error for unknown key")
+	}
+}
+
+func TestByteSize(t *testing.T) {
+	for text, expected := range map[string]ByteSize{"1024": 1024, "8MiB": 8 << 20, "2G": 2 << 30, "5 KiB": 5 << 10} {
+		size, err := ParseByteSize(text)
+		if err != nil || size != expected {
+			t.Fatalf("expected %d for %q, got %d, %v", expected, text, size, err)
+		}
+	}
+	if _, err := ParseByteSize("lots"); err == nil {
+		t.Fatal("expected error for invalid size")
+	}
+	if s := ByteSize(16 << 20).String(); s != "16MiB" {
+		t.Fatalf("expected 16MiB, got %q", s)
+	}
+}
+
+func TestConfigShow(t *testing.T) {
+	original := Config
+	t.Cleanup(func() {
+		Config = original
+	})
+
+	var out bytes.Buffer
+	cmd := configCmd()
+	cmd.SetOut(&out)
+	cmd.SetArgs([]string{"show"})
+	Config = DefaultConfig()
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("config show failed: %v", err)
+	}
+	for _, expected := range []string{"# no config file", "theme: dark", "part_size: 8MiB", "  aws: true"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Fatalf("expected %q in output:\n%s", expected, out.String())
+		}
+	}
+}
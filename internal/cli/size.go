@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ByteSize is a number of bytes, written like 8388608, "8MiB" or "8M".
+type ByteSize int64
+
+var byteSizeUnits = []struct {
+	suffix string
+	factor int64
+}{
+	{"GiB", 1 << 30},
+	{"MiB", 1 << 20},
+	{"KiB", 1 << 10},
+	{"G", 1 << 30},
+	{"M", 1 << 20},
+	{"K", 1 << 10},
+	{"B", 1},
+}
+
+// ParseByteSize parses a size with an optional binary unit.
+func ParseByteSize(s string) (ByteSize, error) {
+	value := strings.TrimSpace(s)
+	factor := int64(1)
+	for _, unit := range byteSizeUnits {
+		if number, ok := strings.CutSuffix(value, unit.suffix); ok {
+			value, factor = strings.TrimSpace(number), unit.factor
+			break
+		}
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return ByteSize(n * factor), nil
+}
+
+// String returns the size with the largest unit that divides it.
+func (s ByteSize) String() string {
+	for _, unit := range byteSizeUnits[:3] {
+		if s != 0 && int64(s)%unit.factor == 0 {
+			return strconv.FormatInt(int64(s)/unit.factor, 10) + unit.suffix
+		}
+	}
+	return strconv.FormatInt(int64(s), 10)
+}
+
+func (s *ByteSize) UnmarshalYAML(node *yaml.Node) error {
+	size, err := ParseByteSize(node.Value)
+	if err != nil {
+		return err
+	}
+	*s = size
+	return nil
+}
+
+func (s ByteSize) MarshalYAML() (any, error) {
+	return s.String(), nil
+}
@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/spf13/cobra"
+)
+
+func mbCmd(env *environment) *cobra.Command {
+	var asJSON bool
+	var region string
+	cmd := &cobra.Command{
+		Use:   "mb <profile:bucket>",
+		Short: "Make a bucket",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			location := env.location(args[0])
+			if err := location.requireBucketOnly(); err != nil {
+				return err
+			}
+
+			client, err := env.client(cmd.Context(), location.Profile)
+			if err != nil {
+				return err
+			}
+			if region == "" {
+				region = aws.ToString(client.ConnectionParameters(location.Bucket).Region)
+			}
+			if err := client.CreateBucket(cmd.Context(), location.Bucket, region); err != nil {
+				return err
+			}
+
+			return printResult(cmd, asJSON, resultEntry{Operation: "make bucket", Destination: location.String()})
+		},
+	}
+	addJSONFlag(cmd, &asJSON)
+	cmd.Flags().StringVar(&region, "region", "", "Region of the bucket (default: region of the profile)")
+	return cmd
+}
+
+func rbCmd(env *environment) *cobra.Command {
+	var asJSON bool
+	cmd := &cobra.Command{
+		Use:   "rb <profile:bucket>",
+		Short: "Remove an empty bucket",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			location := env.location(args[0])
+			if err := location.requireBucketOnly(); err != nil {
+				return err
+			}
+
+			client, err := env.client(cmd.Context(), location.Profile)
+			if err != nil {
+				return err
+			}
+			if err := client.DeleteBucket(cmd.Context(), location.Bucket); err != nil {
+				return err
+			}
+
+			return printResult(cmd, asJSON, resultEntry{Operation: "remove bucket", Destination: location.String()})
+		},
+	}
+	addJSONFlag(cmd, &asJSON)
+	return cmd
+}
@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+func catCmd(env *environment) *cobra.Command {
+	return &cobra.Command{
+		Use:   "cat <profile:bucket/key>...",
+		Short: "Print the content of objects",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			for _, arg := range args {
+				if err := catObject(cmd.Context(), env, env.location(arg), cmd.OutOrStdout()); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func catObject(ctx context.Context, env *environment, location Location, w io.Writer) error {
+	if err := location.requireObject(); err != nil {
+		return err
+	}
+
+	client, err := env.client(ctx, location.Profile)
+	if err != nil {
+		return err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "s3tool")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.RemoveAll(tmpDir)
+	}()
+
+	tmpFilePath := filepath.Join(tmpDir, "object")
+	if err := client.DownloadFile(ctx, location.Bucket, location.Key, tmpFilePath, env.transfer()...); err != nil {
+		return err
+	}
+
+	file, err := os.Open(tmpFilePath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	_, err = io.Copy(w, file)
+	return err
+}
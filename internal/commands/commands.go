@@ -0,0 +1,105 @@
+// Package commands implements the non-interactive subcommands of s3tool.
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/schidstorm/s3tool/internal/s3lib"
+	"github.com/spf13/cobra"
+)
+
+// Commands returns the subcommands working on the profiles of loaders. loaders
+// and transferOptions, which returns the options of uploads, downloads and
+// copies, are called when a command runs, after the command line has been
+// parsed. transferOptions may be nil.
+func Commands(loaders func() []s3lib.ConnectorLoader, transferOptions func() []func(*s3lib.TransferOptions)) []*cobra.Command {
+	env := &environment{loaders: loaders, transferOptions: transferOptions}
+	return []*cobra.Command{
+		lsCmd(env),
+		cpCmd(env),
+		syncCmd(env),
+		rmCmd(env),
+		catCmd(env),
+		statCmd(env),
+		presignCmd(env),
+		mbCmd(env),
+		rbCmd(env),
+	}
+}
+
+// environment resolves profile names to clients. Clients are created once per
+// profile and reused by all operations of a command.
+type environment struct {
+	loaders         func() []s3lib.ConnectorLoader
+	transferOptions func() []func(*s3lib.TransferOptions)
+	clients         map[string]s3lib.Client
+}
+
+func (e *environment) connectors() ([]s3lib.Connector, error) {
+	return s3lib.LoadConnectors(e.loaders())
+}
+
+// transfer returns the options of transfers.
+func (e *environment) transfer() []func(*s3lib.TransferOptions) {
+	if e.transferOptions == nil {
+		return nil
+	}
+	return e.transferOptions()
+}
+
+// location parses s like ParseLocation, but as a local path if the profile it
+// starts with does not exist, so local paths like notes:2024.txt need no ./
+// in front.
+func (e *environment) location(s string) Location {
+	location := ParseLocation(s)
+	if !location.IsRemote() {
+		return location
+	}
+
+	connectors, err := e.connectors()
+	if err != nil {
+		// the error shows when the client of the profile is created
+		return location
+	}
+	for _, connector := range connectors {
+		if connector.Name() == location.Profile {
+			return location
+		}
+	}
+	return Location{Path: s}
+}
+
+func (e *environment) client(ctx context.Context, profile string) (s3lib.Client, error) {
+	if client, ok := e.clients[profile]; ok {
+		return client, nil
+	}
+
+	connectors, err := e.connectors()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, connector := range connectors {
+		if connector.Name() != profile {
+			continue
+		}
+
+		client, err := connector.CreateClient(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if e.clients == nil {
+			e.clients = map[string]s3lib.Client{}
+		}
+		e.clients[profile] = client
+		return client, nil
+	}
+
+	return nil, fmt.Errorf("profile %q not found", profile)
+}
+
+// addJSONFlag registers the --json flag of cmd.
+func addJSONFlag(cmd *cobra.Command, asJSON *bool) {
+	cmd.Flags().BoolVar(asJSON, "json", false, "Print the output as JSON")
+}
@@ -0,0 +1,331 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/schidstorm/s3tool/internal/s3lib"
+	"github.com/spf13/cobra"
+)
+
+type testLoader struct {
+	connectors []s3lib.Connector
+}
+
+func (l testLoader) Load() ([]s3lib.Connector, error) {
+	return l.connectors, nil
+}
+
+type testConnector struct {
+	name   string
+	client s3lib.Client
+}
+
+func (c testConnector) Name() string { return c.name }
+func (c testConnector) Type() string { return "memory" }
+func (c testConnector) CreateClient(context.Context) (s3lib.Client, error) {
+	return c.client, nil
+}
+
+func testClient() *s3lib.MemoryClient {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	return s3lib.NewMemoryClientFactory().
+		WithBucket("bucket", "eu-west-1", now).
+		WithObject("bucket", "hello.txt", 5, now, "etag", "STANDARD", []byte("hello")).
+		Build()
+}
+
+// run executes the subcommand given by args against the profiles and returns
+// its output.
+func run(t *testing.T, profiles map[string]s3lib.Client, args ...string) (string, error) {
+	t.Helper()
+
+	var loader testLoader
+	for name, client := range profiles {
+		loader.connectors = append(loader.connectors, testConnector{name: name, client: client})
+	}
+
+	root := &cobra.Command{Use: "s3tool", SilenceErrors: true}
+	root.AddCommand(Commands(func() []s3lib.ConnectorLoader {
+		return []s3lib.ConnectorLoader{loader}
+	}, nil)...)
+
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs(args)
+	err := root.Execute()
+	return out.String(), err
+}
+
+func TestLsProfilesAndBuckets(t *testing.T) {
+	profiles := map[string]s3lib.Client{"prod": testClient()}
+
+	out, err := run(t, profiles, "ls", "--json")
+	if err != nil {
+		t.Fatalf("ls failed: %v", err)
+	}
+	if strings.TrimSpace(out) != `[{"name":"prod","type":"memory"}]` {
+		t.Fatalf("unexpected profiles output: %s", out)
+	}
+
+	out, err = run(t, profiles, "ls", "prod:", "--json")
+	if err != nil {
+		t.Fatalf("ls buckets failed: %v", err)
+	}
+	var buckets []bucketEntry
+	if err := json.Unmarshal([]byte(out), &buckets); err != nil {
+		t.Fatalf("invalid json %q: %v", out, err)
+	}
+	if len(buckets) != 1 || buckets[0].Name != "bucket" || buckets[0].Region != "eu-west-1" {
+		t.Fatalf("unexpected buckets: %#v", buckets)
+	}
+}
+
+func TestLsObjects(t *testing.T) {
+	profiles := map[string]s3lib.Client{"prod": testClient()}
+
+	out, err := run(t, profiles, "ls", "prod:bucket")
+	if err != nil {
+		t.Fatalf("ls failed: %v", err)
+	}
+	if strings.TrimSpace(out) != "2025-01-02T03:04:05Z  5  hello.txt" {
+		t.Fatalf("unexpected output: %q", out)
+	}
+
+	out, err = run(t, profiles, "ls", "prod:bucket", "--json")
+	if err != nil {
+		t.Fatalf("ls failed: %v", err)
+	}
+	var objects []objectEntry
+	if err := json.Unmarshal([]byte(out), &objects); err != nil {
+		t.Fatalf("invalid json %q: %v", out, err)
+	}
+	if len(objects) != 1 || objects[0].Key != "hello.txt" || objects[0].Type != "file" || *objects[0].Size != 5 {
+		t.Fatalf("unexpected objects: %#v", objects)
+	}
+
+	if _, err := run(t, profiles, "ls", "missing:bucket"); err == nil || !strings.Contains(err.Error(), `profile "missing" not found`) {
+		t.Fatalf("expected unknown profile error, got %v", err)
+	}
+	if _, err := run(t, profiles, "ls", "local/path"); err == nil {
+		t.Fatal("expected error for local path")
+	}
+}
+
+func TestCpRoundTrip(t *testing.T) {
+	prod, backup := testClient(), testClient()
+	profiles := map[string]s3lib.Client{"prod": prod, "backup": backup}
+	dir := t.TempDir()
+
+	srcPath := filepath.Join(dir, "local.txt")
+	if err := os.WriteFile(srcPath, []byte("local content"), 0o600); err != nil {
+		t.Fatalf("write file failed: %v", err)
+	}
+
+	out, err := run(t, profiles, "cp", srcPath, "prod:bucket/dir/", "--json")
+	if err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+	var result resultEntry
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid json %q: %v", out, err)
+	}
+	if result.Destination != "prod:bucket/dir/local.txt" {
+		t.Fatalf("unexpected destination %q", result.Destination)
+	}
+
+	if _, err := run(t, profiles, "cp", "prod:bucket/dir/local.txt", "backup:bucket/copy.txt"); err != nil {
+		t.Fatalf("remote copy failed: %v", err)
+	}
+
+	if _, err := run(t, profiles, "cp", "backup:bucket/copy.txt", "backup:bucket/same.txt"); err != nil {
+		t.Fatalf("server side copy failed: %v", err)
+	}
+	if _, err := run(t, profiles, "stat", "backup:bucket/same.txt"); err != nil {
+		t.Fatalf("stat of copied object failed: %v", err)
+	}
+
+	if _, err := run(t, profiles, "cp", "backup:bucket/copy.txt", dir); err != nil {
+		t.Fatalf("download failed: %v", err)
+	}
+	got, err := os.ReadFile(filepath.Join(dir, "copy.txt"))
+	if err != nil {
+		t.Fatalf("read downloaded file failed: %v", err)
+	}
+	if string(got) != "local content" {
+		t.Fatalf("unexpected content %q", got)
+	}
+
+	if _, err := run(t, profiles, "cp", srcPath, filepath.Join(dir, "other.txt")); err == nil {
+		t.Fatal("expected error for local to local copy")
+	}
+}
+
+func TestCpLocalPathWithColon(t *testing.T) {
+	prod := testClient()
+	profiles := map[string]s3lib.Client{"prod": prod}
+	t.Chdir(t.TempDir())
+
+	if err := os.WriteFile("notes:2024.txt", []byte("notes"), 0o600); err != nil {
+		t.Fatalf("write file failed: %v", err)
+	}
+	if _, err := run(t, profiles, "cp", "notes:2024.txt", "prod:bucket/"); err != nil {
+		t.Fatalf("upload of local path with colon failed: %v", err)
+	}
+	if _, err := run(t, profiles, "cp", "./notes:2024.txt", "prod:bucket/copy.txt"); err != nil {
+		t.Fatalf("upload of ./ path failed: %v", err)
+	}
+	if out, err := run(t, profiles, "cat", "prod:bucket/notes:2024.txt"); err != nil || out != "notes" {
+		t.Fatalf("expected uploaded notes, got %q %v", out, err)
+	}
+
+	_, err := run(t, profiles, "cp", "prdo:bucket/hello.txt", "local.txt")
+	if err == nil || !strings.Contains(err.Error(), `profile "prdo" not found`) {
+		t.Fatalf("expected unknown profile error, got %v", err)
+	}
+}
+
+func TestCatStatAndRm(t *testing.T) {
+	profiles := map[string]s3lib.Client{"prod": testClient()}
+
+	out, err := run(t, profiles, "cat", "prod:bucket/hello.txt", "prod:bucket/hello.txt")
+	if err != nil {
+		t.Fatalf("cat failed: %v", err)
+	}
+	if out != "hellohello" {
+		t.Fatalf("unexpected cat output %q", out)
+	}
+
+	out, err = run(t, profiles, "stat", "prod:bucket/hello.txt", "--json")
+	if err != nil {
+		t.Fatalf("stat failed: %v", err)
+	}
+	var stat statEntry
+	if err := json.Unmarshal([]byte(out), &stat); err != nil {
+		t.Fatalf("invalid json %q: %v", out, err)
+	}
+	if stat.Bucket != "bucket" || stat.Key != "hello.txt" || *stat.Size != 5 {
+		t.Fatalf("unexpected stat: %#v", stat)
+	}
+
+	out, err = run(t, profiles, "rm", "prod:bucket/hello.txt")
+	if err != nil {
+		t.Fatalf("rm failed: %v", err)
+	}
+	if out != "delete: prod:bucket/hello.txt\n" {
+		t.Fatalf("unexpected rm output %q", out)
+	}
+	if _, err := run(t, profiles, "stat", "prod:bucket/hello.txt"); err == nil {
+		t.Fatal("expected stat of removed object to fail")
+	}
+}
+
+func TestMbAndRb(t *testing.T) {
+	profiles := map[string]s3lib.Client{"prod": testClient()}
+
+	if _, err := run(t, profiles, "mb", "prod:new-bucket", "--region", "us-east-2"); err != nil {
+		t.Fatalf("mb failed: %v", err)
+	}
+	out, err := run(t, profiles, "ls", "prod:", "--json")
+	if err != nil {
+		t.Fatalf("ls failed: %v", err)
+	}
+	if !strings.Contains(out, `"name":"new-bucket","region":"us-east-2"`) {
+		t.Fatalf("expected new bucket in %s", out)
+	}
+
+	if _, err := run(t, profiles, "rb", "prod:new-bucket"); err != nil {
+		t.Fatalf("rb failed: %v", err)
+	}
+	if _, err := run(t, profiles, "mb", "prod:bucket/key"); err == nil {
+		t.Fatal("expected error for object location")
+	}
+}
+
+func TestSync(t *testing.T) {
+	profiles := map[string]s3lib.Client{"prod": testClient()}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "local.txt"), []byte("local"), 0o600); err != nil {
+		t.Fatalf("write file failed: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "skip.log"), []byte("log"), 0o600); err != nil {
+		t.Fatalf("write file failed: %v", err)
+	}
+
+	out, err := run(t, profiles, "sync", dir, "prod:bucket", "--delete", "--exclude", "*.log", "--dry-run")
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	want := "(dry run) copy: " + dir + "/local.txt -> prod:bucket/local.txt\n" +
+		"(dry run) delete: prod:bucket/hello.txt\n" +
+		"1 new, 0 changed, 1 deleted, 0 unchanged, 5 bytes\n"
+	if out != want {
+		t.Fatalf("unexpected dry run output %q", out)
+	}
+
+	out, err = run(t, profiles, "sync", dir, "prod:bucket", "--delete", "--exclude", "*.log", "--json")
+	if err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+	var report syncReport
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("invalid json %q: %v", out, err)
+	}
+	if report.DryRun || report.New != 1 || report.Deleted != 1 {
+		t.Fatalf("unexpected report %#v", report)
+	}
+
+	out, err = run(t, profiles, "ls", "prod:bucket")
+	if err != nil {
+		t.Fatalf("ls failed: %v", err)
+	}
+	if !strings.HasSuffix(strings.TrimSpace(out), "local.txt") || strings.Contains(out, "hello.txt") {
+		t.Fatalf("unexpected objects after sync: %q", out)
+	}
+
+	download := filepath.Join(dir, "download")
+	if _, err := run(t, profiles, "sync", "prod:bucket", download); err != nil {
+		t.Fatalf("download sync failed: %v", err)
+	}
+	if got, err := os.ReadFile(filepath.Join(download, "local.txt")); err != nil || string(got) != "local" {
+		t.Fatalf("unexpected downloaded content %q, %v", got, err)
+	}
+}
+
+func TestPresign(t *testing.T) {
+	profiles := map[string]s3lib.Client{"prod": testClient()}
+
+	out, err := run(t, profiles, "presign", "prod:bucket/hello.txt", "--expires", "2h")
+	if err != nil {
+		t.Fatalf("presign failed: %v", err)
+	}
+	if out != "memory://bucket/hello.txt?Method=GET&X-Amz-Expires=7200\n" {
+		t.Fatalf("unexpected presign output %q", out)
+	}
+
+	out, err = run(t, profiles, "presign", "prod:bucket/new.txt", "--method", "put", "--json")
+	if err != nil {
+		t.Fatalf("presign put failed: %v", err)
+	}
+	var entry presignEntry
+	if err := json.Unmarshal([]byte(out), &entry); err != nil {
+		t.Fatalf("invalid json %q: %v", out, err)
+	}
+	if entry.Method != "PUT" || !strings.Contains(entry.URL, "new.txt") || entry.ExpiresAt.Before(time.Now()) {
+		t.Fatalf("unexpected presign entry: %#v", entry)
+	}
+
+	if _, err := run(t, profiles, "presign", "prod:bucket/hello.txt", "--expires", "200h"); err == nil {
+		t.Fatal("expected presign with expiry above 7 days to fail")
+	}
+	if _, err := run(t, profiles, "presign", "prod:bucket/"); err == nil {
+		t.Fatal("expected presign of a prefix to fail")
+	}
+}
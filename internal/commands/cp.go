@@ -0,0 +1,131 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/schidstorm/s3tool/internal/s3lib"
+	"github.com/spf13/cobra"
+)
+
+func cpCmd(env *environment) *cobra.Command {
+	var asJSON bool
+	cmd := &cobra.Command{
+		Use:   "cp <source> <destination>",
+		Short: "Copy objects between the local filesystem and S3 or between S3 locations",
+		Long: "Copy a local file to S3, an object to the local filesystem or an object to\n" +
+			"another S3 location. Remote locations are written as profile:bucket/key. A\n" +
+			"destination ending with a slash keeps the name of the source. Copies within\n" +
+			"a profile are done on the server side, copies between profiles are streamed\n" +
+			"through s3tool.",
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			src, dst := env.location(args[0]), env.location(args[1])
+
+			dst, err := copyLocation(cmd.Context(), env, src, dst)
+			if err != nil {
+				return err
+			}
+
+			return printResult(cmd, asJSON, resultEntry{
+				Operation:   "copy",
+				Source:      src.String(),
+				Destination: dst.String(),
+			})
+		},
+	}
+	addJSONFlag(cmd, &asJSON)
+	return cmd
+}
+
+// copyLocation copies src to dst and returns the location written to.
+func copyLocation(ctx context.Context, env *environment, src, dst Location) (Location, error) {
+	if !src.IsRemote() && !dst.IsRemote() {
+		for _, l := range []Location{src, dst} {
+			if ParseLocation(l.Path).IsRemote() {
+				return dst, l.expectedError("a remote location", "profile:bucket/key")
+			}
+		}
+		return dst, errors.New("at least one location must be remote, use profile:bucket/key")
+	}
+
+	if src.IsRemote() {
+		if err := src.requireObject(); err != nil {
+			return dst, err
+		}
+	} else if info, err := os.Stat(src.Path); err != nil {
+		return dst, err
+	} else if info.IsDir() {
+		return dst, fmt.Errorf("%q is a directory", src.Path)
+	}
+
+	dst, err := destination(src, dst)
+	if err != nil {
+		return dst, err
+	}
+
+	switch {
+	case !src.IsRemote():
+		client, err := env.client(ctx, dst.Profile)
+		if err != nil {
+			return dst, err
+		}
+		return dst, client.UploadFile(ctx, dst.Bucket, dst.Key, src.Path, env.transfer()...)
+	case !dst.IsRemote():
+		client, err := env.client(ctx, src.Profile)
+		if err != nil {
+			return dst, err
+		}
+		return dst, client.DownloadFile(ctx, src.Bucket, src.Key, dst.Path, env.transfer()...)
+	}
+
+	srcClient, err := env.client(ctx, src.Profile)
+	if err != nil {
+		return dst, err
+	}
+	dstClient, err := env.client(ctx, dst.Profile)
+	if err != nil {
+		return dst, err
+	}
+	return dst, s3lib.TransferObject(ctx, srcClient, src.Bucket, src.Key, dstClient, dst.Bucket, dst.Key, env.transfer()...)
+}
+
+// destination completes dst with the name of src if dst addresses a
+// directory.
+func destination(src, dst Location) (Location, error) {
+	if dst.IsRemote() {
+		if err := dst.requireBucket(); err != nil {
+			return dst, err
+		}
+		if dst.IsPrefix() {
+			dst.Key += src.baseName()
+		}
+		return dst, nil
+	}
+
+	if strings.HasSuffix(dst.Path, string(filepath.Separator)) || strings.HasSuffix(dst.Path, "/") {
+		dst.Path = filepath.Join(dst.Path, src.baseName())
+	} else if info, err := os.Stat(dst.Path); err == nil && info.IsDir() {
+		dst.Path = filepath.Join(dst.Path, src.baseName())
+	}
+	return dst, nil
+}
+
+// printResult prints the result of an operation modifying objects or buckets.
+func printResult(cmd *cobra.Command, asJSON bool, result resultEntry) error {
+	if asJSON {
+		return printJSON(cmd.OutOrStdout(), result)
+	}
+
+	if result.Source != "" {
+		_, err := fmt.Fprintf(cmd.OutOrStdout(), "%s: %s -> %s\n", result.Operation, result.Source, result.Destination)
+		return err
+	}
+	_, err := fmt.Fprintf(cmd.OutOrStdout(), "%s: %s\n", result.Operation, result.Destination)
+	return err
+}
@@ -0,0 +1,102 @@
+package commands
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Location addresses either a local path or an S3 object as
+// profile:bucket/key. An empty key addresses the bucket, an empty bucket the
+// buckets of the profile.
+type Location struct {
+	Profile string
+	Bucket  string
+	Key     string
+
+	// Path is the local path of locations without a profile.
+	Path string
+}
+
+// ParseLocation parses s as a remote location if it starts with a profile
+// name followed by a colon, and as a local path otherwise. Commands only treat
+// it as remote if the profile exists, see environment.location; ./ in front
+// of a local path containing a colon makes it local either way.
+func ParseLocation(s string) Location {
+	profile, rest, found := strings.Cut(s, ":")
+	if !found || profile == "" || strings.ContainsAny(profile, `/\`) {
+		return Location{Path: s}
+	}
+
+	bucket, key, _ := strings.Cut(rest, "/")
+	return Location{
+		Profile: profile,
+		Bucket:  bucket,
+		Key:     key,
+	}
+}
+
+func (l Location) IsRemote() bool {
+	return l.Profile != ""
+}
+
+// IsPrefix reports whether the key of l addresses a directory rather than a
+// single object.
+func (l Location) IsPrefix() bool {
+	return l.Key == "" || strings.HasSuffix(l.Key, "/")
+}
+
+// WithKey returns l addressing key in the same bucket.
+func (l Location) WithKey(key string) Location {
+	l.Key = key
+	return l
+}
+
+func (l Location) String() string {
+	if !l.IsRemote() {
+		return l.Path
+	}
+	if l.Key == "" {
+		return l.Profile + ":" + l.Bucket
+	}
+	return l.Profile + ":" + l.Bucket + "/" + l.Key
+}
+
+func (l Location) requireBucket() error {
+	if !l.IsRemote() || l.Bucket == "" {
+		return l.expectedError("a bucket", "profile:bucket")
+	}
+	return nil
+}
+
+func (l Location) requireBucketOnly() error {
+	if err := l.requireBucket(); err != nil || l.Key != "" {
+		return l.expectedError("a bucket", "profile:bucket")
+	}
+	return nil
+}
+
+func (l Location) requireObject() error {
+	if !l.IsRemote() || l.Bucket == "" || l.IsPrefix() {
+		return l.expectedError("an object", "profile:bucket/key")
+	}
+	return nil
+}
+
+// expectedError returns the error for l not being what, written as syntax.
+// Local paths that look like remote locations name their unknown profile.
+func (l Location) expectedError(what, syntax string) error {
+	if remote := ParseLocation(l.Path); !l.IsRemote() && remote.IsRemote() {
+		return fmt.Errorf("%q is not %s, profile %q not found", l, what, remote.Profile)
+	}
+	return fmt.Errorf("%q is not %s, expected %s", l, what, syntax)
+}
+
+// baseName returns the last element of the key or path of l.
+func (l Location) baseName() string {
+	if l.IsRemote() {
+		return path.Base(l.Key)
+	}
+	return filepath.Base(l.Path)
+}
@@ -0,0 +1,47 @@
+package commands
+
+import "testing"
+
+func TestParseLocation(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Location
+	}{
+		{"prod:bucket/dir/key.txt", Location{Profile: "prod", Bucket: "bucket", Key: "dir/key.txt"}},
+		{"prod:bucket", Location{Profile: "prod", Bucket: "bucket"}},
+		{"prod:bucket/", Location{Profile: "prod", Bucket: "bucket"}},
+		{"prod:", Location{Profile: "prod"}},
+		{"file.txt", Location{Path: "file.txt"}},
+		{"./dir:with/colon", Location{Path: "./dir:with/colon"}},
+		{":bucket/key", Location{Path: ":bucket/key"}},
+	}
+
+	for _, test := range tests {
+		if got := ParseLocation(test.input); got != test.expected {
+			t.Errorf("ParseLocation(%q) = %#v, expected %#v", test.input, got, test.expected)
+		}
+	}
+}
+
+func TestLocationString(t *testing.T) {
+	for _, s := range []string{"prod:bucket/dir/key.txt", "prod:bucket", "local/file.txt"} {
+		if got := ParseLocation(s).String(); got != s {
+			t.Errorf("expected %q, got %q", s, got)
+		}
+	}
+}
+
+func TestLocationRequirements(t *testing.T) {
+	if err := ParseLocation("prod:bucket/key").requireObject(); err != nil {
+		t.Fatalf("expected object location, got %v", err)
+	}
+	if err := ParseLocation("prod:bucket/dir/").requireObject(); err == nil {
+		t.Fatal("expected error for prefix location")
+	}
+	if err := ParseLocation("prod:bucket/key").requireBucketOnly(); err == nil {
+		t.Fatal("expected error for object location")
+	}
+	if err := ParseLocation("file.txt").requireBucket(); err == nil {
+		t.Fatal("expected error for local location")
+	}
+}
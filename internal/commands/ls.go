@@ -0,0 +1,121 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/schidstorm/s3tool/internal/s3lib"
+	"github.com/spf13/cobra"
+)
+
+func lsCmd(env *environment) *cobra.Command {
+	var asJSON bool
+	cmd := &cobra.Command{
+		Use:   "ls [profile:[bucket[/prefix]]]",
+		Short: "List profiles, buckets or objects",
+		Long: "List the profiles without an argument, the buckets of a profile for profile:\n" +
+			"and the objects and directories below a prefix for profile:bucket/prefix.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			if len(args) == 0 {
+				return listProfiles(cmd, env, asJSON)
+			}
+
+			location := env.location(args[0])
+			if !location.IsRemote() {
+				return location.expectedError("a remote location", "profile:[bucket[/prefix]]")
+			}
+
+			client, err := env.client(cmd.Context(), location.Profile)
+			if err != nil {
+				return err
+			}
+			if location.Bucket == "" {
+				return listBuckets(cmd, client, asJSON)
+			}
+			return listObjects(cmd, client, location, asJSON)
+		},
+	}
+	addJSONFlag(cmd, &asJSON)
+	return cmd
+}
+
+func listProfiles(cmd *cobra.Command, env *environment, asJSON bool) error {
+	connectors, err := env.connectors()
+	if err != nil {
+		return err
+	}
+
+	entries := make([]profileEntry, 0, len(connectors))
+	for _, connector := range connectors {
+		entries = append(entries, newProfileEntry(connector))
+	}
+	if asJSON {
+		return printJSON(cmd.OutOrStdout(), entries)
+	}
+
+	var rows [][]string
+	for _, entry := range entries {
+		rows = append(rows, []string{entry.Type, entry.Name})
+	}
+	return printTable(cmd.OutOrStdout(), rows)
+}
+
+func listBuckets(cmd *cobra.Command, client s3lib.Client, asJSON bool) error {
+	buckets, err := collect(cmd.Context(), client.ListBuckets(cmd.Context()))
+	if err != nil {
+		return err
+	}
+
+	entries := make([]bucketEntry, 0, len(buckets))
+	for _, bucket := range buckets {
+		entries = append(entries, newBucketEntry(bucket))
+	}
+	if asJSON {
+		return printJSON(cmd.OutOrStdout(), entries)
+	}
+
+	var rows [][]string
+	for _, entry := range entries {
+		rows = append(rows, []string{formatTime(entry.CreationDate), entry.Region, entry.Name})
+	}
+	return printTable(cmd.OutOrStdout(), rows)
+}
+
+func listObjects(cmd *cobra.Command, client s3lib.Client, location Location, asJSON bool) error {
+	objects, err := collect(cmd.Context(), client.ListObjects(cmd.Context(), location.Bucket, location.Key))
+	if err != nil {
+		return err
+	}
+
+	entries := make([]objectEntry, 0, len(objects))
+	for _, object := range objects {
+		entries = append(entries, newObjectEntry(object))
+	}
+	if asJSON {
+		return printJSON(cmd.OutOrStdout(), entries)
+	}
+
+	var rows [][]string
+	for _, entry := range entries {
+		if entry.Type == "directory" {
+			rows = append(rows, []string{"", "DIR", entry.Key})
+			continue
+		}
+		rows = append(rows, []string{formatTime(entry.LastModified), formatSize(entry.Size), entry.Key})
+	}
+	return printTable(cmd.OutOrStdout(), rows)
+}
+
+// collect reads all pages of p.
+func collect[T any](ctx context.Context, p s3lib.Paginator[T]) ([]T, error) {
+	var items []T
+	for p.HasMorePages() {
+		page, err := p.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page...)
+	}
+	return items, nil
+}
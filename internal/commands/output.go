@@ -0,0 +1,130 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/schidstorm/s3tool/internal/s3lib"
+)
+
+type profileEntry struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+type bucketEntry struct {
+	Name         string     `json:"name"`
+	Region       string     `json:"region,omitempty"`
+	CreationDate *time.Time `json:"creationDate,omitempty"`
+}
+
+type objectEntry struct {
+	Key          string     `json:"key"`
+	Type         string     `json:"type"`
+	Size         *int64     `json:"size,omitempty"`
+	LastModified *time.Time `json:"lastModified,omitempty"`
+	ETag         string     `json:"etag,omitempty"`
+	StorageClass string     `json:"storageClass,omitempty"`
+}
+
+type statEntry struct {
+	Bucket       string            `json:"bucket"`
+	Key          string            `json:"key"`
+	Region       string            `json:"region,omitempty"`
+	Size         *int64            `json:"size,omitempty"`
+	LastModified *time.Time        `json:"lastModified,omitempty"`
+	ContentType  string            `json:"contentType,omitempty"`
+	ETag         string            `json:"etag,omitempty"`
+	Owner        string            `json:"owner,omitempty"`
+	LegalHold    string            `json:"legalHold,omitempty"`
+	Metadata     map[string]string `json:"metadata,omitempty"`
+	Tags         map[string]string `json:"tags,omitempty"`
+}
+
+// resultEntry describes an operation that modified an object or bucket.
+type resultEntry struct {
+	Operation   string `json:"operation"`
+	Source      string `json:"source,omitempty"`
+	Destination string `json:"destination"`
+}
+
+func newProfileEntry(connector s3lib.Connector) profileEntry {
+	return profileEntry{Name: connector.Name(), Type: connector.Type()}
+}
+
+func newBucketEntry(bucket types.Bucket) bucketEntry {
+	return bucketEntry{
+		Name:         aws.ToString(bucket.Name),
+		Region:       aws.ToString(bucket.BucketRegion),
+		CreationDate: bucket.CreationDate,
+	}
+}
+
+func newObjectEntry(object s3lib.Object) objectEntry {
+	if object.IsDirectory() {
+		return objectEntry{Key: aws.ToString(object.Object.Key), Type: "directory"}
+	}
+	return objectEntry{
+		Key:          aws.ToString(object.Object.Key),
+		Type:         "file",
+		Size:         object.Object.Size,
+		LastModified: object.Object.LastModified,
+		ETag:         aws.ToString(object.Object.ETag),
+		StorageClass: string(object.Object.StorageClass),
+	}
+}
+
+func newStatEntry(metadata s3lib.ObjectMetadata) statEntry {
+	return statEntry{
+		Bucket:       metadata.Bucket,
+		Key:          metadata.Key,
+		Region:       metadata.Region,
+		Size:         metadata.Size,
+		LastModified: metadata.LastModified,
+		ContentType:  aws.ToString(metadata.Type),
+		ETag:         aws.ToString(metadata.ETag),
+		Owner:        aws.ToString(metadata.Owner),
+		LegalHold:    metadata.LegalHold,
+		Metadata:     metadata.Metadata,
+		Tags:         metadata.Tags,
+	}
+}
+
+func printJSON(w io.Writer, v any) error {
+	return json.NewEncoder(w).Encode(v)
+}
+
+// printTable prints rows as tab aligned columns.
+func printTable(w io.Writer, rows [][]string) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, row := range rows {
+		for i, column := range row {
+			if i > 0 {
+				_, _ = fmt.Fprint(tw, "\t")
+			}
+			_, _ = fmt.Fprint(tw, column)
+		}
+		_, _ = fmt.Fprintln(tw)
+	}
+	return tw.Flush()
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func formatSize(size *int64) string {
+	if size == nil {
+		return ""
+	}
+	return strconv.FormatInt(*size, 10)
+}
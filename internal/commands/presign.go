@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/schidstorm/s3tool/internal/s3lib"
+	"github.com/spf13/cobra"
+)
+
+type presignEntry struct {
+	URL       string    `json:"url"`
+	Method    string    `json:"method"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+func presignCmd(env *environment) *cobra.Command {
+	var (
+		method  string
+		expires time.Duration
+		asJSON  bool
+	)
+	cmd := &cobra.Command{
+		Use:   "presign <profile:bucket/key>",
+		Short: "Print a presigned URL of an object",
+		Long: "Print a URL that allows anyone to download the object, or to upload it\n" +
+			"with --method PUT, without credentials until the URL expires.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			location := env.location(args[0])
+			if err := location.requireObject(); err != nil {
+				return err
+			}
+			presignMethod, err := s3lib.ParsePresignMethod(method)
+			if err != nil {
+				return err
+			}
+
+			client, err := env.client(cmd.Context(), location.Profile)
+			if err != nil {
+				return err
+			}
+			url, err := client.PresignObject(cmd.Context(), location.Bucket, location.Key, "", presignMethod, expires)
+			if err != nil {
+				return err
+			}
+
+			if asJSON {
+				return printJSON(cmd.OutOrStdout(), presignEntry{
+					URL:       url,
+					Method:    string(presignMethod),
+					ExpiresAt: time.Now().Add(expires).UTC().Truncate(time.Second),
+				})
+			}
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), url)
+			return err
+		},
+	}
+	cmd.Flags().StringVar(&method, "method", string(s3lib.PresignGet), "Request the URL allows, GET or PUT")
+	cmd.Flags().DurationVar(&expires, "expires", s3lib.DefaultPresignExpiry, "Validity of the URL, at most 168h")
+	addJSONFlag(cmd, &asJSON)
+	return cmd
+}
@@ -0,0 +1,39 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func rmCmd(env *environment) *cobra.Command {
+	var asJSON bool
+	cmd := &cobra.Command{
+		Use:   "rm <profile:bucket/key>...",
+		Short: "Remove objects",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			for _, arg := range args {
+				location := env.location(arg)
+				if err := location.requireObject(); err != nil {
+					return err
+				}
+
+				client, err := env.client(cmd.Context(), location.Profile)
+				if err != nil {
+					return err
+				}
+				if err := client.DeleteObject(cmd.Context(), location.Bucket, location.Key); err != nil {
+					return err
+				}
+
+				err = printResult(cmd, asJSON, resultEntry{Operation: "delete", Destination: location.String()})
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+	addJSONFlag(cmd, &asJSON)
+	return cmd
+}
@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/spf13/cobra"
+)
+
+func statCmd(env *environment) *cobra.Command {
+	var asJSON bool
+	cmd := &cobra.Command{
+		Use:   "stat <profile:bucket/key>",
+		Short: "Show the metadata of an object",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			location := env.location(args[0])
+			if err := location.requireObject(); err != nil {
+				return err
+			}
+
+			client, err := env.client(cmd.Context(), location.Profile)
+			if err != nil {
+				return err
+			}
+			metadata, err := client.GetObject(cmd.Context(), location.Bucket, location.Key)
+			if err != nil {
+				return err
+			}
+
+			entry := newStatEntry(metadata)
+			if asJSON {
+				return printJSON(cmd.OutOrStdout(), entry)
+			}
+			return printTable(cmd.OutOrStdout(), statRows(entry))
+		},
+	}
+	addJSONFlag(cmd, &asJSON)
+	return cmd
+}
+
+func statRows(entry statEntry) [][]string {
+	var rows [][]string
+	add := func(title, value string) {
+		if value != "" {
+			rows = append(rows, []string{title + ":", value})
+		}
+	}
+	add("Bucket", entry.Bucket)
+	add("Key", entry.Key)
+	add("Region", entry.Region)
+	add("Size", formatSize(entry.Size))
+	add("LastModified", formatTime(entry.LastModified))
+	add("ContentType", entry.ContentType)
+	add("ETag", entry.ETag)
+	add("Owner", entry.Owner)
+	add("LegalHold", entry.LegalHold)
+	for _, k := range slices.Sorted(maps.Keys(entry.Metadata)) {
+		add("Metadata "+k, entry.Metadata[k])
+	}
+	for _, k := range slices.Sorted(maps.Keys(entry.Tags)) {
+		add("Tag "+k, entry.Tags[k])
+	}
+	return rows
+}
@@ -0,0 +1,134 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/schidstorm/s3tool/internal/s3lib"
+	"github.com/spf13/cobra"
+)
+
+func syncCmd(env *environment) *cobra.Command {
+	var asJSON bool
+	var dryRun bool
+	var opts s3lib.SyncOptions
+	cmd := &cobra.Command{
+		Use:   "sync <source> <destination>",
+		Short: "Mirror a local directory or S3 prefix to another location",
+		Long: "Copy new and changed files from the source to the destination, which may\n" +
+			"be local directories or S3 prefixes written as profile:bucket/prefix. Files\n" +
+			"differ if their size, ETag or, if no ETag can be compared, modification time\n" +
+			"differs. Globs without a slash match the file name, others the path relative\n" +
+			"to the synced directory.",
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+			src, err := syncLocation(cmd.Context(), env, env.location(args[0]))
+			if err != nil {
+				return err
+			}
+			dst, err := syncLocation(cmd.Context(), env, env.location(args[1]))
+			if err != nil {
+				return err
+			}
+
+			plan, err := s3lib.PlanSync(cmd.Context(), src, dst, opts)
+			if err != nil {
+				return err
+			}
+			if !dryRun {
+				if err := plan.Run(cmd.Context(), nil, env.transfer()...); err != nil {
+					return err
+				}
+			}
+
+			return printSyncReport(cmd, asJSON, newSyncReport(plan, args[0], args[1], dryRun))
+		},
+	}
+	cmd.Flags().BoolVar(&opts.Delete, "delete", false, "Delete files in the destination that do not exist in the source")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the changes without performing them")
+	cmd.Flags().StringArrayVar(&opts.Include, "include", nil, "Only sync files matching the glob, may be repeated")
+	cmd.Flags().StringArrayVar(&opts.Exclude, "exclude", nil, "Skip files matching the glob, may be repeated")
+	addJSONFlag(cmd, &asJSON)
+	return cmd
+}
+
+// syncLocation resolves l to a directory or a prefix. Keys not ending with a
+// slash are treated as prefixes of a directory.
+func syncLocation(ctx context.Context, env *environment, l Location) (s3lib.SyncLocation, error) {
+	if !l.IsRemote() {
+		return s3lib.LocalSyncLocation(l.Path), nil
+	}
+	if err := l.requireBucket(); err != nil {
+		return s3lib.SyncLocation{}, err
+	}
+
+	client, err := env.client(ctx, l.Profile)
+	if err != nil {
+		return s3lib.SyncLocation{}, err
+	}
+	prefix := l.Key
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return s3lib.RemoteSyncLocation(client, l.Bucket, prefix), nil
+}
+
+// syncReport describes the changes made, or planned with --dry-run, by sync.
+type syncReport struct {
+	DryRun    bool          `json:"dryRun"`
+	Actions   []resultEntry `json:"actions"`
+	New       int           `json:"new"`
+	Changed   int           `json:"changed"`
+	Deleted   int           `json:"deleted"`
+	Unchanged int           `json:"unchanged"`
+	Bytes     int64         `json:"bytes"`
+}
+
+func newSyncReport(plan s3lib.SyncPlan, src, dst string, dryRun bool) syncReport {
+	report := syncReport{
+		DryRun:    dryRun,
+		Actions:   []resultEntry{},
+		New:       plan.Count(s3lib.SyncCopy),
+		Changed:   plan.Count(s3lib.SyncUpdate),
+		Deleted:   plan.Count(s3lib.SyncDelete),
+		Unchanged: plan.Unchanged,
+		Bytes:     plan.TransferSize(),
+	}
+
+	for _, action := range plan.Actions {
+		entry := resultEntry{Operation: string(action.Kind), Destination: joinSyncPath(dst, action.Path)}
+		if action.Kind != s3lib.SyncDelete {
+			entry.Source = joinSyncPath(src, action.Path)
+		}
+		report.Actions = append(report.Actions, entry)
+	}
+	return report
+}
+
+func joinSyncPath(location, relPath string) string {
+	if strings.HasSuffix(location, "/") || strings.HasSuffix(location, ":") {
+		return location + relPath
+	}
+	return location + "/" + relPath
+}
+
+func printSyncReport(cmd *cobra.Command, asJSON bool, report syncReport) error {
+	if asJSON {
+		return printJSON(cmd.OutOrStdout(), report)
+	}
+
+	for _, action := range report.Actions {
+		if report.DryRun {
+			action.Operation = "(dry run) " + action.Operation
+		}
+		if err := printResult(cmd, false, action); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(cmd.OutOrStdout(), "%d new, %d changed, %d deleted, %d unchanged, %d bytes\n",
+		report.New, report.Changed, report.Deleted, report.Unchanged, report.Bytes)
+	return err
+}
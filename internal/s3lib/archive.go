@@ -0,0 +1,375 @@
+package s3lib
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/flate"
+	"context"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+const (
+	// archiveBlockSize is the size of the ranged reads of archives.
+	archiveBlockSize = 1 << 20
+	// archiveCachedBlocks is the number of blocks kept in memory.
+	archiveCachedBlocks = 8
+)
+
+// ErrNotArchive is returned by OpenArchive for objects that are not a zip
+// or tar archive.
+var ErrNotArchive = errors.New("not a zip or tar archive")
+
+// IsArchiveKey reports whether key names a zip or tar archive.
+func IsArchiveKey(key string) bool {
+	switch strings.ToLower(path.Ext(key)) {
+	case ".zip", ".tar", ".jar":
+		return true
+	}
+	return false
+}
+
+// Archive is a zip or tar object whose members are read with ranged reads
+// instead of downloading the whole object. The members are listed like
+// objects with keys below the key of the archive, so "a.zip/docs/readme.md"
+// is the member "docs/readme.md" of "a.zip".
+type Archive struct {
+	key     string
+	reader  *objectReaderAt
+	members map[string]archiveMember
+}
+
+type archiveMember struct {
+	size    int64
+	modTime time.Time
+	// open reads the member from the archive object r
+	open func(r io.ReaderAt) (io.ReadCloser, error)
+}
+
+// OpenArchive reads the member list of the archive key. Zip archives only
+// read their central directory at the end of the object, tar archives read
+// the header of each member. ctx is used for listing the members, members
+// are read with the context passed to ReadMember and ExtractMember.
+func OpenArchive(ctx context.Context, client Client, bucket, key, versionID string) (*Archive, error) {
+	reader := &objectReaderAt{
+		client:    client,
+		bucket:    bucket,
+		key:       key,
+		versionID: versionID,
+		blocks:    map[int64][]byte{},
+	}
+	// the first read also returns the size of the object
+	if _, err := reader.block(ctx, 0); err != nil {
+		return nil, err
+	}
+
+	archive := &Archive{key: key, reader: reader, members: map[string]archiveMember{}}
+	var err error
+	if strings.EqualFold(path.Ext(key), ".tar") {
+		err = archive.readTar(ctx, reader)
+	} else {
+		err = archive.readZip(ctx, reader)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrNotArchive, err)
+	}
+	return archive, nil
+}
+
+func (a *Archive) readZip(ctx context.Context, reader *objectReaderAt) error {
+	directory := &contextReaderAt{ctx: ctx, reader: reader}
+	zipReader, err := zip.NewReader(directory, reader.size)
+	if err != nil {
+		return err
+	}
+	// zip only finds the data of members by reading their local header with
+	// the reader of the directory, which therefore outlives ctx
+	directory.ctx = context.WithoutCancel(ctx)
+
+	for _, file := range zipReader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		a.members[cleanMemberName(file.Name)] = archiveMember{
+			size:    int64(file.UncompressedSize64),
+			modTime: file.Modified,
+			open: func(r io.ReaderAt) (io.ReadCloser, error) {
+				return openZipMember(file, r)
+			},
+		}
+	}
+	return nil
+}
+
+// openZipMember reads file from r and decompresses it like file.Open does.
+func openZipMember(file *zip.File, r io.ReaderAt) (io.ReadCloser, error) {
+	offset, err := file.DataOffset()
+	if err != nil {
+		return nil, err
+	}
+	data := io.NewSectionReader(r, offset, int64(file.CompressedSize64))
+
+	var decompressed io.ReadCloser
+	switch file.Method {
+	case zip.Store:
+		decompressed = io.NopCloser(data)
+	case zip.Deflate:
+		decompressed = flate.NewReader(data)
+	default:
+		return nil, zip.ErrAlgorithm
+	}
+	return &checksumReader{ReadCloser: decompressed, hash: crc32.NewIEEE(), crc: file.CRC32}, nil
+}
+
+// checksumReader fails with zip.ErrChecksum at the end of the data if its
+// CRC-32 is not crc.
+type checksumReader struct {
+	io.ReadCloser
+	hash hash.Hash32
+	crc  uint32
+}
+
+func (r *checksumReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.hash.Write(p[:n])
+	if err == io.EOF && r.crc != 0 && r.hash.Sum32() != r.crc {
+		err = zip.ErrChecksum
+	}
+	return n, err
+}
+
+func (a *Archive) readTar(ctx context.Context, reader *objectReaderAt) error {
+	// tar skips the contents of members by seeking, so only headers are read
+	section := io.NewSectionReader(&contextReaderAt{ctx: ctx, reader: reader}, 0, reader.size)
+	tarReader := tar.NewReader(section)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		offset, err := section.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return err
+		}
+		size := header.Size
+		a.members[cleanMemberName(header.Name)] = archiveMember{
+			size:    size,
+			modTime: header.ModTime,
+			open: func(r io.ReaderAt) (io.ReadCloser, error) {
+				return io.NopCloser(io.NewSectionReader(r, offset, size)), nil
+			},
+		}
+	}
+}
+
+// cleanMemberName makes the paths of members relative, like "./a" and "/a"
+// to "a".
+func cleanMemberName(name string) string {
+	return strings.TrimLeft(path.Clean("/"+name), "/")
+}
+
+// Key returns the key of the archive object.
+func (a *Archive) Key() string {
+	return a.key
+}
+
+// ListObjects lists the members and directories directly below prefix,
+// which is a path inside of the archive ending with a slash or empty.
+func (a *Archive) ListObjects(prefix string) Paginator[Object] {
+	var objects []Object
+	var directories []string
+	for name, member := range a.members {
+		after, ok := strings.CutPrefix(name, prefix)
+		if !ok {
+			continue
+		}
+		if dir, _, isDir := strings.Cut(after, "/"); isDir {
+			directories = append(directories, a.key+"/"+prefix+dir+"/")
+			continue
+		}
+		objects = append(objects, NewObjectFile(types.Object{
+			Key:          aws.String(a.key + "/" + name),
+			Size:         aws.Int64(member.size),
+			LastModified: aws.Time(member.modTime),
+		}))
+	}
+
+	slices.Sort(directories)
+	items := make([]Object, 0, len(objects)+len(directories))
+	for _, dir := range slices.Compact(directories) {
+		items = append(items, NewObjectDirectory(dir))
+	}
+	slices.SortFunc(objects, func(a, b Object) int {
+		return strings.Compare(aws.ToString(a.Object.Key), aws.ToString(b.Object.Key))
+	})
+	items = append(items, objects...)
+	return &memoryPaginator[Object]{items: items}
+}
+
+// MemberName returns the path inside of the archive of a key listed by
+// ListObjects.
+func (a *Archive) MemberName(key string) string {
+	return strings.TrimPrefix(key, a.key+"/")
+}
+
+// ReadMember reads up to length bytes from the start of a member and returns
+// them with the size of the member.
+func (a *Archive) ReadMember(ctx context.Context, name string, length int64) ([]byte, int64, error) {
+	member, ok := a.members[name]
+	if !ok {
+		return nil, 0, fmt.Errorf("%s has no member %s", a.key, name)
+	}
+
+	r, err := member.open(&contextReaderAt{ctx: ctx, reader: a.reader})
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() {
+		_ = r.Close()
+	}()
+
+	data, err := io.ReadAll(io.LimitReader(r, length))
+	if err != nil {
+		return nil, 0, err
+	}
+	return data, member.size, nil
+}
+
+// ExtractMember writes a member to filePath. progress may be nil.
+func (a *Archive) ExtractMember(ctx context.Context, name, filePath string, progress func(transferred, total int64)) error {
+	member, ok := a.members[name]
+	if !ok {
+		return fmt.Errorf("%s has no member %s", a.key, name)
+	}
+
+	r, err := member.open(&contextReaderAt{ctx: ctx, reader: a.reader})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = r.Close()
+	}()
+
+	if err := os.MkdirAll(path.Dir(filePath), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(filePath)
+	if err != nil {
+		return err
+	}
+
+	counter := newProgressCounter(member.size, progress)
+	buffer := make([]byte, 256<<10)
+	for {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		var n int
+		n, err = r.Read(buffer)
+		if n > 0 {
+			if _, writeErr := f.Write(buffer[:n]); writeErr != nil {
+				err = writeErr
+				break
+			}
+			counter.add(int64(n))
+		}
+		if err != nil {
+			break
+		}
+	}
+	if err == io.EOF {
+		err = nil
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(filePath)
+	}
+	return err
+}
+
+// objectReaderAt reads an object with ranged reads of whole blocks and keeps
+// the last read blocks, since archive readers read in small pieces.
+type objectReaderAt struct {
+	client                 Client
+	bucket, key, versionID string
+	size                   int64
+	mu                     sync.Mutex
+	blocks                 map[int64][]byte
+	order                  []int64
+}
+
+// contextReaderAt reads the object of reader with the requests bound to ctx.
+type contextReaderAt struct {
+	ctx    context.Context
+	reader *objectReaderAt
+}
+
+func (r *contextReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	return r.reader.readAt(r.ctx, p, off)
+}
+
+func (r *objectReaderAt) readAt(ctx context.Context, p []byte, off int64) (int, error) {
+	var n int
+	for n < len(p) {
+		if off+int64(n) >= r.size {
+			return n, io.EOF
+		}
+		index := (off + int64(n)) / archiveBlockSize
+		block, err := r.block(ctx, index)
+		if err != nil {
+			return n, err
+		}
+		start := off + int64(n) - index*archiveBlockSize
+		if start >= int64(len(block)) {
+			return n, io.EOF
+		}
+		n += copy(p[n:], block[start:])
+	}
+	return n, nil
+}
+
+func (r *objectReaderAt) block(ctx context.Context, index int64) ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if block, ok := r.blocks[index]; ok {
+		return block, nil
+	}
+
+	block, size, err := r.client.ReadObjectRange(ctx, r.bucket, r.key, r.versionID, index*archiveBlockSize, archiveBlockSize)
+	if err != nil {
+		return nil, err
+	}
+	if r.size == 0 {
+		r.size = size
+	}
+
+	if len(r.order) == archiveCachedBlocks {
+		delete(r.blocks, r.order[0])
+		r.order = r.order[1:]
+	}
+	r.blocks[index] = block
+	r.order = append(r.order, index)
+	return block, nil
+}
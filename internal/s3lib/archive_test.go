@@ -0,0 +1,156 @@
+package s3lib
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// countingClient counts the bytes read with ranged reads and fails reads
+// with a done ctx.
+type countingClient struct {
+	*MemoryClient
+	read int64
+}
+
+func (c *countingClient) ReadObjectRange(ctx context.Context, bucket, key, versionID string, offset, length int64) ([]byte, int64, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+	data, size, err := c.MemoryClient.ReadObjectRange(ctx, bucket, key, versionID, offset, length)
+	c.read += int64(len(data))
+	return data, size, err
+}
+
+func testZip(t *testing.T, files map[string][]byte) []byte {
+	var buffer bytes.Buffer
+	w := zip.NewWriter(&buffer)
+	for name, data := range files {
+		// stored without compression, so the archive is as large as its
+		// files, except for text files
+		method := zip.Store
+		if strings.HasSuffix(name, ".txt") {
+			method = zip.Deflate
+		}
+		f, err := w.CreateHeader(&zip.FileHeader{Name: name, Method: method})
+		if err != nil {
+			t.Fatalf("create zip member failed: %v", err)
+		}
+		if _, err := f.Write(data); err != nil {
+			t.Fatalf("write zip member failed: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("close zip failed: %v", err)
+	}
+	return buffer.Bytes()
+}
+
+func testTar(t *testing.T, files map[string][]byte) []byte {
+	var buffer bytes.Buffer
+	w := tar.NewWriter(&buffer)
+	for name, data := range files {
+		if err := w.WriteHeader(&tar.Header{Name: name, Size: int64(len(data)), Mode: 0o644, Typeflag: tar.TypeReg}); err != nil {
+			t.Fatalf("write tar header failed: %v", err)
+		}
+		if _, err := w.Write(data); err != nil {
+			t.Fatalf("write tar member failed: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("close tar failed: %v", err)
+	}
+	return buffer.Bytes()
+}
+
+func objectKeys(t *testing.T, paginator Paginator[Object]) []string {
+	objects, err := paginator.NextPage(context.Background())
+	if err != nil {
+		t.Fatalf("list failed: %v", err)
+	}
+	var keys []string
+	for _, obj := range objects {
+		keys = append(keys, aws.ToString(obj.Object.Key))
+	}
+	return keys
+}
+
+func TestOpenArchive(t *testing.T) {
+	files := map[string][]byte{
+		"readme.md":        []byte("# readme"),
+		"docs/a.txt":       []byte("a"),
+		"./docs/sub/b.txt": []byte("b"),
+		"large.bin":        testPayload(3 * archiveBlockSize),
+	}
+
+	for _, key := range []string{"bundle.zip", "bundle.tar"} {
+		data := testZip(t, files)
+		if key == "bundle.tar" {
+			data = testTar(t, files)
+		}
+		client := &countingClient{MemoryClient: NewMemoryClientFactory().
+			WithBucket("bucket", "us-east-1", time.Now()).
+			WithObject("bucket", key, int64(len(data)), time.Now(), "etag", "STANDARD", data).
+			Build()}
+
+		archive, err := OpenArchive(context.Background(), client, "bucket", key, "")
+		if err != nil {
+			t.Fatalf("open %s failed: %v", key, err)
+		}
+		if client.read >= int64(len(data)) {
+			t.Errorf("%s: expected only parts to be read, read %d of %d bytes", key, client.read, len(data))
+		}
+
+		want := []string{key + "/docs/", key + "/large.bin", key + "/readme.md"}
+		if got := objectKeys(t, archive.ListObjects("")); !slices.Equal(got, want) {
+			t.Errorf("%s: expected %v, got %v", key, want, got)
+		}
+		want = []string{key + "/docs/sub/", key + "/docs/a.txt"}
+		if got := objectKeys(t, archive.ListObjects("docs/")); !slices.Equal(got, want) {
+			t.Errorf("%s: expected %v, got %v", key, want, got)
+		}
+
+		// members are read with the context of the call
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, _, err := archive.ReadMember(ctx, "large.bin", 3*archiveBlockSize); !errors.Is(err, context.Canceled) {
+			t.Errorf("%s: expected cancelled read, got %v", key, err)
+		}
+
+		member, size, err := archive.ReadMember(context.Background(), archive.MemberName(key+"/docs/sub/b.txt"), 10)
+		if err != nil || string(member) != "b" || size != 1 {
+			t.Errorf("%s: expected member b, got %q, %d, %v", key, member, size, err)
+		}
+
+		filePath := filepath.Join(t.TempDir(), "large.bin")
+		if err := archive.ExtractMember(context.Background(), "large.bin", filePath, nil); err != nil {
+			t.Fatalf("%s: extract failed: %v", key, err)
+		}
+		extracted, _ := os.ReadFile(filePath)
+		if !bytes.Equal(extracted, files["large.bin"]) {
+			t.Errorf("%s: extracted member differs", key)
+		}
+	}
+}
+
+func TestOpenArchiveRejectsOtherObjects(t *testing.T) {
+	client := NewMemoryClientFactory().
+		WithBucket("bucket", "us-east-1", time.Now()).
+		WithObject("bucket", "fake.zip", 4, time.Now(), "etag", "STANDARD", []byte("fake")).
+		Build()
+
+	_, err := OpenArchive(context.Background(), client, "bucket", "fake.zip", "")
+	if !errors.Is(err, ErrNotArchive) {
+		t.Fatalf("expected ErrNotArchive, got %v", err)
+	}
+}
@@ -3,13 +3,26 @@ package s3lib
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/ssooidc"
 )
 
+// assumeRoleDuration is the duration of role sessions of profiles without
+// duration_seconds. Sessions with MFA cannot be renewed without asking for a
+// code again, so they last for the longest duration roles allow by default.
+const assumeRoleDuration = time.Hour
+
 type AwsConnector struct {
-	name string
+	name           string
+	credentialType string
+	mfaSerial      string
+	sso            *awsSSO
 }
 
 func (c *AwsConnector) Name() string {
@@ -20,15 +33,60 @@ func (c *AwsConnector) Type() string {
 	return "aws"
 }
 
+// CredentialType returns the kind of credentials of the profile, like
+// CredentialsSSO, or an empty string if they come from the environment.
+func (c *AwsConnector) CredentialType() string {
+	return c.credentialType
+}
+
+// CreateClient creates a client for the profile. Profiles with SSO or an MFA
+// device sign in right away, asking the Prompter of ctx for MFA codes and
+// for a device login if the SSO session expired.
 func (c *AwsConnector) CreateClient(ctx context.Context) (Client, error) {
+	var prompting atomic.Bool
+	prompting.Store(true)
+	defer prompting.Store(false)
+
+	sdkConfig, err := c.loadConfig(ctx, &prompting)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.sso != nil || c.mfaSerial != "" {
+		_, err = sdkConfig.Credentials.Retrieve(ctx)
+		if err != nil && c.sso != nil && prompterFrom(ctx) != nil && !ssoTokenValid(*c.sso) {
+			oidc := ssooidc.New(ssooidc.Options{Region: c.sso.region})
+			if err := ssoDeviceLogin(ctx, oidc, c.name, *c.sso); err != nil {
+				return nil, err
+			}
+			sdkConfig, err = c.loadConfig(ctx, &prompting)
+			if err != nil {
+				return nil, err
+			}
+			_, err = sdkConfig.Credentials.Retrieve(ctx)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to sign in with profile %s: %w", c.name, err)
+		}
+	}
+
+	return NewSdkClient(s3.NewFromConfig(sdkConfig)), nil
+}
+
+func (c *AwsConnector) loadConfig(ctx context.Context, prompting *atomic.Bool) (aws.Config, error) {
 	opts := []func(*config.LoadOptions) error{
 		config.WithSharedConfigProfile(c.name),
+		config.WithAssumeRoleCredentialOptions(func(o *stscreds.AssumeRoleOptions) {
+			o.TokenProvider = mfaTokenProvider(ctx, c.name, aws.ToString(o.SerialNumber), prompting)
+			if o.Duration == 0 {
+				o.Duration = assumeRoleDuration
+			}
+		}),
 	}
 
 	sdkConfig, err := config.LoadDefaultConfig(ctx, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load AWS SDK config for profile %s: %w", c.name, err)
+		return aws.Config{}, fmt.Errorf("failed to load AWS SDK config for profile %s: %w", c.name, err)
 	}
-
-	return NewSdkClient(s3.NewFromConfig(sdkConfig)), nil
+	return sdkConfig, nil
 }
@@ -0,0 +1,104 @@
+package s3lib
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testPrompter answers prompts in order and records their messages.
+type testPrompter struct {
+	answers  []string
+	messages []string
+}
+
+func (p *testPrompter) Prompt(ctx context.Context, message, label string) (string, error) {
+	p.messages = append(p.messages, message)
+	if len(p.answers) == 0 {
+		return "", context.Canceled
+	}
+	answer := p.answers[0]
+	p.answers = p.answers[1:]
+	return answer, nil
+}
+
+// newSTSStandIn starts a server answering AssumeRole requests like STS and
+// records their form values and Authorization header.
+func newSTSStandIn(t *testing.T, requests *[]map[string]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("parse form failed: %v", err)
+		}
+		values := map[string]string{}
+		for key := range r.PostForm {
+			values[key] = r.PostForm.Get(key)
+		}
+		values["Authorization"] = r.Header.Get("Authorization")
+		*requests = append(*requests, values)
+
+		w.Header().Set("Content-Type", "text/xml")
+		_, _ = fmt.Fprint(w, `<AssumeRoleResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleResult>
+    <Credentials>
+      <AccessKeyId>ASSUMED</AccessKeyId>
+      <SecretAccessKey>secret</SecretAccessKey>
+      <SessionToken>token</SessionToken>
+      <Expiration>2099-01-01T00:00:00Z</Expiration>
+    </Credentials>
+    <AssumedRoleUser>
+      <Arn>arn:aws:sts::123456789012:assumed-role/admin/s3tool</Arn>
+      <AssumedRoleId>AROA:s3tool</AssumedRoleId>
+    </AssumedRoleUser>
+  </AssumeRoleResult>
+</AssumeRoleResponse>`)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestAwsConnectorPromptsForMFA(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	var requests []map[string]string
+	server := newSTSStandIn(t, &requests)
+	t.Setenv("AWS_ENDPOINT_URL_STS", server.URL)
+
+	// the SDK resolves the default path of the config before HOME is set
+	configFile := filepath.Join(home, "config")
+	t.Setenv("AWS_CONFIG_FILE", configFile)
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(home, "credentials"))
+	mustWriteTestFile(t, configFile, `[profile base]
+region = eu-central-1
+aws_access_key_id = x
+aws_secret_access_key = y
+
+[profile admin]
+region = eu-central-1
+role_arn = arn:aws:iam::123456789012:role/admin
+source_profile = base
+mfa_serial = arn:aws:iam::123456789012:mfa/user
+`)
+
+	connector := &AwsConnector{name: "admin", credentialType: CredentialsAssumeRole, mfaSerial: "arn:aws:iam::123456789012:mfa/user"}
+	prompter := &testPrompter{answers: []string{"123456"}}
+	if _, err := connector.CreateClient(WithPrompter(context.Background(), prompter)); err != nil {
+		t.Fatalf("create client failed: %v", err)
+	}
+
+	if len(prompter.messages) != 1 || !strings.Contains(prompter.messages[0], "mfa/user") {
+		t.Fatalf("expected one MFA prompt, got %q", prompter.messages)
+	}
+	if len(requests) != 1 || requests[0]["TokenCode"] != "123456" || requests[0]["SerialNumber"] != "arn:aws:iam::123456789012:mfa/user" || requests[0]["DurationSeconds"] != "3600" {
+		t.Fatalf("unexpected assume role requests %v", requests)
+	}
+
+	if _, err := connector.CreateClient(context.Background()); err == nil || !strings.Contains(err.Error(), "cannot ask") {
+		t.Fatalf("expected error without prompter, got %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package s3lib
 
 import (
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
 	"strings"
@@ -9,6 +10,15 @@ import (
 	"gopkg.in/ini.v1"
 )
 
+// Credential types of profiles, as reported by CredentialType.
+const (
+	CredentialsStatic      = "static"
+	CredentialsSSO         = "sso"
+	CredentialsAssumeRole  = "assume-role"
+	CredentialsProcess     = "credential-process"
+	CredentialsWebIdentity = "web-identity"
+)
+
 type AwsLoader struct{}
 
 func (l *AwsLoader) Load() ([]Connector, error) {
@@ -20,7 +30,9 @@ func (l *AwsLoader) Load() ([]Connector, error) {
 	configFile := filepath.Join(home, ".aws", "config")
 	credsFile := filepath.Join(home, ".aws", "credentials")
 
-	profiles := make(map[string]bool)
+	// settings of the profiles, the credentials file overrides the config
+	profiles := make(map[string]map[string]string)
+	ssoSessions := make(map[string]map[string]string)
 
 	loadProfiles := func(path string, isConfig bool) {
 		cfg, err := ini.Load(path)
@@ -31,12 +43,18 @@ func (l *AwsLoader) Load() ([]Connector, error) {
 		for _, section := range cfg.Sections() {
 			name := section.Name()
 			if name == ini.DefaultSection {
-				profiles["default"] = true
+				name = "default"
+			} else if isConfig && strings.HasPrefix(name, "sso-session ") {
+				ssoSessions[strings.TrimPrefix(name, "sso-session ")] = section.KeysHash()
+				continue
 			} else if isConfig && strings.HasPrefix(name, "profile ") {
-				profiles[strings.TrimPrefix(name, "profile ")] = true
-			} else {
-				profiles[name] = true
+				name = strings.TrimPrefix(name, "profile ")
 			}
+
+			if profiles[name] == nil {
+				profiles[name] = make(map[string]string)
+			}
+			maps.Copy(profiles[name], section.KeysHash())
 		}
 	}
 
@@ -44,8 +62,71 @@ func (l *AwsLoader) Load() ([]Connector, error) {
 	loadProfiles(credsFile, false)
 
 	var profileList []Connector
-	for profile := range profiles {
-		profileList = append(profileList, &AwsConnector{name: profile})
+	for profile, settings := range profiles {
+		profileList = append(profileList, &AwsConnector{
+			name:           profile,
+			credentialType: credentialType(settings),
+			mfaSerial:      settings["mfa_serial"],
+			sso:            profileSSO(profiles, ssoSessions, profile),
+		})
 	}
 	return profileList, nil
 }
+
+// credentialType detects the kind of credentials a profile uses in the order
+// the SDK resolves them. Profiles without any get their credentials from the
+// environment and have no type.
+func credentialType(settings map[string]string) string {
+	switch {
+	case settings["role_arn"] != "" && settings["web_identity_token_file"] != "":
+		return CredentialsWebIdentity
+	case settings["role_arn"] != "":
+		return CredentialsAssumeRole
+	case settings["sso_session"] != "" || settings["sso_start_url"] != "":
+		return CredentialsSSO
+	case settings["credential_process"] != "":
+		return CredentialsProcess
+	case settings["aws_access_key_id"] != "":
+		return CredentialsStatic
+	}
+	return ""
+}
+
+// profileSSO returns the SSO sign-in of a profile, or of the profile its role
+// is assumed with, or nil if it does not use SSO.
+func profileSSO(profiles, ssoSessions map[string]map[string]string, name string) *awsSSO {
+	// source profiles may form a cycle
+	for range len(profiles) {
+		settings, ok := profiles[name]
+		if !ok {
+			return nil
+		}
+
+		if sessionName := settings["sso_session"]; sessionName != "" {
+			session, ok := ssoSessions[sessionName]
+			if !ok {
+				return nil
+			}
+			return &awsSSO{
+				startURL: session["sso_start_url"],
+				region:   session["sso_region"],
+				scopes:   strings.FieldsFunc(session["sso_registration_scopes"], isScopeSeparator),
+				cacheKey: sessionName,
+			}
+		}
+		if startURL := settings["sso_start_url"]; startURL != "" {
+			return &awsSSO{
+				startURL: startURL,
+				region:   settings["sso_region"],
+				cacheKey: startURL,
+			}
+		}
+
+		name = settings["source_profile"]
+	}
+	return nil
+}
+
+func isScopeSeparator(r rune) bool {
+	return r == ',' || r == ' '
+}
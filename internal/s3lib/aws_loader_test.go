@@ -95,3 +95,93 @@ func mustWriteTestFile(t *testing.T, path, content string) {
 		t.Fatalf("write file %s failed: %v", path, err)
 	}
 }
+
+func TestAwsLoaderDetectsCredentialTypes(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	awsDir := filepath.Join(home, ".aws")
+	mustMkdirAll(t, awsDir)
+
+	config := `[profile static]
+region = eu-central-1
+
+[profile sso]
+sso_session = corp
+sso_account_id = 123456789012
+sso_role_name = Admin
+
+[profile legacy-sso]
+sso_start_url = https://legacy.awsapps.com/start
+sso_region = us-east-1
+
+[profile role]
+role_arn = arn:aws:iam::123456789012:role/admin
+source_profile = sso
+mfa_serial = arn:aws:iam::123456789012:mfa/user
+
+[profile process]
+credential_process = /usr/bin/creds
+
+[profile web]
+role_arn = arn:aws:iam::123456789012:role/web
+web_identity_token_file = /var/token
+
+[sso-session corp]
+sso_start_url = https://corp.awsapps.com/start
+sso_region = eu-west-1
+sso_registration_scopes = sso:account:access
+`
+	credentials := `[static]
+aws_access_key_id = x
+aws_secret_access_key = y
+`
+	mustWriteTestFile(t, filepath.Join(awsDir, "config"), config)
+	mustWriteTestFile(t, filepath.Join(awsDir, "credentials"), credentials)
+
+	profiles, err := (&AwsLoader{}).Load()
+	if err != nil {
+		t.Fatalf("load profiles failed: %v", err)
+	}
+
+	connectors := map[string]*AwsConnector{}
+	for _, p := range profiles {
+		connectors[p.Name()] = p.(*AwsConnector)
+	}
+
+	expected := map[string]string{
+		"default":    "",
+		"static":     CredentialsStatic,
+		"sso":        CredentialsSSO,
+		"legacy-sso": CredentialsSSO,
+		"role":       CredentialsAssumeRole,
+		"process":    CredentialsProcess,
+		"web":        CredentialsWebIdentity,
+	}
+	if len(connectors) != len(expected) {
+		t.Fatalf("expected profiles %v, got %v", expected, connectors)
+	}
+	for name, credentialType := range expected {
+		connector, ok := connectors[name]
+		if !ok {
+			t.Fatalf("expected profile %s", name)
+		}
+		if connector.CredentialType() != credentialType {
+			t.Fatalf("expected credential type %q for %s, got %q", credentialType, name, connector.CredentialType())
+		}
+	}
+
+	sso := connectors["role"].sso
+	if sso == nil || sso.cacheKey != "corp" || sso.startURL != "https://corp.awsapps.com/start" || sso.region != "eu-west-1" || len(sso.scopes) != 1 {
+		t.Fatalf("expected the sso session of the source profile, got %+v", sso)
+	}
+	if connectors["role"].mfaSerial != "arn:aws:iam::123456789012:mfa/user" {
+		t.Fatalf("expected mfa serial, got %q", connectors["role"].mfaSerial)
+	}
+	if sso := connectors["legacy-sso"].sso; sso == nil || sso.cacheKey != "https://legacy.awsapps.com/start" {
+		t.Fatalf("expected the start url as cache key, got %+v", sso)
+	}
+	if connectors["static"].sso != nil {
+		t.Fatal("expected no sso for static profile")
+	}
+}
@@ -0,0 +1,140 @@
+package s3lib
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/ssocreds"
+	"github.com/aws/aws-sdk-go-v2/service/ssooidc"
+	oidctypes "github.com/aws/aws-sdk-go-v2/service/ssooidc/types"
+)
+
+const deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+// awsSSO is the SSO sign-in of a profile.
+type awsSSO struct {
+	startURL string
+	region   string
+	scopes   []string
+	// cacheKey names the cached token: the name of the sso-session, or the
+	// start URL for profiles configured without one
+	cacheKey string
+}
+
+// ssoCachedToken is a token in ~/.aws/sso/cache in the format of the AWS CLI,
+// which the SDK reads and refreshes.
+type ssoCachedToken struct {
+	AccessToken           string `json:"accessToken"`
+	ExpiresAt             string `json:"expiresAt"`
+	RefreshToken          string `json:"refreshToken,omitempty"`
+	ClientID              string `json:"clientId,omitempty"`
+	ClientSecret          string `json:"clientSecret,omitempty"`
+	RegistrationExpiresAt string `json:"registrationExpiresAt,omitempty"`
+	Region                string `json:"region,omitempty"`
+	StartURL              string `json:"startUrl,omitempty"`
+}
+
+// ssoTokenValid reports whether the cached token of sso exists and has not
+// expired.
+func ssoTokenValid(sso awsSSO) bool {
+	path, err := ssocreds.StandardCachedTokenFilepath(sso.cacheKey)
+	if err != nil {
+		return false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+
+	var token ssoCachedToken
+	if err := json.Unmarshal(data, &token); err != nil {
+		return false
+	}
+	expiresAt, err := time.Parse(time.RFC3339, token.ExpiresAt)
+	return err == nil && token.AccessToken != "" && time.Now().Before(expiresAt)
+}
+
+// ssoDeviceLogin signs in to sso with the device authorization flow, which
+// the user confirms in a browser, and caches the token for the SDK.
+func ssoDeviceLogin(ctx context.Context, client *ssooidc.Client, profile string, sso awsSSO) error {
+	prompter := prompterFrom(ctx)
+	if prompter == nil {
+		return fmt.Errorf("the SSO session of profile %s expired, but cannot sign in", profile)
+	}
+
+	registration, err := client.RegisterClient(ctx, &ssooidc.RegisterClientInput{
+		ClientName: aws.String("s3tool"),
+		ClientType: aws.String("public"),
+		Scopes:     sso.scopes,
+	})
+	if err != nil {
+		return fmt.Errorf("SSO sign-in of profile %s failed: %w", profile, err)
+	}
+
+	authorization, err := client.StartDeviceAuthorization(ctx, &ssooidc.StartDeviceAuthorizationInput{
+		ClientId:     registration.ClientId,
+		ClientSecret: registration.ClientSecret,
+		StartUrl:     aws.String(sso.startURL),
+	})
+	if err != nil {
+		return fmt.Errorf("SSO sign-in of profile %s failed: %w", profile, err)
+	}
+
+	instructions := fmt.Sprintf("Open\n\n%s\n\nand confirm the code %s, then continue.",
+		aws.ToString(authorization.VerificationUriComplete), aws.ToString(authorization.UserCode))
+	message := fmt.Sprintf("The SSO session of profile %s expired. %s", profile, instructions)
+	for {
+		if _, err := prompter.Prompt(ctx, message, ""); err != nil {
+			return err
+		}
+
+		token, err := client.CreateToken(ctx, &ssooidc.CreateTokenInput{
+			ClientId:     registration.ClientId,
+			ClientSecret: registration.ClientSecret,
+			DeviceCode:   authorization.DeviceCode,
+			GrantType:    aws.String(deviceCodeGrantType),
+		})
+		var pending *oidctypes.AuthorizationPendingException
+		var slowDown *oidctypes.SlowDownException
+		if errors.As(err, &pending) || errors.As(err, &slowDown) {
+			message = "The sign-in is not confirmed yet. " + instructions
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("SSO sign-in of profile %s failed: %w", profile, err)
+		}
+
+		now := time.Now().UTC()
+		return storeSSOToken(sso, ssoCachedToken{
+			AccessToken:           aws.ToString(token.AccessToken),
+			ExpiresAt:             now.Add(time.Duration(token.ExpiresIn) * time.Second).Format(time.RFC3339),
+			RefreshToken:          aws.ToString(token.RefreshToken),
+			ClientID:              aws.ToString(registration.ClientId),
+			ClientSecret:          aws.ToString(registration.ClientSecret),
+			RegistrationExpiresAt: time.Unix(registration.ClientSecretExpiresAt, 0).UTC().Format(time.RFC3339),
+			Region:                sso.region,
+			StartURL:              sso.startURL,
+		})
+	}
+}
+
+func storeSSOToken(sso awsSSO, token ssoCachedToken) error {
+	path, err := ssocreds.StandardCachedTokenFilepath(sso.cacheKey)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
@@ -0,0 +1,124 @@
+package s3lib
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/ssocreds"
+	"github.com/aws/aws-sdk-go-v2/service/ssooidc"
+)
+
+// newOIDCStandIn starts a server answering the device authorization flow
+// like the SSO OIDC service. The token is pending for the first pending
+// requests.
+func newOIDCStandIn(t *testing.T, pending int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/client/register":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"clientId":              "client",
+				"clientSecret":          "secret",
+				"clientSecretExpiresAt": 4070908800,
+			})
+		case "/device_authorization":
+			if body["startUrl"] != "https://corp.awsapps.com/start" {
+				t.Errorf("unexpected start url %v", body["startUrl"])
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"deviceCode":              "device",
+				"userCode":                "ABCD-EFGH",
+				"verificationUriComplete": "https://device.sso.example/?user_code=ABCD-EFGH",
+				"expiresIn":               600,
+				"interval":                1,
+			})
+		case "/token":
+			if pending > 0 {
+				pending--
+				w.Header().Set("X-Amzn-Errortype", "AuthorizationPendingException")
+				w.WriteHeader(http.StatusBadRequest)
+				_ = json.NewEncoder(w).Encode(map[string]any{"error": "authorization_pending"})
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"accessToken":  "access",
+				"refreshToken": "refresh",
+				"expiresIn":    3600,
+				"tokenType":    "Bearer",
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSSODeviceLogin(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	server := newOIDCStandIn(t, 1)
+	client := ssooidc.New(ssooidc.Options{Region: "eu-west-1", BaseEndpoint: aws.String(server.URL)})
+	sso := awsSSO{startURL: "https://corp.awsapps.com/start", region: "eu-west-1", cacheKey: "corp"}
+
+	if ssoTokenValid(sso) {
+		t.Fatal("expected no valid token before signing in")
+	}
+
+	prompter := &testPrompter{answers: []string{"", ""}}
+	if err := ssoDeviceLogin(WithPrompter(context.Background(), prompter), client, "dev", sso); err != nil {
+		t.Fatalf("device login failed: %v", err)
+	}
+
+	if len(prompter.messages) != 2 {
+		t.Fatalf("expected a second prompt while pending, got %q", prompter.messages)
+	}
+	if !strings.Contains(prompter.messages[0], "ABCD-EFGH") || !strings.Contains(prompter.messages[1], "not confirmed yet") {
+		t.Fatalf("unexpected prompts %q", prompter.messages)
+	}
+	if !ssoTokenValid(sso) {
+		t.Fatal("expected a valid cached token after signing in")
+	}
+
+	path, err := ssocreds.StandardCachedTokenFilepath("corp")
+	if err != nil {
+		t.Fatalf("token path failed: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read token failed: %v", err)
+	}
+	var token ssoCachedToken
+	if err := json.Unmarshal(data, &token); err != nil {
+		t.Fatalf("parse token failed: %v", err)
+	}
+	if token.AccessToken != "access" || token.RefreshToken != "refresh" || token.ClientID != "client" || token.StartURL != sso.startURL {
+		t.Fatalf("unexpected cached token %+v", token)
+	}
+}
+
+func TestSSODeviceLoginCancelled(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	server := newOIDCStandIn(t, 0)
+	client := ssooidc.New(ssooidc.Options{Region: "eu-west-1", BaseEndpoint: aws.String(server.URL)})
+	sso := awsSSO{startURL: "https://corp.awsapps.com/start", region: "eu-west-1", cacheKey: "corp"}
+
+	err := ssoDeviceLogin(WithPrompter(context.Background(), &testPrompter{}), client, "dev", sso)
+	if err != context.Canceled {
+		t.Fatalf("expected cancelled login, got %v", err)
+	}
+	if ssoTokenValid(sso) {
+		t.Fatal("expected no token after cancelled login")
+	}
+}
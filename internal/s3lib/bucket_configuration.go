@@ -0,0 +1,116 @@
+package s3lib
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+
+	"github.com/aws/smithy-go"
+)
+
+// Names of the settings reported by GetBucketConfiguration, in the order they
+// are reported.
+const (
+	BucketSettingRegion            = "Region"
+	BucketSettingVersioning        = "Versioning"
+	BucketSettingEncryption        = "Encryption"
+	BucketSettingLifecycle         = "Lifecycle"
+	BucketSettingCORS              = "CORS"
+	BucketSettingPolicy            = "Policy"
+	BucketSettingPublicAccessBlock = "Public Access Block"
+	BucketSettingObjectLock        = "Object Lock"
+	BucketSettingTags              = "Tags"
+	BucketSettingLogging           = "Logging"
+	BucketSettingReplication       = "Replication"
+)
+
+var bucketSettingNames = []string{
+	BucketSettingRegion,
+	BucketSettingVersioning,
+	BucketSettingEncryption,
+	BucketSettingLifecycle,
+	BucketSettingCORS,
+	BucketSettingPolicy,
+	BucketSettingPublicAccessBlock,
+	BucketSettingObjectLock,
+	BucketSettingTags,
+	BucketSettingLogging,
+	BucketSettingReplication,
+}
+
+// ErrNotSupported is the error of bucket settings the provider does not
+// implement, as some S3-compatible providers like MinIO do.
+var ErrNotSupported = errors.New("not supported by the provider")
+
+// BucketSetting is one part of the configuration of a bucket.
+type BucketSetting struct {
+	Name string
+	// Values summarizes the setting, one entry per property or rule. It is
+	// empty if the setting is not configured.
+	Values []string
+	// Err is set if the setting could not be fetched. It is ErrNotSupported
+	// if the provider does not implement the setting.
+	Err error
+}
+
+// notConfiguredErrorCodes are the error codes S3 answers with when a bucket
+// has no such setting.
+var notConfiguredErrorCodes = []string{
+	"NoSuchBucketPolicy",
+	"NoSuchCORSConfiguration",
+	"NoSuchLifecycleConfiguration",
+	"NoSuchPublicAccessBlockConfiguration",
+	"NoSuchTagSet",
+	"NoSuchTagSetError",
+	"ObjectLockConfigurationNotFoundError",
+	"ReplicationConfigurationNotFoundError",
+	"ServerSideEncryptionConfigurationNotFoundError",
+}
+
+// notSupportedErrorCodes are the error codes of providers that do not
+// implement a setting.
+var notSupportedErrorCodes = []string{
+	"NotImplemented",
+	"XNotImplemented",
+}
+
+// bucketSettingError classifies the error of fetching a bucket setting.
+// Errors meaning that the setting is not configured become nil.
+func bucketSettingError(err error) error {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch {
+		case slices.Contains(notConfiguredErrorCodes, apiErr.ErrorCode()):
+			return nil
+		case slices.Contains(notSupportedErrorCodes, apiErr.ErrorCode()):
+			return ErrNotSupported
+		}
+	}
+	return err
+}
+
+// bucketSettingFetcher fetches the summary of a single bucket setting.
+type bucketSettingFetcher struct {
+	name  string
+	fetch func(ctx context.Context, bucket string) ([]string, error)
+}
+
+// fetchBucketSettings runs all fetchers concurrently, so that slow or
+// failing settings do not hold up the others.
+func fetchBucketSettings(ctx context.Context, bucket string, fetchers []bucketSettingFetcher) []BucketSetting {
+	settings := make([]BucketSetting, len(fetchers))
+	var wg sync.WaitGroup
+	for i, fetcher := range fetchers {
+		wg.Go(func() {
+			values, err := fetcher.fetch(ctx, bucket)
+			err = bucketSettingError(err)
+			if err != nil {
+				values = nil
+			}
+			settings[i] = BucketSetting{Name: fetcher.name, Values: values, Err: err}
+		})
+	}
+	wg.Wait()
+	return settings
+}
@@ -0,0 +1,67 @@
+package s3lib
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/smithy-go"
+)
+
+func TestFetchBucketSettingsClassifiesErrors(t *testing.T) {
+	accessDenied := &smithy.GenericAPIError{Code: "AccessDenied", Message: "denied"}
+	fetchers := []bucketSettingFetcher{
+		{BucketSettingVersioning, func(ctx context.Context, bucket string) ([]string, error) {
+			return []string{"Enabled"}, nil
+		}},
+		{BucketSettingLifecycle, func(ctx context.Context, bucket string) ([]string, error) {
+			return nil, &smithy.GenericAPIError{Code: "NoSuchLifecycleConfiguration"}
+		}},
+		{BucketSettingReplication, func(ctx context.Context, bucket string) ([]string, error) {
+			return []string{"partial"}, &smithy.GenericAPIError{Code: "NotImplemented"}
+		}},
+		{BucketSettingPolicy, func(ctx context.Context, bucket string) ([]string, error) {
+			return nil, accessDenied
+		}},
+	}
+
+	settings := fetchBucketSettings(context.Background(), "bucket", fetchers)
+	if len(settings) != 4 {
+		t.Fatalf("expected 4 settings, got %d", len(settings))
+	}
+	if settings[0].Name != BucketSettingVersioning || settings[0].Err != nil || settings[0].Values[0] != "Enabled" {
+		t.Fatalf("unexpected versioning setting %#v", settings[0])
+	}
+	if settings[1].Err != nil || len(settings[1].Values) != 0 {
+		t.Fatalf("expected lifecycle to be not configured, got %#v", settings[1])
+	}
+	if !errors.Is(settings[2].Err, ErrNotSupported) || settings[2].Values != nil {
+		t.Fatalf("expected replication to be not supported, got %#v", settings[2])
+	}
+	if !errors.Is(settings[3].Err, accessDenied) {
+		t.Fatalf("expected policy to keep its error, got %#v", settings[3])
+	}
+}
+
+func TestMemoryClientGetBucketConfiguration(t *testing.T) {
+	client := NewMemoryClientFactory().
+		WithBucket("bucket", "eu-central-1", time.Now()).
+		WithVersioning("bucket").
+		Build()
+
+	settings, err := client.GetBucketConfiguration(context.Background(), "bucket")
+	if err != nil {
+		t.Fatalf("get bucket configuration failed: %v", err)
+	}
+	if len(settings) != len(bucketSettingNames) {
+		t.Fatalf("expected %d settings, got %d", len(bucketSettingNames), len(settings))
+	}
+	if settings[0].Values[0] != "eu-central-1" || settings[1].Values[0] != "Enabled" {
+		t.Fatalf("unexpected settings %#v", settings[:2])
+	}
+
+	if _, err := client.GetBucketConfiguration(context.Background(), "missing"); err == nil {
+		t.Fatal("expected error for missing bucket, got nil")
+	}
+}
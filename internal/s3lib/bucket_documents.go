@@ -0,0 +1,200 @@
+package s3lib
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"gopkg.in/yaml.v3"
+)
+
+// BucketDocument names a bucket setting that is edited as a whole JSON
+// document.
+type BucketDocument string
+
+const (
+	BucketPolicyDocument    BucketDocument = "policy"
+	BucketLifecycleDocument BucketDocument = "lifecycle"
+	BucketCORSDocument      BucketDocument = "cors"
+)
+
+// lifecycleDocument and corsDocument are the documents of lifecycle and CORS
+// settings, in the format of the AWS CLI.
+type lifecycleDocument struct {
+	Rules []types.LifecycleRule
+}
+
+type corsDocument struct {
+	CORSRules []types.CORSRule
+}
+
+// policyDocument holds the parts of a bucket policy that are checked before
+// it is sent.
+type policyDocument struct {
+	Version   string
+	Statement json.RawMessage
+}
+
+type policyStatement struct {
+	Effect string
+}
+
+var corsMethods = []string{"GET", "PUT", "POST", "DELETE", "HEAD"}
+
+// ParseBucketDocument checks a document given as JSON or YAML and returns it
+// as indented JSON. Only whitespace returns an empty document, which removes
+// the setting.
+func ParseBucketDocument(doc BucketDocument, data []byte) (string, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return "", nil
+	}
+
+	// JSON is valid YAML, so both are read the same way
+	var value any
+	if err := yaml.Unmarshal(data, &value); err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	switch doc {
+	case BucketPolicyDocument:
+		err = validatePolicy(data)
+	case BucketLifecycleDocument:
+		var lifecycle lifecycleDocument
+		if err = decodeStrict(data, &lifecycle); err == nil {
+			err = validateLifecycle(lifecycle)
+		}
+	case BucketCORSDocument:
+		var cors corsDocument
+		if err = decodeStrict(data, &cors); err == nil {
+			err = validateCORS(cors)
+		}
+	default:
+		err = fmt.Errorf("unknown bucket document %q", doc)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return formatDocument(data)
+}
+
+func decodeStrict(data []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
+
+func validatePolicy(data []byte) error {
+	var policy policyDocument
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return fmt.Errorf("policy must be a JSON object: %w", err)
+	}
+	if len(policy.Statement) == 0 {
+		return errors.New("policy has no Statement")
+	}
+
+	// Statement is either a single statement or a list of them
+	var statements []policyStatement
+	if err := json.Unmarshal(policy.Statement, &statements); err != nil {
+		var statement policyStatement
+		if err := json.Unmarshal(policy.Statement, &statement); err != nil {
+			return errors.New("policy Statement must be an object or a list of objects")
+		}
+		statements = []policyStatement{statement}
+	}
+	if len(statements) == 0 {
+		return errors.New("policy has no Statement")
+	}
+	for i, statement := range statements {
+		if statement.Effect != "Allow" && statement.Effect != "Deny" {
+			return fmt.Errorf("statement %d: Effect must be Allow or Deny", i+1)
+		}
+	}
+	return nil
+}
+
+func validateLifecycle(lifecycle lifecycleDocument) error {
+	if len(lifecycle.Rules) == 0 {
+		return errors.New("lifecycle configuration has no Rules")
+	}
+	for i, rule := range lifecycle.Rules {
+		if rule.Status != types.ExpirationStatusEnabled && rule.Status != types.ExpirationStatusDisabled {
+			return fmt.Errorf("rule %d: Status must be Enabled or Disabled", i+1)
+		}
+		if rule.Expiration == nil && len(rule.Transitions) == 0 && rule.NoncurrentVersionExpiration == nil &&
+			len(rule.NoncurrentVersionTransitions) == 0 && rule.AbortIncompleteMultipartUpload == nil {
+			return fmt.Errorf("rule %d: no action, expected Expiration, Transitions, NoncurrentVersionExpiration, NoncurrentVersionTransitions or AbortIncompleteMultipartUpload", i+1)
+		}
+	}
+	return nil
+}
+
+func validateCORS(cors corsDocument) error {
+	if len(cors.CORSRules) == 0 {
+		return errors.New("CORS configuration has no CORSRules")
+	}
+	for i, rule := range cors.CORSRules {
+		if len(rule.AllowedOrigins) == 0 {
+			return fmt.Errorf("rule %d: AllowedOrigins is empty", i+1)
+		}
+		if len(rule.AllowedMethods) == 0 {
+			return fmt.Errorf("rule %d: AllowedMethods is empty", i+1)
+		}
+		for _, method := range rule.AllowedMethods {
+			if !slices.Contains(corsMethods, method) {
+				return fmt.Errorf("rule %d: unsupported method %q, expected one of %s", i+1, method, strings.Join(corsMethods, ", "))
+			}
+		}
+	}
+	return nil
+}
+
+// formatDocument indents a JSON document and leaves out null values, which the
+// SDK types have for every unset field.
+func formatDocument(data []byte) (string, error) {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return "", err
+	}
+
+	formatted, err := json.MarshalIndent(withoutNulls(value), "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(formatted) + "\n", nil
+}
+
+func withoutNulls(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			if item == nil {
+				delete(v, key)
+			} else {
+				v[key] = withoutNulls(item)
+			}
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = withoutNulls(item)
+		}
+	}
+	return value
+}
+
+// marshalDocument formats a document read from S3.
+func marshalDocument(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return formatDocument(data)
+}
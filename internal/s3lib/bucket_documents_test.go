@@ -0,0 +1,111 @@
+package s3lib
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+func TestParseBucketDocument(t *testing.T) {
+	lifecycleYAML := `
+Rules:
+  - ID: expire-logs
+    Status: Enabled
+    Filter:
+      Prefix: logs/
+    Expiration:
+      Days: 30
+`
+	document, err := ParseBucketDocument(BucketLifecycleDocument, []byte(lifecycleYAML))
+	if err != nil {
+		t.Fatalf("parse lifecycle failed: %v", err)
+	}
+	if !strings.Contains(document, `"Days": 30`) || !strings.Contains(document, `"Prefix": "logs/"`) {
+		t.Fatalf("expected lifecycle as JSON, got %s", document)
+	}
+
+	document, err = ParseBucketDocument(BucketPolicyDocument, []byte(" \n"))
+	if err != nil || document != "" {
+		t.Fatalf("expected empty document, got %q, %v", document, err)
+	}
+
+	invalid := []struct {
+		doc      BucketDocument
+		document string
+	}{
+		{BucketLifecycleDocument, `{"Rules": [{"Status": "On", "Expiration": {"Days": 1}}]}`},
+		{BucketLifecycleDocument, `{"Rules": [{"Status": "Enabled"}]}`},
+		{BucketLifecycleDocument, `{"Rulez": []}`},
+		{BucketCORSDocument, `{"CORSRules": [{"AllowedMethods": ["PATCH"], "AllowedOrigins": ["*"]}]}`},
+		{BucketCORSDocument, `{"CORSRules": [{"AllowedMethods": ["GET"]}]}`},
+		{BucketPolicyDocument, `{"Version": "2012-10-17"}`},
+		{BucketPolicyDocument, `{"Statement": [{"Effect": "Maybe"}]}`},
+		{BucketPolicyDocument, `{"Statement": [`},
+	}
+	for _, test := range invalid {
+		if _, err := ParseBucketDocument(test.doc, []byte(test.document)); err == nil {
+			t.Errorf("expected %s document %s to be invalid", test.doc, test.document)
+		}
+	}
+
+	if _, err := ParseBucketDocument(BucketPolicyDocument, []byte(`{"Statement": {"Effect": "Deny"}}`)); err != nil {
+		t.Errorf("expected single statement policy to be valid, got %v", err)
+	}
+}
+
+func TestMarshalDocumentLeavesOutNulls(t *testing.T) {
+	document, err := marshalDocument(corsDocument{CORSRules: []types.CORSRule{{
+		AllowedMethods: []string{"GET"},
+		AllowedOrigins: []string{"*"},
+		MaxAgeSeconds:  aws.Int32(60),
+	}}})
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	if strings.Contains(document, "null") {
+		t.Fatalf("expected no null values, got %s", document)
+	}
+	if _, err := ParseBucketDocument(BucketCORSDocument, []byte(document)); err != nil {
+		t.Fatalf("expected marshalled document to be valid, got %v", err)
+	}
+}
+
+func TestMemoryClientBucketDocuments(t *testing.T) {
+	client := NewMemoryClientFactory().
+		WithBucket("bucket", "us-east-1", time.Now()).
+		Build()
+	ctx := context.Background()
+
+	cors := `{"CORSRules": [{"AllowedMethods": ["GET", "PUT"], "AllowedOrigins": ["*"]}]}`
+	if err := client.PutBucketDocument(ctx, "bucket", BucketCORSDocument, cors); err != nil {
+		t.Fatalf("put cors failed: %v", err)
+	}
+	document, err := client.GetBucketDocument(ctx, "bucket", BucketCORSDocument)
+	if err != nil || !strings.Contains(document, `"PUT"`) {
+		t.Fatalf("expected stored cors, got %q, %v", document, err)
+	}
+
+	settings, err := client.GetBucketConfiguration(ctx, "bucket")
+	if err != nil {
+		t.Fatalf("get bucket configuration failed: %v", err)
+	}
+	for _, setting := range settings {
+		if setting.Name == BucketSettingCORS && (len(setting.Values) != 1 || setting.Values[0] != "GET,PUT from *") {
+			t.Fatalf("unexpected cors setting %#v", setting)
+		}
+	}
+
+	if err := client.PutBucketDocument(ctx, "bucket", BucketCORSDocument, `{"CORSRules": []}`); err == nil {
+		t.Fatal("expected invalid document to be rejected, got nil")
+	}
+	if err := client.PutBucketDocument(ctx, "bucket", BucketCORSDocument, ""); err != nil {
+		t.Fatalf("delete cors failed: %v", err)
+	}
+	if document, _ := client.GetBucketDocument(ctx, "bucket", BucketCORSDocument); document != "" {
+		t.Fatalf("expected cors to be removed, got %q", document)
+	}
+}
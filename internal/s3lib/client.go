@@ -2,6 +2,7 @@ package s3lib
 
 import (
 	"context"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 )
@@ -10,10 +11,43 @@ type Client interface {
 	ConnectionParameters(bucket string) ConnectionParameters
 	ListBuckets(ctx context.Context) Paginator[types.Bucket]
 	ListObjects(ctx context.Context, bucket string, prefix string) Paginator[Object]
+	ListObjectVersions(ctx context.Context, bucket string, prefix string) Paginator[Object]
+	// ListObjectsRecursive lists all objects below prefix without grouping
+	// them into directories.
+	ListObjectsRecursive(ctx context.Context, bucket string, prefix string) Paginator[Object]
 	CreateBucket(ctx context.Context, bucket, region string) error
-	UploadFile(ctx context.Context, bucket, key, filePath string) error
-	DownloadFile(ctx context.Context, bucket, key, filePath string) error
+	UploadFile(ctx context.Context, bucket, key, filePath string, optFns ...func(*TransferOptions)) error
+	DownloadFile(ctx context.Context, bucket, key, filePath string, optFns ...func(*TransferOptions)) error
+	// ReadObjectRange reads up to length bytes of key starting at offset and
+	// returns them with the size of the object. A negative offset counts from
+	// the end of the object, an empty versionID reads the current version.
+	ReadObjectRange(ctx context.Context, bucket, key, versionID string, offset, length int64) ([]byte, int64, error)
+	// CopyObject copies an object on the server side, keeping its metadata
+	// and tags.
+	CopyObject(ctx context.Context, srcBucket, srcKey, bucket, key string, optFns ...func(*TransferOptions)) error
 	GetObject(ctx context.Context, bucket, key string) (ObjectMetadata, error)
+	GetObjectTags(ctx context.Context, bucket, key string) (map[string]string, error)
+	// PutObjectTags replaces all tags of key.
+	PutObjectTags(ctx context.Context, bucket, key string, tags map[string]string) error
+	// SetObjectProperties replaces the headers and user metadata of key with
+	// an in-place copy, keeping its content and tags.
+	SetObjectProperties(ctx context.Context, bucket, key string, props ObjectProperties) error
+	// GetBucketConfiguration fetches the settings of bucket. Settings that
+	// cannot be fetched carry their error instead of failing the call.
+	GetBucketConfiguration(ctx context.Context, bucket string) ([]BucketSetting, error)
+	// GetBucketDocument returns the policy, lifecycle or CORS setting of
+	// bucket as indented JSON, or an empty string if it is not configured.
+	GetBucketDocument(ctx context.Context, bucket string, doc BucketDocument) (string, error)
+	// PutBucketDocument replaces the policy, lifecycle or CORS setting of
+	// bucket by a JSON or YAML document. An empty document removes it.
+	PutBucketDocument(ctx context.Context, bucket string, doc BucketDocument, document string) error
 	DeleteBucket(ctx context.Context, bucket string) error
 	DeleteObject(ctx context.Context, bucket, key string) error
+	DeleteObjects(ctx context.Context, bucket string, keys []string) error
+	DeleteObjectVersion(ctx context.Context, bucket, key, versionID string) error
+	RestoreObjectVersion(ctx context.Context, bucket, key, versionID string) error
+	// PresignObject returns a URL that allows anyone to perform method on key
+	// until expires has passed. An empty versionID addresses the current
+	// version; upload URLs cannot address a version.
+	PresignObject(ctx context.Context, bucket, key, versionID string, method PresignMethod, expires time.Duration) (string, error)
 }
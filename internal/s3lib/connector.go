@@ -7,3 +7,9 @@ type Connector interface {
 	Type() string
 	CreateClient(ctx context.Context) (Client, error)
 }
+
+// CredentialTyper is implemented by connectors that know which kind of
+// credentials they use, like CredentialsSSO.
+type CredentialTyper interface {
+	CredentialType() string
+}
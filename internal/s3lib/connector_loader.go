@@ -1,5 +1,32 @@
 package s3lib
 
+import (
+	"slices"
+	"strings"
+)
+
 type ConnectorLoader interface {
 	Load() ([]Connector, error)
 }
+
+// LoadConnectors loads the connectors of all loaders, sorted by type and name.
+func LoadConnectors(loaders []ConnectorLoader) ([]Connector, error) {
+	var profiles []Connector
+
+	for _, loader := range loaders {
+		loadedProfiles, err := loader.Load()
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, loadedProfiles...)
+	}
+
+	slices.SortFunc(profiles, func(a, b Connector) int {
+		if byType := strings.Compare(a.Type(), b.Type()); byType != 0 {
+			return byType
+		}
+		return strings.Compare(a.Name(), b.Name())
+	})
+
+	return profiles, nil
+}
@@ -1,13 +1,26 @@
 package s3lib
 
 import (
+	"slices"
+	"strconv"
 	"time"
 )
 
 type MemoryBucket struct {
 	objects      []MemoryObject
+	uploads      map[string]*memoryUpload
 	region       string
 	creationDate time.Time
+
+	// versioned buckets keep every version and delete marker in versions,
+	// oldest first. objects only holds the current versions.
+	versioned     bool
+	versions      []MemoryObject
+	nextVersionID int
+
+	// documents holds the policy, lifecycle and CORS settings as indented
+	// JSON.
+	documents map[BucketDocument]string
 }
 
 type MemoryObject struct {
@@ -17,4 +30,158 @@ type MemoryObject struct {
 	etag         string
 	storageClass string
 	data         []byte
+	versionID    string
+	deleteMarker bool
+	contentType  string
+	metadata     map[string]string
+	tags         map[string]string
+
+	cacheControl       string
+	contentDisposition string
+	contentEncoding    string
+	contentLanguage    string
+}
+
+type memoryUpload struct {
+	key   string
+	attrs objectAttributes
+	parts map[int32]memoryPart
+}
+
+type memoryPart struct {
+	etag string
+	data []byte
+}
+
+// nullVersionID is the version id S3 reports for objects of unversioned buckets.
+const nullVersionID = "null"
+
+func (b *MemoryBucket) findObject(key string) (int, bool) {
+	index := slices.IndexFunc(b.objects, func(obj MemoryObject) bool {
+		return obj.key == key
+	})
+	return index, index >= 0
+}
+
+// putObject stores obj, replacing an existing object with the same key. In
+// versioned buckets the previous object is kept as an older version.
+func (b *MemoryBucket) putObject(obj MemoryObject) {
+	if b.versioned {
+		b.nextVersionID++
+		obj.versionID = "v" + strconv.Itoa(b.nextVersionID)
+		b.versions = append(b.versions, obj)
+	}
+
+	b.setCurrent(obj)
+}
+
+func (b *MemoryBucket) setCurrent(obj MemoryObject) {
+	if i, ok := b.findObject(obj.key); ok {
+		b.objects[i] = obj
+		return
+	}
+	b.objects = append(b.objects, obj)
+}
+
+// updateCurrent changes the current version of key in place, without creating
+// a new version.
+func (b *MemoryBucket) updateCurrent(key string, update func(obj *MemoryObject)) bool {
+	i, ok := b.findObject(key)
+	if !ok {
+		return false
+	}
+
+	obj := &b.objects[i]
+	update(obj)
+	for j := range b.versions {
+		if b.versions[j].key == key && b.versions[j].versionID == obj.versionID {
+			b.versions[j] = *obj
+		}
+	}
+	return true
+}
+
+func (b *MemoryBucket) removeCurrent(key string) bool {
+	i, ok := b.findObject(key)
+	if ok {
+		b.objects = append(b.objects[:i], b.objects[i+1:]...)
+	}
+	return ok
+}
+
+// deleteObject removes the current version of key. Versioned buckets get a
+// delete marker instead.
+func (b *MemoryBucket) deleteObject(key string) bool {
+	if !b.removeCurrent(key) {
+		return false
+	}
+
+	if b.versioned {
+		b.nextVersionID++
+		b.versions = append(b.versions, MemoryObject{
+			key:          key,
+			lastModified: time.Now(),
+			versionID:    "v" + strconv.Itoa(b.nextVersionID),
+			deleteMarker: true,
+		})
+	}
+	return true
+}
+
+// objectVersions returns all versions of the bucket, oldest first.
+func (b *MemoryBucket) objectVersions() []MemoryObject {
+	if b.versioned {
+		return b.versions
+	}
+
+	versions := make([]MemoryObject, 0, len(b.objects))
+	for _, obj := range b.objects {
+		obj.versionID = nullVersionID
+		versions = append(versions, obj)
+	}
+	return versions
+}
+
+func (b *MemoryBucket) findVersion(key, versionID string) (MemoryObject, bool) {
+	if versionID == "" {
+		i, ok := b.findObject(key)
+		if !ok {
+			return MemoryObject{}, false
+		}
+		return b.objects[i], true
+	}
+
+	for _, obj := range b.objectVersions() {
+		if obj.key == key && obj.versionID == versionID {
+			return obj, true
+		}
+	}
+	return MemoryObject{}, false
+}
+
+// deleteVersion permanently removes a single version. If it was the latest
+// version, the next older one becomes current.
+func (b *MemoryBucket) deleteVersion(key, versionID string) bool {
+	if !b.versioned {
+		return versionID == nullVersionID && b.removeCurrent(key)
+	}
+
+	i := slices.IndexFunc(b.versions, func(obj MemoryObject) bool {
+		return obj.key == key && obj.versionID == versionID
+	})
+	if i < 0 {
+		return false
+	}
+	b.versions = append(b.versions[:i], b.versions[i+1:]...)
+
+	b.removeCurrent(key)
+	for j := len(b.versions) - 1; j >= 0; j-- {
+		if b.versions[j].key == key {
+			if !b.versions[j].deleteMarker {
+				b.setCurrent(b.versions[j])
+			}
+			break
+		}
+	}
+	return true
 }
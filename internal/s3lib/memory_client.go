@@ -2,10 +2,14 @@ package s3lib
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
-	"os"
+	"maps"
+	"net/url"
 	"slices"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -13,7 +17,9 @@ import (
 )
 
 type MemoryClient struct {
-	buckets map[string]*MemoryBucket
+	mu           sync.Mutex
+	buckets      map[string]*MemoryBucket
+	nextUploadID int
 }
 
 func NewMemoryClient() *MemoryClient {
@@ -31,6 +37,9 @@ func (c *MemoryClient) ConnectionParameters(bucket string) ConnectionParameters
 }
 
 func (c *MemoryClient) ListBuckets(ctx context.Context) Paginator[types.Bucket] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	var bucketList []types.Bucket
 	for name := range c.buckets {
 		bucketList = append(bucketList, types.Bucket{
@@ -46,6 +55,9 @@ func (c *MemoryClient) ListBuckets(ctx context.Context) Paginator[types.Bucket]
 }
 
 func (c *MemoryClient) ListObjects(ctx context.Context, bucket string, prefix string) Paginator[Object] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if memBucket, exists := c.buckets[bucket]; exists {
 		var objects []Object
 		var directories []string
@@ -53,7 +65,8 @@ func (c *MemoryClient) ListObjects(ctx context.Context, bucket string, prefix st
 			if after, ok := strings.CutPrefix(obj.key, prefix); ok {
 				if strings.Contains(after, "/") {
 					// It's a directory
-					dir := strings.SplitN(after, "/", 2)[0] + "/"
+					// common prefixes contain the listed prefix, like in S3
+					dir := prefix + strings.SplitN(after, "/", 2)[0] + "/"
 					directories = append(directories, dir)
 				} else {
 					// It's a file
@@ -84,64 +97,57 @@ func (c *MemoryClient) ListObjects(ctx context.Context, bucket string, prefix st
 }
 
 func (c *MemoryClient) CreateBucket(ctx context.Context, bucket, region string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if _, exists := c.buckets[bucket]; exists {
 		return errors.New("bucket already exists")
 	}
-	c.buckets[bucket] = &MemoryBucket{}
+	c.buckets[bucket] = &MemoryBucket{
+		region:       region,
+		creationDate: time.Now(),
+	}
 	return nil
 }
 
-func (c *MemoryClient) UploadFile(ctx context.Context, bucket, key, filePath string) error {
-	if memBucket, exists := c.buckets[bucket]; exists {
-		data, err := os.ReadFile(filePath)
-		if err != nil {
-			return err
-		}
+func (c *MemoryClient) UploadFile(ctx context.Context, bucket, key, filePath string, optFns ...func(*TransferOptions)) error {
+	return uploadFile(ctx, c, bucket, key, filePath, transferOptions(optFns))
+}
 
-		memBucket.objects = append(memBucket.objects, MemoryObject{
-			key:          key,
-			data:         data,
-			size:         int64(len(data)), // Simulated size
-			lastModified: time.Now(),
-			etag:         "dummy-etag",
-			storageClass: "STANDARD",
-		})
+func (c *MemoryClient) DownloadFile(ctx context.Context, bucket, key, filePath string, optFns ...func(*TransferOptions)) error {
+	return downloadFile(ctx, c, bucket, key, filePath, transferOptions(optFns))
+}
 
-		return nil
-	}
-	return errors.New("bucket not found")
+func (c *MemoryClient) ReadObjectRange(ctx context.Context, bucket, key, versionID string, offset, length int64) ([]byte, int64, error) {
+	return readObjectRange(ctx, c, bucket, key, versionID, offset, length)
 }
 
-func (c *MemoryClient) DownloadFile(ctx context.Context, bucket, key, filePath string) error {
-	if memBucket, exists := c.buckets[bucket]; exists {
-		for _, obj := range memBucket.objects {
-			if obj.key == key {
-				if err := os.WriteFile(filePath, obj.data, os.ModePerm); err != nil {
-					return err
-				}
-				return nil
-			}
-		}
-		return errors.New("object not found")
-	}
-	return errors.New("bucket not found")
+func (c *MemoryClient) CopyObject(ctx context.Context, srcBucket, srcKey, bucket, key string, optFns ...func(*TransferOptions)) error {
+	return copyObject(ctx, c, srcBucket, srcKey, bucket, key, transferOptions(optFns))
 }
 
 func (c *MemoryClient) GetObject(ctx context.Context, bucket, key string) (ObjectMetadata, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if memBucket, exists := c.buckets[bucket]; exists {
 		for _, obj := range memBucket.objects {
 			if obj.key == key {
 				return ObjectMetadata{
-					Region:       memBucket.region,
-					LastModified: &obj.lastModified,
-					Size:         &obj.size,
-					Type:         aws.String("image/png"),
-					Key:          obj.key,
-					Bucket:       bucket,
-					Owner:        aws.String("memory-user"),
-					Tags:         map[string]string{"Environment": "Test"},
-					LegalHold:    "OFF",
-					ETag:         aws.String(obj.etag),
+					Region:             memBucket.region,
+					LastModified:       &obj.lastModified,
+					Size:               &obj.size,
+					Type:               aws.String(obj.contentType),
+					CacheControl:       optionalString(obj.cacheControl),
+					ContentDisposition: optionalString(obj.contentDisposition),
+					ContentEncoding:    optionalString(obj.contentEncoding),
+					Key:                obj.key,
+					Bucket:             bucket,
+					Owner:              aws.String("memory-user"),
+					Tags:               obj.tags,
+					Metadata:           obj.metadata,
+					LegalHold:          "OFF",
+					ETag:               aws.String(obj.etag),
 				}, nil
 			}
 		}
@@ -150,7 +156,119 @@ func (c *MemoryClient) GetObject(ctx context.Context, bucket, key string) (Objec
 	return ObjectMetadata{}, errors.New("bucket not found")
 }
 
+func (c *MemoryClient) GetObjectTags(ctx context.Context, bucket, key string) (map[string]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	obj, err := c.findObject(bucket, key, "")
+	if err != nil {
+		return nil, err
+	}
+	tags := maps.Clone(obj.tags)
+	if tags == nil {
+		tags = map[string]string{}
+	}
+	return tags, nil
+}
+
+// PutObjectTags replaces the tags of the current version of key without
+// creating a new version, like S3.
+func (c *MemoryClient) PutObjectTags(ctx context.Context, bucket, key string, tags map[string]string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if memBucket, exists := c.buckets[bucket]; exists {
+		if memBucket.updateCurrent(key, func(obj *MemoryObject) { obj.tags = maps.Clone(tags) }) {
+			return nil
+		}
+		return errors.New("object not found")
+	}
+	return errors.New("bucket not found")
+}
+
+func (c *MemoryClient) SetObjectProperties(ctx context.Context, bucket, key string, props ObjectProperties) error {
+	return setObjectProperties(ctx, c, bucket, key, props)
+}
+
+// GetBucketConfiguration reports the region and versioning of bucket. Memory
+// buckets have no other settings.
+func (c *MemoryClient) GetBucketConfiguration(ctx context.Context, bucket string) ([]BucketSetting, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	memBucket, exists := c.buckets[bucket]
+	if !exists {
+		return nil, errors.New("bucket not found")
+	}
+
+	values := map[string][]string{
+		BucketSettingRegion: {memBucket.region},
+	}
+	if memBucket.versioned {
+		values[BucketSettingVersioning] = []string{"Enabled"}
+	}
+	if policy, ok := memBucket.documents[BucketPolicyDocument]; ok {
+		values[BucketSettingPolicy] = []string{policySummary(policy)}
+	}
+	var lifecycle lifecycleDocument
+	if err := json.Unmarshal([]byte(memBucket.documents[BucketLifecycleDocument]), &lifecycle); err == nil {
+		for _, rule := range lifecycle.Rules {
+			values[BucketSettingLifecycle] = append(values[BucketSettingLifecycle], lifecycleRuleSummary(rule))
+		}
+	}
+	var cors corsDocument
+	if err := json.Unmarshal([]byte(memBucket.documents[BucketCORSDocument]), &cors); err == nil {
+		for _, rule := range cors.CORSRules {
+			values[BucketSettingCORS] = append(values[BucketSettingCORS], corsRuleSummary(rule))
+		}
+	}
+
+	settings := make([]BucketSetting, 0, len(bucketSettingNames))
+	for _, name := range bucketSettingNames {
+		settings = append(settings, BucketSetting{Name: name, Values: values[name]})
+	}
+	return settings, nil
+}
+
+func (c *MemoryClient) GetBucketDocument(ctx context.Context, bucket string, doc BucketDocument) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	memBucket, exists := c.buckets[bucket]
+	if !exists {
+		return "", errors.New("bucket not found")
+	}
+	return memBucket.documents[doc], nil
+}
+
+func (c *MemoryClient) PutBucketDocument(ctx context.Context, bucket string, doc BucketDocument, document string) error {
+	document, err := ParseBucketDocument(doc, []byte(document))
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	memBucket, exists := c.buckets[bucket]
+	if !exists {
+		return errors.New("bucket not found")
+	}
+	if document == "" {
+		delete(memBucket.documents, doc)
+		return nil
+	}
+	if memBucket.documents == nil {
+		memBucket.documents = map[BucketDocument]string{}
+	}
+	memBucket.documents[doc] = document
+	return nil
+}
+
 func (c *MemoryClient) DeleteBucket(ctx context.Context, bucket string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if _, exists := c.buckets[bucket]; exists {
 		delete(c.buckets, bucket)
 		return nil
@@ -159,14 +277,171 @@ func (c *MemoryClient) DeleteBucket(ctx context.Context, bucket string) error {
 }
 
 func (c *MemoryClient) DeleteObject(ctx context.Context, bucket, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if memBucket, exists := c.buckets[bucket]; exists {
-		for i, obj := range memBucket.objects {
-			if obj.key == key {
-				memBucket.objects = append(memBucket.objects[:i], memBucket.objects[i+1:]...)
-				return nil
-			}
+		if memBucket.deleteObject(key) {
+			return nil
 		}
 		return errors.New("object not found")
 	}
 	return errors.New("bucket not found")
 }
+
+func (c *MemoryClient) ListObjectsRecursive(ctx context.Context, bucket string, prefix string) Paginator[Object] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if memBucket, exists := c.buckets[bucket]; exists {
+		var objects []Object
+		for _, obj := range memBucket.objects {
+			if strings.HasPrefix(obj.key, prefix) {
+				objects = append(objects, NewObjectFile(types.Object{
+					Key:          aws.String(obj.key),
+					Size:         aws.Int64(obj.size),
+					LastModified: aws.Time(obj.lastModified),
+					ETag:         aws.String(obj.etag),
+					StorageClass: types.ObjectStorageClass(obj.storageClass),
+				}))
+			}
+		}
+		slices.SortFunc(objects, func(a, b Object) int {
+			return strings.Compare(aws.ToString(a.Object.Key), aws.ToString(b.Object.Key))
+		})
+
+		return &memoryPaginator[Object]{items: objects}
+	}
+	return &memoryPaginator[Object]{
+		err: errors.New("bucket not found"),
+	}
+}
+
+func (c *MemoryClient) ListObjectVersions(ctx context.Context, bucket string, prefix string) Paginator[Object] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if memBucket, exists := c.buckets[bucket]; exists {
+		allVersions := memBucket.objectVersions()
+		var versions []Object
+		var directories []string
+		for i, obj := range allVersions {
+			after, ok := strings.CutPrefix(obj.key, prefix)
+			if !ok {
+				continue
+			}
+			if strings.Contains(after, "/") {
+				directories = append(directories, prefix+strings.SplitN(after, "/", 2)[0]+"/")
+				continue
+			}
+
+			isLatest := !slices.ContainsFunc(allVersions[i+1:], func(other MemoryObject) bool {
+				return other.key == obj.key
+			})
+			if obj.deleteMarker {
+				versions = append(versions, NewObjectDeleteMarker(types.DeleteMarkerEntry{
+					Key:          aws.String(obj.key),
+					LastModified: aws.Time(obj.lastModified),
+					VersionId:    aws.String(obj.versionID),
+					IsLatest:     aws.Bool(isLatest),
+				}))
+			} else {
+				versions = append(versions, NewObjectVersion(types.ObjectVersion{
+					Key:          aws.String(obj.key),
+					Size:         aws.Int64(obj.size),
+					LastModified: aws.Time(obj.lastModified),
+					ETag:         aws.String(obj.etag),
+					StorageClass: types.ObjectVersionStorageClass(obj.storageClass),
+					VersionId:    aws.String(obj.versionID),
+					IsLatest:     aws.Bool(isLatest),
+				}))
+			}
+		}
+
+		// newest first per key, like S3 returns them
+		slices.Reverse(versions)
+		slices.SortStableFunc(versions, func(a, b Object) int {
+			return strings.Compare(aws.ToString(a.Object.Key), aws.ToString(b.Object.Key))
+		})
+
+		slices.Sort(directories)
+		uniqueDirs := slices.Compact(directories)
+		result := make([]Object, 0, len(uniqueDirs)+len(versions))
+		for _, dir := range uniqueDirs {
+			result = append(result, NewObjectDirectory(dir))
+		}
+		result = append(result, versions...)
+
+		return &memoryPaginator[Object]{items: result}
+	}
+	return &memoryPaginator[Object]{
+		err: errors.New("bucket not found"),
+	}
+}
+
+// DeleteObjects deletes keys like S3 does, ignoring keys that do not exist.
+func (c *MemoryClient) DeleteObjects(ctx context.Context, bucket string, keys []string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	memBucket, exists := c.buckets[bucket]
+	if !exists {
+		return errors.New("bucket not found")
+	}
+	for _, key := range keys {
+		memBucket.deleteObject(key)
+	}
+	return nil
+}
+
+func (c *MemoryClient) DeleteObjectVersion(ctx context.Context, bucket, key, versionID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if memBucket, exists := c.buckets[bucket]; exists {
+		if memBucket.deleteVersion(key, versionID) {
+			return nil
+		}
+		return errors.New("version not found")
+	}
+	return errors.New("bucket not found")
+}
+
+// RestoreObjectVersion copies a version over the current version of key.
+func (c *MemoryClient) RestoreObjectVersion(ctx context.Context, bucket, key, versionID string) error {
+	return c.CopyObject(ctx, bucket, key, bucket, key, WithVersion(versionID))
+}
+
+// PresignObject returns a memory:// URL, which is only useful to check what
+// was presigned.
+func (c *MemoryClient) PresignObject(ctx context.Context, bucket, key, versionID string, method PresignMethod, expires time.Duration) (string, error) {
+	if err := validatePresign(method, versionID, expires); err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	memBucket, exists := c.buckets[bucket]
+	if !exists {
+		return "", errors.New("bucket not found")
+	}
+	if _, ok := memBucket.findVersion(key, versionID); method == PresignGet && !ok {
+		return "", errors.New("object not found")
+	}
+
+	query := url.Values{
+		"X-Amz-Expires": {strconv.Itoa(int(expires.Seconds()))},
+		"Method":        {string(method)},
+	}
+	if versionID != "" {
+		query.Set("versionId", versionID)
+	}
+	u := url.URL{
+		Scheme:   "memory",
+		Host:     bucket,
+		Path:     "/" + key,
+		RawQuery: query.Encode(),
+	}
+	return u.String(), nil
+}
@@ -20,15 +20,48 @@ func (f *MemoryClientFactory) WithBucket(bucket string, region string, creationD
 	return f
 }
 
+// WithVersioning enables versioning for an existing bucket. Objects added
+// afterwards keep their previous versions.
+func (f *MemoryClientFactory) WithVersioning(bucket string) *MemoryClientFactory {
+	if memBucket, exists := f.client.buckets[bucket]; exists {
+		memBucket.versioned = true
+	}
+	return f
+}
+
 func (f *MemoryClientFactory) WithObject(bucket, key string, size int64, lastModified time.Time, etag, storageClass string, data []byte) *MemoryClientFactory {
 	if memBucket, exists := f.client.buckets[bucket]; exists {
-		memBucket.objects = append(memBucket.objects, MemoryObject{
+		memBucket.putObject(MemoryObject{
 			key:          key,
 			size:         size,
 			lastModified: lastModified,
 			etag:         etag,
 			storageClass: storageClass,
 			data:         data,
+			contentType:  "image/png",
+			tags:         map[string]string{"Environment": "Test"},
+		})
+	}
+	return f
+}
+
+// WithObjectMetadata replaces the user metadata and tags of the current
+// version of an existing object.
+func (f *MemoryClientFactory) WithObjectMetadata(bucket, key string, metadata, tags map[string]string) *MemoryClientFactory {
+	if memBucket, exists := f.client.buckets[bucket]; exists {
+		memBucket.updateCurrent(key, func(obj *MemoryObject) {
+			obj.metadata, obj.tags = metadata, tags
+		})
+	}
+	return f
+}
+
+// WithContentEncoding sets the Content-Encoding header of the current version
+// of an existing object.
+func (f *MemoryClientFactory) WithContentEncoding(bucket, key, encoding string) *MemoryClientFactory {
+	if memBucket, exists := f.client.buckets[bucket]; exists {
+		memBucket.updateCurrent(key, func(obj *MemoryObject) {
+			obj.contentEncoding = encoding
 		})
 	}
 	return f
@@ -4,6 +4,7 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -92,8 +93,8 @@ func TestMemoryClientListObjectsWithPrefixAndDirectories(t *testing.T) {
 		t.Fatalf("expected 2 items (one dir + one file), got %d", len(items))
 	}
 
-	if !items[0].IsDirectory() || aws.ToString(items[0].Object.Key) != "2024/" {
-		t.Fatalf("expected first item directory 2024/, got kind=%v key=%s", items[0].Kind, aws.ToString(items[0].Object.Key))
+	if !items[0].IsDirectory() || aws.ToString(items[0].Object.Key) != "photos/2024/" {
+		t.Fatalf("expected first item directory photos/2024/, got kind=%v key=%s", items[0].Kind, aws.ToString(items[0].Object.Key))
 	}
 	if !items[1].IsFile() || aws.ToString(items[1].Object.Key) != "photos/root.txt" {
 		t.Fatalf("expected second item file photos/root.txt, got kind=%v key=%s", items[1].Kind, aws.ToString(items[1].Object.Key))
@@ -154,3 +155,133 @@ func TestMemoryClientGetObjectAndConnectionParameters(t *testing.T) {
 		t.Fatalf("expected nil region for memory client, got %v", aws.ToString(params.Region))
 	}
 }
+
+func TestMemoryClientVersionedBucket(t *testing.T) {
+	now := time.Now().UTC()
+	client := NewMemoryClientFactory().
+		WithBucket("bucket", "eu-west-1", now).
+		WithVersioning("bucket").
+		WithObject("bucket", "doc.txt", 2, now.Add(-2*time.Hour), "e1", "STANDARD", []byte("v1")).
+		WithObject("bucket", "doc.txt", 2, now.Add(-time.Hour), "e2", "STANDARD", []byte("v2")).
+		Build()
+	ctx := context.Background()
+
+	if err := client.DeleteObject(ctx, "bucket", "doc.txt"); err != nil {
+		t.Fatalf("delete object failed: %v", err)
+	}
+	if _, err := client.GetObject(ctx, "bucket", "doc.txt"); err == nil {
+		t.Fatal("expected deleted object to be gone")
+	}
+
+	versions, err := client.ListObjectVersions(ctx, "bucket", "").NextPage(ctx)
+	if err != nil {
+		t.Fatalf("list object versions failed: %v", err)
+	}
+	if len(versions) != 3 {
+		t.Fatalf("expected 3 versions, got %d", len(versions))
+	}
+	if !versions[0].IsDeleteMarker() || !versions[0].IsLatest {
+		t.Fatalf("expected latest delete marker first, got %#v", versions[0])
+	}
+	if versions[1].VersionID != "v2" || versions[2].VersionID != "v1" || versions[1].IsLatest {
+		t.Fatalf("expected older versions v2 and v1, got %s and %s", versions[1].VersionID, versions[2].VersionID)
+	}
+
+	dstPath := filepath.Join(t.TempDir(), "v1.txt")
+	if err := client.DownloadFile(ctx, "bucket", "doc.txt", dstPath, WithVersion("v1")); err != nil {
+		t.Fatalf("download version failed: %v", err)
+	}
+	if got, _ := os.ReadFile(dstPath); string(got) != "v1" {
+		t.Fatalf("expected version content v1, got %q", got)
+	}
+
+	if err := client.RestoreObjectVersion(ctx, "bucket", "doc.txt", "v1"); err != nil {
+		t.Fatalf("restore version failed: %v", err)
+	}
+	if err := client.DownloadFile(ctx, "bucket", "doc.txt", dstPath); err != nil {
+		t.Fatalf("download restored object failed: %v", err)
+	}
+	if got, _ := os.ReadFile(dstPath); string(got) != "v1" {
+		t.Fatalf("expected restored content v1, got %q", got)
+	}
+
+	restored, err := client.ListObjectVersions(ctx, "bucket", "").NextPage(ctx)
+	if err != nil {
+		t.Fatalf("list object versions failed: %v", err)
+	}
+	if err := client.DeleteObjectVersion(ctx, "bucket", "doc.txt", restored[0].VersionID); err != nil {
+		t.Fatalf("delete version failed: %v", err)
+	}
+	if _, err := client.GetObject(ctx, "bucket", "doc.txt"); err == nil {
+		t.Fatal("expected delete marker to be current again")
+	}
+	if err := client.DeleteObjectVersion(ctx, "bucket", "doc.txt", "missing"); err == nil {
+		t.Fatal("expected error for unknown version")
+	}
+}
+
+func TestMemoryClientUnversionedBucketListsNullVersions(t *testing.T) {
+	now := time.Now().UTC()
+	client := NewMemoryClientFactory().
+		WithBucket("bucket", "eu-west-1", now).
+		WithObject("bucket", "dir/a.txt", 1, now, "e1", "STANDARD", []byte("a")).
+		WithObject("bucket", "b.txt", 1, now, "e2", "STANDARD", []byte("b")).
+		Build()
+	ctx := context.Background()
+
+	versions, err := client.ListObjectVersions(ctx, "bucket", "").NextPage(ctx)
+	if err != nil {
+		t.Fatalf("list object versions failed: %v", err)
+	}
+	if len(versions) != 2 || !versions[0].IsDirectory() {
+		t.Fatalf("expected one directory and one version, got %#v", versions)
+	}
+	if versions[1].VersionID != "null" || !versions[1].IsLatest {
+		t.Fatalf("expected latest null version, got %#v", versions[1])
+	}
+
+	if err := client.DeleteObjectVersion(ctx, "bucket", "b.txt", "null"); err != nil {
+		t.Fatalf("delete null version failed: %v", err)
+	}
+	if _, err := client.GetObject(ctx, "bucket", "b.txt"); err == nil {
+		t.Fatal("expected object to be deleted")
+	}
+}
+
+func TestMemoryClientListObjectsRecursiveAndDeleteObjects(t *testing.T) {
+	now := time.Now().UTC()
+	client := NewMemoryClientFactory().
+		WithBucket("bucket", "eu-west-1", now).
+		WithObject("bucket", "photos/2024/b.jpg", 1, now, "e1", "STANDARD", []byte("b")).
+		WithObject("bucket", "photos/2024/a.jpg", 1, now, "e2", "STANDARD", []byte("a")).
+		WithObject("bucket", "photos/root.txt", 1, now, "e3", "STANDARD", []byte("r")).
+		WithObject("bucket", "videos/clip.mp4", 1, now, "e4", "STANDARD", []byte("c")).
+		Build()
+	ctx := context.Background()
+
+	items, err := client.ListObjectsRecursive(ctx, "bucket", "photos/").NextPage(ctx)
+	if err != nil {
+		t.Fatalf("list objects recursive failed: %v", err)
+	}
+	var keys []string
+	for _, item := range items {
+		keys = append(keys, aws.ToString(item.Object.Key))
+	}
+	if strings.Join(keys, ",") != "photos/2024/a.jpg,photos/2024/b.jpg,photos/root.txt" {
+		t.Fatalf("unexpected keys %v", keys)
+	}
+
+	if err := client.DeleteObjects(ctx, "bucket", append(keys, "photos/missing.txt")); err != nil {
+		t.Fatalf("delete objects failed: %v", err)
+	}
+	items, err = client.ListObjectsRecursive(ctx, "bucket", "").NextPage(ctx)
+	if err != nil {
+		t.Fatalf("list objects recursive failed: %v", err)
+	}
+	if len(items) != 1 || aws.ToString(items[0].Object.Key) != "videos/clip.mp4" {
+		t.Fatalf("expected only videos/clip.mp4 to remain, got %v", items)
+	}
+	if err := client.DeleteObjects(ctx, "missing", keys); err == nil {
+		t.Fatal("expected error for missing bucket")
+	}
+}
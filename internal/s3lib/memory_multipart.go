@@ -0,0 +1,341 @@
+package s3lib
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"strconv"
+	"time"
+)
+
+func (c *MemoryClient) putObject(ctx context.Context, bucket, key string, body io.ReadSeeker, attrs objectAttributes, ifMatch string) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	memBucket, exists := c.buckets[bucket]
+	if !exists {
+		return errors.New("bucket not found")
+	}
+	if err := c.checkIfMatch(bucket, key, ifMatch); err != nil {
+		return err
+	}
+
+	obj := MemoryObject{
+		key:          key,
+		data:         data,
+		size:         int64(len(data)),
+		lastModified: time.Now(),
+		etag:         md5Hex(data),
+		storageClass: "STANDARD",
+	}
+	obj.setAttributes(attrs)
+	memBucket.putObject(obj)
+	return nil
+}
+
+func (c *MemoryClient) createMultipartUpload(ctx context.Context, bucket, key string, attrs objectAttributes) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	memBucket, exists := c.buckets[bucket]
+	if !exists {
+		return "", errors.New("bucket not found")
+	}
+
+	c.nextUploadID++
+	uploadID := "upload-" + strconv.Itoa(c.nextUploadID)
+	if memBucket.uploads == nil {
+		memBucket.uploads = map[string]*memoryUpload{}
+	}
+	memBucket.uploads[uploadID] = &memoryUpload{
+		key:   key,
+		attrs: attrs,
+		parts: map[int32]memoryPart{},
+	}
+	return uploadID, nil
+}
+
+func (c *MemoryClient) uploadPart(ctx context.Context, bucket, key, uploadID string, partNumber int32, body io.ReadSeeker) (completedPart, error) {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return completedPart{}, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	upload, err := c.findUpload(bucket, key, uploadID)
+	if err != nil {
+		return completedPart{}, err
+	}
+	if partNumber < 1 || partNumber > MaxParts {
+		return completedPart{}, fmt.Errorf("invalid part number %d", partNumber)
+	}
+
+	part := memoryPart{etag: md5Hex(data), data: data}
+	upload.parts[partNumber] = part
+	return completedPart{partNumber: partNumber, etag: part.etag}, nil
+}
+
+func (c *MemoryClient) completeMultipartUpload(ctx context.Context, bucket, key, uploadID string, parts []completedPart, ifMatch string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	upload, err := c.findUpload(bucket, key, uploadID)
+	if err != nil {
+		return err
+	}
+	if len(parts) == 0 {
+		return errors.New("multipart upload has no parts")
+	}
+	if err := c.checkIfMatch(bucket, key, ifMatch); err != nil {
+		return err
+	}
+
+	var data bytes.Buffer
+	var partHashes []byte
+	for i, p := range parts {
+		if i > 0 && p.partNumber <= parts[i-1].partNumber {
+			return errors.New("invalid part order")
+		}
+		part, ok := upload.parts[p.partNumber]
+		if !ok || part.etag != p.etag {
+			return fmt.Errorf("invalid part %d", p.partNumber)
+		}
+		if i < len(parts)-1 && int64(len(part.data)) < MinPartSize {
+			return fmt.Errorf("part %d is smaller than the minimum allowed size", p.partNumber)
+		}
+
+		data.Write(part.data)
+		hash, _ := hex.DecodeString(part.etag)
+		partHashes = append(partHashes, hash...)
+	}
+
+	memBucket := c.buckets[bucket]
+	delete(memBucket.uploads, uploadID)
+	obj := MemoryObject{
+		key:          key,
+		data:         data.Bytes(),
+		size:         int64(data.Len()),
+		lastModified: time.Now(),
+		etag:         md5Hex(partHashes) + "-" + strconv.Itoa(len(parts)),
+		storageClass: "STANDARD",
+	}
+	obj.setAttributes(upload.attrs)
+	memBucket.putObject(obj)
+	return nil
+}
+
+func (c *MemoryClient) copyObject(ctx context.Context, src objectSource, bucket, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	obj, err := c.findSource(src)
+	if err != nil {
+		return err
+	}
+	memBucket, exists := c.buckets[bucket]
+	if !exists {
+		return errors.New("bucket not found")
+	}
+
+	obj.key = key
+	obj.lastModified = time.Now()
+	obj.metadata = maps.Clone(obj.metadata)
+	obj.tags = maps.Clone(obj.tags)
+	memBucket.putObject(obj)
+	return nil
+}
+
+func (c *MemoryClient) copyObjectReplacing(ctx context.Context, src objectSource, bucket, key string, attrs objectAttributes) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	obj, err := c.findSource(src)
+	if err != nil {
+		return err
+	}
+	memBucket, exists := c.buckets[bucket]
+	if !exists {
+		return errors.New("bucket not found")
+	}
+
+	attrs.tags = obj.tags
+	obj.key = key
+	obj.lastModified = time.Now()
+	obj.setAttributes(attrs)
+	memBucket.putObject(obj)
+	return nil
+}
+
+func (c *MemoryClient) uploadPartCopy(ctx context.Context, bucket, key, uploadID string, partNumber int32, src objectSource, start, end int64) (completedPart, error) {
+	c.mu.Lock()
+	obj, err := c.findSource(src)
+	c.mu.Unlock()
+	if err != nil {
+		return completedPart{}, err
+	}
+	if start < 0 || start > end || end >= int64(len(obj.data)) {
+		return completedPart{}, errors.New("invalid range")
+	}
+
+	return c.uploadPart(ctx, bucket, key, uploadID, partNumber, bytes.NewReader(obj.data[start:end+1]))
+}
+
+func (c *MemoryClient) objectAttributes(ctx context.Context, bucket, key, versionID string) (objectAttributes, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	obj, err := c.findObject(bucket, key, versionID)
+	if err != nil {
+		return objectAttributes{}, err
+	}
+	return obj.attributes(), nil
+}
+
+func (c *MemoryClient) abortMultipartUpload(ctx context.Context, bucket, key, uploadID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := c.findUpload(bucket, key, uploadID); err != nil {
+		return err
+	}
+	delete(c.buckets[bucket].uploads, uploadID)
+	return nil
+}
+
+func (c *MemoryClient) headObject(ctx context.Context, bucket, key, versionID string) (int64, string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	obj, err := c.findObject(bucket, key, versionID)
+	if err != nil {
+		return 0, "", err
+	}
+	return int64(len(obj.data)), obj.etag, nil
+}
+
+func (c *MemoryClient) getObjectRange(ctx context.Context, bucket, key, versionID, etag string, start, end int64) (io.ReadCloser, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	obj, err := c.findObject(bucket, key, versionID)
+	if err != nil {
+		return nil, err
+	}
+	if etag != "" && etag != obj.etag {
+		return nil, ErrPreconditionFailed
+	}
+	if start < 0 || start > end || start >= int64(len(obj.data)) {
+		return nil, errors.New("invalid range")
+	}
+
+	end = min(end, int64(len(obj.data))-1)
+	return io.NopCloser(bytes.NewReader(obj.data[start : end+1])), nil
+}
+
+func (c *MemoryClient) findObject(bucket, key, versionID string) (MemoryObject, error) {
+	memBucket, exists := c.buckets[bucket]
+	if !exists {
+		return MemoryObject{}, errors.New("bucket not found")
+	}
+	obj, ok := memBucket.findVersion(key, versionID)
+	if !ok {
+		return MemoryObject{}, errObjectNotFound
+	}
+	if obj.deleteMarker {
+		// like S3, a deleted object is not found unless a version is asked for
+		if versionID == "" {
+			return MemoryObject{}, errObjectNotFound
+		}
+		return MemoryObject{}, errors.New("version is a delete marker")
+	}
+	return obj, nil
+}
+
+// errObjectNotFound is returned for keys without an object.
+var errObjectNotFound = errors.New("object not found")
+
+// checkIfMatch fails like S3 if ifMatch is set and the current object of key
+// does not have it as ETag.
+func (c *MemoryClient) checkIfMatch(bucket, key, ifMatch string) error {
+	if ifMatch == "" {
+		return nil
+	}
+	obj, err := c.findObject(bucket, key, "")
+	if err != nil || obj.etag != ifMatch {
+		return fmt.Errorf("%w: %s was changed", ErrPreconditionFailed, key)
+	}
+	return nil
+}
+
+// findSource returns the object of src, failing like S3 if its etag changed.
+func (c *MemoryClient) findSource(src objectSource) (MemoryObject, error) {
+	obj, err := c.findObject(src.bucket, src.key, src.versionID)
+	if err != nil {
+		return MemoryObject{}, err
+	}
+	if src.etag != "" && src.etag != obj.etag {
+		return MemoryObject{}, ErrPreconditionFailed
+	}
+	return obj, nil
+}
+
+func (c *MemoryClient) findUpload(bucket, key, uploadID string) (*memoryUpload, error) {
+	memBucket, exists := c.buckets[bucket]
+	if !exists {
+		return nil, errors.New("bucket not found")
+	}
+	upload, ok := memBucket.uploads[uploadID]
+	if !ok || upload.key != key {
+		return nil, errors.New("no such upload")
+	}
+	return upload, nil
+}
+
+// setAttributes sets the headers, metadata and tags of obj to copies of attrs.
+func (obj *MemoryObject) setAttributes(attrs objectAttributes) {
+	obj.contentType = attrs.contentTypeOrDefault()
+	obj.cacheControl = attrs.cacheControl
+	obj.contentDisposition = attrs.contentDisposition
+	obj.contentEncoding = attrs.contentEncoding
+	obj.contentLanguage = attrs.contentLanguage
+	obj.metadata = maps.Clone(attrs.metadata)
+	obj.tags = maps.Clone(attrs.tags)
+}
+
+func (obj MemoryObject) attributes() objectAttributes {
+	return objectAttributes{
+		contentType:        obj.contentType,
+		cacheControl:       obj.cacheControl,
+		contentDisposition: obj.contentDisposition,
+		contentEncoding:    obj.contentEncoding,
+		contentLanguage:    obj.contentLanguage,
+		metadata:           maps.Clone(obj.metadata),
+		tags:               maps.Clone(obj.tags),
+	}
+}
+
+// contentTypeOrDefault returns the content type S3 assigns to objects
+// uploaded without one.
+func (a objectAttributes) contentTypeOrDefault() string {
+	if a.contentType == "" {
+		return "binary/octet-stream"
+	}
+	return a.contentType
+}
+
+func md5Hex(data []byte) string {
+	hash := md5.Sum(data)
+	return hex.EncodeToString(hash[:])
+}
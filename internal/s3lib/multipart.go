@@ -0,0 +1,607 @@
+package s3lib
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sync"
+	"sync/atomic"
+
+	"github.com/aws/smithy-go"
+)
+
+const (
+	// MinPartSize is the smallest size S3 accepts for every part but the last one.
+	MinPartSize int64 = 5 * 1024 * 1024
+	// MaxParts is the maximum number of parts of a single multipart upload.
+	MaxParts = 10000
+
+	DefaultPartSize    int64 = 8 * 1024 * 1024
+	DefaultConcurrency       = 4
+)
+
+type TransferOptions struct {
+	// PartSize is the size of each chunk uploaded or downloaded. Objects not
+	// larger than one part are transferred with a single request.
+	PartSize int64
+	// Concurrency is the number of parts transferred in parallel.
+	Concurrency int
+	// Progress is called with the number of bytes transferred so far and the
+	// object size. It may be called concurrently from several goroutines.
+	Progress func(transferred, total int64)
+	// VersionID selects the version to download or copy. It is empty for
+	// the current version and ignored by uploads.
+	VersionID string
+	// KeepAttributes makes uploads keep the headers, user metadata and tags
+	// of the object they replace, like its Content-Encoding.
+	KeepAttributes bool
+	// AttributesFrom is the key in the same bucket whose attributes uploads
+	// with KeepAttributes keep instead of those of the object they replace.
+	AttributesFrom string
+	// IfMatch makes uploads fail with ErrPreconditionFailed unless the object
+	// they replace still has this ETag.
+	IfMatch string
+	// DownloadedETag receives the ETag of the downloaded object if it is not
+	// nil.
+	DownloadedETag *string
+}
+
+// ErrPreconditionFailed is returned by uploads with IfMatch set if the object
+// was changed or deleted in the meantime.
+var ErrPreconditionFailed = errors.New("precondition failed")
+
+// DefaultTransferOptions returns the default part size and concurrency.
+func DefaultTransferOptions() TransferOptions {
+	return TransferOptions{
+		PartSize:    DefaultPartSize,
+		Concurrency: DefaultConcurrency,
+	}
+}
+
+// WithPartSize returns a transfer option setting the part size. Sizes not
+// above 0 keep the default.
+func WithPartSize(size int64) func(*TransferOptions) {
+	return func(o *TransferOptions) {
+		if size > 0 {
+			o.PartSize = size
+		}
+	}
+}
+
+// WithConcurrency returns a transfer option setting the number of parts
+// transferred in parallel. Numbers not above 0 keep the default.
+func WithConcurrency(concurrency int) func(*TransferOptions) {
+	return func(o *TransferOptions) {
+		if concurrency > 0 {
+			o.Concurrency = concurrency
+		}
+	}
+}
+
+// WithProgress returns a transfer option setting the progress callback.
+func WithProgress(progress func(transferred, total int64)) func(*TransferOptions) {
+	return func(o *TransferOptions) {
+		o.Progress = progress
+	}
+}
+
+// WithVersion returns a transfer option selecting the object version to
+// download or copy.
+func WithVersion(versionID string) func(*TransferOptions) {
+	return func(o *TransferOptions) {
+		o.VersionID = versionID
+	}
+}
+
+// WithKeepAttributes returns a transfer option making an upload keep the
+// headers, user metadata and tags of the object it replaces.
+func WithKeepAttributes() func(*TransferOptions) {
+	return func(o *TransferOptions) {
+		o.KeepAttributes = true
+	}
+}
+
+// WithAttributesFrom returns a transfer option making an upload keep the
+// headers, user metadata and tags of the object key in the same bucket, like
+// for a copy of an edited object under another key.
+func WithAttributesFrom(key string) func(*TransferOptions) {
+	return func(o *TransferOptions) {
+		o.KeepAttributes = true
+		o.AttributesFrom = key
+	}
+}
+
+// WithIfMatch returns a transfer option making an upload only replace the
+// object if it still has etag.
+func WithIfMatch(etag string) func(*TransferOptions) {
+	return func(o *TransferOptions) {
+		o.IfMatch = etag
+	}
+}
+
+// WithDownloadedETag returns a transfer option storing the ETag of the
+// downloaded object in etag.
+func WithDownloadedETag(etag *string) func(*TransferOptions) {
+	return func(o *TransferOptions) {
+		o.DownloadedETag = etag
+	}
+}
+
+func transferOptions(optFns []func(*TransferOptions)) TransferOptions {
+	opts := DefaultTransferOptions()
+	for _, fn := range optFns {
+		fn(&opts)
+	}
+
+	if opts.PartSize < MinPartSize {
+		opts.PartSize = MinPartSize
+	}
+	if opts.Concurrency < 1 {
+		opts.Concurrency = 1
+	}
+	return opts
+}
+
+// partSizeFor grows the part size until size fits into MaxParts parts.
+func (o TransferOptions) partSizeFor(size int64) int64 {
+	partSize := o.PartSize
+	if minSize := (size + MaxParts - 1) / MaxParts; partSize < minSize {
+		partSize = minSize
+	}
+	return partSize
+}
+
+// MaxCopyObjectSize is the largest object S3 copies with a single request.
+const MaxCopyObjectSize = 5 * 1024 * 1024 * 1024
+
+type completedPart struct {
+	partNumber    int32
+	etag          string
+	checksumCRC32 string
+}
+
+// objectSource addresses the object or object version a copy reads from.
+// A non-empty etag makes the copy fail if the object changed.
+type objectSource struct {
+	bucket    string
+	key       string
+	versionID string
+	etag      string
+}
+
+// objectAttributes are the attributes of an object that copies in parts have
+// to set on the new object themselves.
+type objectAttributes struct {
+	contentType        string
+	cacheControl       string
+	contentDisposition string
+	contentEncoding    string
+	contentLanguage    string
+	metadata           map[string]string
+	tags               map[string]string
+}
+
+// multipartAPI is the set of primitive operations the chunked transfers are
+// built on. It is implemented by SdkClient and MemoryClient.
+type multipartAPI interface {
+	// putObject and completeMultipartUpload only replace an object with the
+	// ETag ifMatch if it is not empty.
+	putObject(ctx context.Context, bucket, key string, body io.ReadSeeker, attrs objectAttributes, ifMatch string) error
+	createMultipartUpload(ctx context.Context, bucket, key string, attrs objectAttributes) (string, error)
+	uploadPart(ctx context.Context, bucket, key, uploadID string, partNumber int32, body io.ReadSeeker) (completedPart, error)
+	// copyObject copies src with a single request, keeping its metadata and tags.
+	copyObject(ctx context.Context, src objectSource, bucket, key string) error
+	// copyObjectReplacing copies src with a single request, replacing its
+	// headers and metadata by attrs. The tags of src are kept.
+	copyObjectReplacing(ctx context.Context, src objectSource, bucket, key string, attrs objectAttributes) error
+	uploadPartCopy(ctx context.Context, bucket, key, uploadID string, partNumber int32, src objectSource, start, end int64) (completedPart, error)
+	objectAttributes(ctx context.Context, bucket, key, versionID string) (objectAttributes, error)
+	completeMultipartUpload(ctx context.Context, bucket, key, uploadID string, parts []completedPart, ifMatch string) error
+	abortMultipartUpload(ctx context.Context, bucket, key, uploadID string) error
+	headObject(ctx context.Context, bucket, key, versionID string) (size int64, etag string, err error)
+	getObjectRange(ctx context.Context, bucket, key, versionID, etag string, start, end int64) (io.ReadCloser, error)
+}
+
+func uploadFile(ctx context.Context, api multipartAPI, bucket, key, filePath string, opts TransferOptions) error {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	if opts.IfMatch != "" {
+		// providers that ignore If-Match still fail here, unless the object
+		// is changed during the upload
+		_, etag, err := api.headObject(ctx, bucket, key, "")
+		if err != nil && !isNotFound(err) {
+			return err
+		}
+		if err != nil || etag != opts.IfMatch {
+			return fmt.Errorf("%w: %s was changed", ErrPreconditionFailed, key)
+		}
+	}
+
+	var attrs objectAttributes
+	if opts.KeepAttributes {
+		// a new object has no attributes to keep
+		attrs, err = api.objectAttributes(ctx, bucket, cmp.Or(opts.AttributesFrom, key), "")
+		if err != nil && !isNotFound(err) {
+			return err
+		}
+	}
+
+	size := info.Size()
+	progress := newProgressCounter(size, opts.Progress)
+	partSize := opts.partSizeFor(size)
+	if size <= partSize {
+		err = api.putObject(ctx, bucket, key, f, attrs, opts.IfMatch)
+		if err != nil {
+			return err
+		}
+		progress.add(size)
+		return nil
+	}
+
+	uploadID, err := api.createMultipartUpload(ctx, bucket, key, attrs)
+	if err != nil {
+		return err
+	}
+
+	partCount := int((size + partSize - 1) / partSize)
+	parts := make([]completedPart, partCount)
+	err = forEachPart(ctx, opts.Concurrency, partCount, func(ctx context.Context, i int) error {
+		offset := int64(i) * partSize
+		length := min(partSize, size-offset)
+		body := io.NewSectionReader(f, offset, length)
+		part, err := api.uploadPart(ctx, bucket, key, uploadID, int32(i+1), body)
+		if err != nil {
+			return err
+		}
+		parts[i] = part
+		// parts are counted once they are done, the sdk may read a body more than once
+		progress.add(length)
+		return nil
+	})
+	if err == nil {
+		err = api.completeMultipartUpload(ctx, bucket, key, uploadID, parts, opts.IfMatch)
+	}
+	if err != nil {
+		// the context may already be cancelled, the abort has to go through anyway
+		abortErr := api.abortMultipartUpload(context.WithoutCancel(ctx), bucket, key, uploadID)
+		return errors.Join(err, abortErr)
+	}
+
+	return nil
+}
+
+// isNotFound reports whether err means that an object does not exist.
+func isNotFound(err error) bool {
+	if errors.Is(err, errObjectNotFound) {
+		return true
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "NoSuchKey", "NotFound":
+			return true
+		}
+	}
+	return false
+}
+
+func downloadFile(ctx context.Context, api multipartAPI, bucket, key, filePath string, opts TransferOptions) error {
+	size, etag, err := api.headObject(ctx, bucket, key, opts.VersionID)
+	if err != nil {
+		return err
+	}
+	if opts.DownloadedETag != nil {
+		*opts.DownloadedETag = etag
+	}
+
+	err = os.MkdirAll(path.Dir(filePath), 0755)
+	if err != nil {
+		return err
+	}
+
+	// the parts go to a temporary file next to filePath, which replaces it
+	// only once the download is complete
+	file, err := os.CreateTemp(path.Dir(filePath), "."+path.Base(filePath)+".*.part")
+	if err != nil {
+		return err
+	}
+
+	progress := newProgressCounter(size, opts.Progress)
+	partSize := opts.partSizeFor(size)
+	partCount := int((size + partSize - 1) / partSize)
+	err = forEachPart(ctx, opts.Concurrency, partCount, func(ctx context.Context, i int) error {
+		start := int64(i) * partSize
+		end := min(start+partSize, size) - 1
+
+		body, err := api.getObjectRange(ctx, bucket, key, opts.VersionID, etag, start, end)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = body.Close()
+		}()
+
+		n, err := io.Copy(progress.writer(io.NewOffsetWriter(file, start)), body)
+		if err != nil {
+			return err
+		}
+		if n != end-start+1 {
+			return io.ErrUnexpectedEOF
+		}
+		return nil
+	})
+
+	closeErr := file.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(file.Name(), downloadMode(filePath))
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), filePath)
+	}
+	if err != nil {
+		_ = os.Remove(file.Name())
+		return err
+	}
+
+	return nil
+}
+
+// downloadMode returns the permissions of filePath, so that downloads
+// replacing it keep them, or 0644 if it does not exist yet.
+func downloadMode(filePath string) os.FileMode {
+	if info, err := os.Stat(filePath); err == nil {
+		return info.Mode().Perm()
+	}
+	return 0644
+}
+
+// copyObject copies an object on the server side. Objects larger than one part
+// are copied in parts concurrently.
+func copyObject(ctx context.Context, api multipartAPI, srcBucket, srcKey, bucket, key string, opts TransferOptions) error {
+	size, etag, err := api.headObject(ctx, srcBucket, srcKey, opts.VersionID)
+	if err != nil {
+		return err
+	}
+
+	src := objectSource{bucket: srcBucket, key: srcKey, versionID: opts.VersionID, etag: etag}
+	progress := newProgressCounter(size, opts.Progress)
+	partSize := min(opts.partSizeFor(size), MaxCopyObjectSize)
+	if size <= partSize {
+		err = api.copyObject(ctx, src, bucket, key)
+		if err != nil {
+			return err
+		}
+		progress.add(size)
+		return nil
+	}
+
+	attrs, err := api.objectAttributes(ctx, srcBucket, srcKey, opts.VersionID)
+	if err != nil {
+		return err
+	}
+	return copyParts(ctx, api, src, size, bucket, key, attrs, opts, progress)
+}
+
+// copyParts copies src of the given size in parts into a new object with the
+// attributes attrs.
+func copyParts(ctx context.Context, api multipartAPI, src objectSource, size int64, bucket, key string, attrs objectAttributes, opts TransferOptions, progress *progressCounter) error {
+	uploadID, err := api.createMultipartUpload(ctx, bucket, key, attrs)
+	if err != nil {
+		return err
+	}
+
+	partSize := min(opts.partSizeFor(size), MaxCopyObjectSize)
+	partCount := int((size + partSize - 1) / partSize)
+	parts := make([]completedPart, partCount)
+	err = forEachPart(ctx, opts.Concurrency, partCount, func(ctx context.Context, i int) error {
+		start := int64(i) * partSize
+		end := min(start+partSize, size) - 1
+		part, err := api.uploadPartCopy(ctx, bucket, key, uploadID, int32(i+1), src, start, end)
+		if err != nil {
+			return err
+		}
+		parts[i] = part
+		progress.add(end - start + 1)
+		return nil
+	})
+	if err == nil {
+		err = api.completeMultipartUpload(ctx, bucket, key, uploadID, parts, "")
+	}
+	if err != nil {
+		abortErr := api.abortMultipartUpload(context.WithoutCancel(ctx), bucket, key, uploadID)
+		return errors.Join(err, abortErr)
+	}
+
+	return nil
+}
+
+// TransferObject copies an object from the client src to the client dst,
+// keeping its metadata and tags. Copies within one client are done on the
+// server side, copies between clients stream the object through this process
+// part by part.
+func TransferObject(ctx context.Context, src Client, srcBucket, srcKey string, dst Client, bucket, key string, optFns ...func(*TransferOptions)) error {
+	srcAPI, srcOK := src.(multipartAPI)
+	dstAPI, dstOK := dst.(multipartAPI)
+	if !srcOK || !dstOK {
+		return fmt.Errorf("copying objects from %T to %T is not supported", src, dst)
+	}
+	if srcAPI == dstAPI {
+		return src.CopyObject(ctx, srcBucket, srcKey, bucket, key, optFns...)
+	}
+	return streamObject(ctx, srcAPI, srcBucket, srcKey, dstAPI, bucket, key, transferOptions(optFns))
+}
+
+// streamObject copies an object by downloading it from src and uploading it to
+// dst. At most one part per concurrent transfer is held in memory.
+func streamObject(ctx context.Context, src multipartAPI, srcBucket, srcKey string, dst multipartAPI, bucket, key string, opts TransferOptions) error {
+	size, etag, err := src.headObject(ctx, srcBucket, srcKey, opts.VersionID)
+	if err != nil {
+		return err
+	}
+	attrs, err := src.objectAttributes(ctx, srcBucket, srcKey, opts.VersionID)
+	if err != nil {
+		return err
+	}
+
+	readPart := func(ctx context.Context, start, end int64) (*bytes.Reader, error) {
+		if end < start {
+			return bytes.NewReader(nil), nil
+		}
+		body, err := src.getObjectRange(ctx, srcBucket, srcKey, opts.VersionID, etag, start, end)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			_ = body.Close()
+		}()
+
+		data, err := io.ReadAll(body)
+		if err != nil {
+			return nil, err
+		}
+		if int64(len(data)) != end-start+1 {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return bytes.NewReader(data), nil
+	}
+
+	progress := newProgressCounter(size, opts.Progress)
+	partSize := opts.partSizeFor(size)
+	if size <= partSize {
+		body, err := readPart(ctx, 0, size-1)
+		if err != nil {
+			return err
+		}
+		err = dst.putObject(ctx, bucket, key, body, attrs, "")
+		if err != nil {
+			return err
+		}
+		progress.add(size)
+		return nil
+	}
+
+	uploadID, err := dst.createMultipartUpload(ctx, bucket, key, attrs)
+	if err != nil {
+		return err
+	}
+
+	partCount := int((size + partSize - 1) / partSize)
+	parts := make([]completedPart, partCount)
+	err = forEachPart(ctx, opts.Concurrency, partCount, func(ctx context.Context, i int) error {
+		start := int64(i) * partSize
+		end := min(start+partSize, size) - 1
+		body, err := readPart(ctx, start, end)
+		if err != nil {
+			return err
+		}
+		part, err := dst.uploadPart(ctx, bucket, key, uploadID, int32(i+1), body)
+		if err != nil {
+			return err
+		}
+		parts[i] = part
+		progress.add(end - start + 1)
+		return nil
+	})
+	if err == nil {
+		err = dst.completeMultipartUpload(ctx, bucket, key, uploadID, parts, "")
+	}
+	if err != nil {
+		abortErr := dst.abortMultipartUpload(context.WithoutCancel(ctx), bucket, key, uploadID)
+		return errors.Join(err, abortErr)
+	}
+
+	return nil
+}
+
+type progressCounter struct {
+	total       int64
+	transferred atomic.Int64
+	report      func(transferred, total int64)
+}
+
+func newProgressCounter(total int64, report func(transferred, total int64)) *progressCounter {
+	p := &progressCounter{total: total, report: report}
+	p.add(0)
+	return p
+}
+
+func (p *progressCounter) add(n int64) {
+	if p.report != nil {
+		p.report(p.transferred.Add(n), p.total)
+	}
+}
+
+func (p *progressCounter) writer(w io.Writer) io.Writer {
+	return progressWriter{w: w, progress: p}
+}
+
+type progressWriter struct {
+	w        io.Writer
+	progress *progressCounter
+}
+
+func (w progressWriter) Write(b []byte) (int, error) {
+	n, err := w.w.Write(b)
+	w.progress.add(int64(n))
+	return n, err
+}
+
+// forEachPart calls fn for every part index in [0, count) using at most
+// concurrency goroutines. The first error cancels the remaining parts.
+func forEachPart(ctx context.Context, concurrency, count int, fn func(ctx context.Context, i int) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	indexes := make(chan int)
+	var firstErr error
+	var once sync.Once
+	var wg sync.WaitGroup
+
+	for range min(concurrency, count) {
+		wg.Go(func() {
+			for i := range indexes {
+				if err := fn(ctx, i); err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+				}
+			}
+		})
+	}
+
+feed:
+	for i := range count {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,530 @@
+package s3lib
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testPayload(size int) []byte {
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	return data
+}
+
+func smallParts(opts *TransferOptions) {
+	opts.PartSize = MinPartSize
+	opts.Concurrency = 3
+}
+
+func TestMemoryClientMultipartUploadAndRangedDownload(t *testing.T) {
+	client := NewMemoryClientFactory().WithBucket("bucket", "us-east-1", time.Now()).Build()
+
+	payload := testPayload(int(2*MinPartSize) + 1234)
+	srcPath := filepath.Join(t.TempDir(), "large.bin")
+	if err := os.WriteFile(srcPath, payload, 0o600); err != nil {
+		t.Fatalf("write source file failed: %v", err)
+	}
+
+	if err := client.UploadFile(context.Background(), "bucket", "large.bin", srcPath, smallParts); err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+
+	_, etag, err := client.headObject(context.Background(), "bucket", "large.bin", "")
+	if err != nil {
+		t.Fatalf("head object failed: %v", err)
+	}
+	if !strings.HasSuffix(etag, "-3") {
+		t.Fatalf("expected multipart etag with 3 parts, got %s", etag)
+	}
+	if len(client.buckets["bucket"].uploads) != 0 {
+		t.Fatalf("expected no pending uploads, got %d", len(client.buckets["bucket"].uploads))
+	}
+
+	dstPath := filepath.Join(t.TempDir(), "nested", "large.bin")
+	if err := client.DownloadFile(context.Background(), "bucket", "large.bin", dstPath, smallParts); err != nil {
+		t.Fatalf("download failed: %v", err)
+	}
+
+	got, err := os.ReadFile(dstPath)
+	if err != nil {
+		t.Fatalf("read downloaded file failed: %v", err)
+	}
+	if !bytes.Equal(got, payload) {
+		t.Fatal("downloaded content differs from uploaded content")
+	}
+}
+
+func TestMemoryClientUploadSinglePartReplacesObject(t *testing.T) {
+	client := NewMemoryClientFactory().WithBucket("bucket", "us-east-1", time.Now()).Build()
+
+	srcPath := filepath.Join(t.TempDir(), "small.txt")
+	for _, content := range []string{"first", "second"} {
+		if err := os.WriteFile(srcPath, []byte(content), 0o600); err != nil {
+			t.Fatalf("write source file failed: %v", err)
+		}
+		if err := client.UploadFile(context.Background(), "bucket", "small.txt", srcPath); err != nil {
+			t.Fatalf("upload failed: %v", err)
+		}
+	}
+
+	objects := client.buckets["bucket"].objects
+	if len(objects) != 1 {
+		t.Fatalf("expected re-upload to replace the object, got %d objects", len(objects))
+	}
+	if string(objects[0].data) != "second" || objects[0].etag != md5Hex([]byte("second")) {
+		t.Fatalf("unexpected object after re-upload: %q %s", objects[0].data, objects[0].etag)
+	}
+}
+
+func TestMemoryClientUploadKeepsAttributes(t *testing.T) {
+	client := NewMemoryClientFactory().
+		WithBucket("bucket", "us-east-1", time.Now()).
+		WithObject("bucket", "a.log.gz", 3, time.Now(), "etag", "STANDARD", []byte("old")).
+		WithContentEncoding("bucket", "a.log.gz", "gzip").
+		WithObjectMetadata("bucket", "a.log.gz", map[string]string{"owner": "ops"}, nil).
+		Build()
+
+	srcPath := filepath.Join(t.TempDir(), "a.log.gz")
+	if err := os.WriteFile(srcPath, []byte("new"), 0o600); err != nil {
+		t.Fatalf("write source file failed: %v", err)
+	}
+	if err := client.UploadFile(context.Background(), "bucket", "a.log.gz", srcPath, WithKeepAttributes()); err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+
+	obj := client.buckets["bucket"].objects[0]
+	if string(obj.data) != "new" || obj.contentEncoding != "gzip" || obj.metadata["owner"] != "ops" {
+		t.Fatalf("expected attributes to be kept, got %q %q %v", obj.data, obj.contentEncoding, obj.metadata)
+	}
+
+	if err := client.UploadFile(context.Background(), "bucket", "b.log.gz", srcPath, WithAttributesFrom("a.log.gz")); err != nil {
+		t.Fatalf("upload as another key failed: %v", err)
+	}
+	obj = client.buckets["bucket"].objects[1]
+	if obj.key != "b.log.gz" || obj.contentEncoding != "gzip" || obj.metadata["owner"] != "ops" {
+		t.Fatalf("expected attributes of a.log.gz, got %q %q %v", obj.key, obj.contentEncoding, obj.metadata)
+	}
+}
+
+func TestMemoryClientDownloadEmptyObject(t *testing.T) {
+	client := NewMemoryClientFactory().
+		WithBucket("bucket", "us-east-1", time.Now()).
+		WithObject("bucket", "empty", 0, time.Now(), "etag", "STANDARD", nil).
+		Build()
+
+	dstPath := filepath.Join(t.TempDir(), "empty")
+	if err := client.DownloadFile(context.Background(), "bucket", "empty", dstPath); err != nil {
+		t.Fatalf("download failed: %v", err)
+	}
+
+	info, err := os.Stat(dstPath)
+	if err != nil {
+		t.Fatalf("stat downloaded file failed: %v", err)
+	}
+	if info.Size() != 0 {
+		t.Fatalf("expected empty file, got %d bytes", info.Size())
+	}
+}
+
+func TestMemoryClientCompleteMultipartUploadRejectsSmallParts(t *testing.T) {
+	client := NewMemoryClientFactory().WithBucket("bucket", "us-east-1", time.Now()).Build()
+	ctx := context.Background()
+
+	uploadID, err := client.createMultipartUpload(ctx, "bucket", "key", objectAttributes{})
+	if err != nil {
+		t.Fatalf("create multipart upload failed: %v", err)
+	}
+
+	var parts []completedPart
+	for i, body := range []string{"too", "small"} {
+		part, err := client.uploadPart(ctx, "bucket", "key", uploadID, int32(i+1), strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("upload part failed: %v", err)
+		}
+		parts = append(parts, part)
+	}
+
+	if err := client.completeMultipartUpload(ctx, "bucket", "key", uploadID, parts, ""); err == nil {
+		t.Fatal("expected error for parts below the minimum size")
+	}
+	if err := client.completeMultipartUpload(ctx, "bucket", "key", uploadID, []completedPart{parts[1], parts[0]}, ""); err == nil {
+		t.Fatal("expected error for unordered parts")
+	}
+	if err := client.abortMultipartUpload(ctx, "bucket", "key", uploadID); err != nil {
+		t.Fatalf("abort failed: %v", err)
+	}
+	if _, err := client.uploadPart(ctx, "bucket", "key", uploadID, 3, strings.NewReader("late")); err == nil {
+		t.Fatal("expected error for part of aborted upload")
+	}
+}
+
+func TestMemoryClientGetObjectRangeChecksETag(t *testing.T) {
+	client := NewMemoryClientFactory().
+		WithBucket("bucket", "us-east-1", time.Now()).
+		WithObject("bucket", "key", 10, time.Now(), "etag-1", "STANDARD", []byte("0123456789")).
+		Build()
+
+	body, err := client.getObjectRange(context.Background(), "bucket", "key", "", "etag-1", 2, 5)
+	if err != nil {
+		t.Fatalf("get object range failed: %v", err)
+	}
+	got, _ := io.ReadAll(body)
+	if string(got) != "2345" {
+		t.Fatalf("expected range 2345, got %q", got)
+	}
+
+	if _, err := client.getObjectRange(context.Background(), "bucket", "key", "", "etag-2", 2, 5); err == nil {
+		t.Fatal("expected precondition error for changed etag")
+	}
+}
+
+func TestMemoryClientUploadIfMatch(t *testing.T) {
+	client := NewMemoryClientFactory().
+		WithBucket("bucket", "us-east-1", time.Now()).
+		WithObject("bucket", "key", 3, time.Now(), "etag-1", "STANDARD", []byte("old")).
+		Build()
+	ctx := context.Background()
+
+	var etag string
+	dstPath := filepath.Join(t.TempDir(), "key")
+	if err := client.DownloadFile(ctx, "bucket", "key", dstPath, WithDownloadedETag(&etag)); err != nil {
+		t.Fatalf("download failed: %v", err)
+	}
+	if etag != "etag-1" {
+		t.Fatalf("expected downloaded etag etag-1, got %q", etag)
+	}
+
+	srcPath := filepath.Join(t.TempDir(), "key")
+	if err := os.WriteFile(srcPath, []byte("new"), 0o600); err != nil {
+		t.Fatalf("write source file failed: %v", err)
+	}
+	if err := client.UploadFile(ctx, "bucket", "key", srcPath, WithIfMatch("etag-2")); !errors.Is(err, ErrPreconditionFailed) {
+		t.Fatalf("expected precondition error for changed etag, got %v", err)
+	}
+	if err := client.UploadFile(ctx, "bucket", "key", srcPath, WithIfMatch(etag)); err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+	if obj := client.buckets["bucket"].objects[0]; string(obj.data) != "new" {
+		t.Fatalf("expected object to be replaced, got %q", obj.data)
+	}
+
+	if err := client.DeleteObject(ctx, "bucket", "key"); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	if err := client.UploadFile(ctx, "bucket", "key", srcPath, WithIfMatch(etag)); !errors.Is(err, ErrPreconditionFailed) {
+		t.Fatalf("expected precondition error for deleted object, got %v", err)
+	}
+}
+
+func TestMemoryClientCompleteMultipartUploadIfMatch(t *testing.T) {
+	client := NewMemoryClientFactory().
+		WithBucket("bucket", "us-east-1", time.Now()).
+		WithObject("bucket", "key", 3, time.Now(), "etag-1", "STANDARD", []byte("old")).
+		Build()
+	ctx := context.Background()
+
+	uploadID, err := client.createMultipartUpload(ctx, "bucket", "key", objectAttributes{})
+	if err != nil {
+		t.Fatalf("create multipart upload failed: %v", err)
+	}
+	part, err := client.uploadPart(ctx, "bucket", "key", uploadID, 1, strings.NewReader("new"))
+	if err != nil {
+		t.Fatalf("upload part failed: %v", err)
+	}
+
+	if err := client.completeMultipartUpload(ctx, "bucket", "key", uploadID, []completedPart{part}, "etag-2"); !errors.Is(err, ErrPreconditionFailed) {
+		t.Fatalf("expected precondition error for changed etag, got %v", err)
+	}
+	if err := client.completeMultipartUpload(ctx, "bucket", "key", uploadID, []completedPart{part}, "etag-1"); err != nil {
+		t.Fatalf("complete failed: %v", err)
+	}
+}
+
+type failingPartAPI struct {
+	*MemoryClient
+	failPart int32
+	aborted  atomic.Bool
+}
+
+func (a *failingPartAPI) uploadPart(ctx context.Context, bucket, key, uploadID string, partNumber int32, body io.ReadSeeker) (completedPart, error) {
+	if partNumber == a.failPart {
+		return completedPart{}, errors.New("part failed")
+	}
+	return a.MemoryClient.uploadPart(ctx, bucket, key, uploadID, partNumber, body)
+}
+
+func (a *failingPartAPI) abortMultipartUpload(ctx context.Context, bucket, key, uploadID string) error {
+	a.aborted.Store(true)
+	return a.MemoryClient.abortMultipartUpload(ctx, bucket, key, uploadID)
+}
+
+func TestUploadFileAbortsOnPartError(t *testing.T) {
+	client := NewMemoryClientFactory().WithBucket("bucket", "us-east-1", time.Now()).Build()
+	api := &failingPartAPI{MemoryClient: client, failPart: 2}
+
+	srcPath := filepath.Join(t.TempDir(), "large.bin")
+	if err := os.WriteFile(srcPath, testPayload(int(3*MinPartSize)), 0o600); err != nil {
+		t.Fatalf("write source file failed: %v", err)
+	}
+
+	opts := transferOptions([]func(*TransferOptions){smallParts})
+	if err := uploadFile(context.Background(), api, "bucket", "large.bin", srcPath, opts); err == nil {
+		t.Fatal("expected upload error")
+	}
+	if !api.aborted.Load() {
+		t.Fatal("expected multipart upload to be aborted")
+	}
+	if len(client.buckets["bucket"].uploads) != 0 || len(client.buckets["bucket"].objects) != 0 {
+		t.Fatal("expected no leftover uploads or objects")
+	}
+}
+
+type failingRangeAPI struct {
+	*MemoryClient
+}
+
+func (a failingRangeAPI) getObjectRange(ctx context.Context, bucket, key, versionID, etag string, start, end int64) (io.ReadCloser, error) {
+	if start > 0 {
+		return nil, errors.New("range failed")
+	}
+	return a.MemoryClient.getObjectRange(ctx, bucket, key, versionID, etag, start, end)
+}
+
+func TestDownloadFileKeepsExistingFileOnError(t *testing.T) {
+	client := NewMemoryClientFactory().WithBucket("bucket", "us-east-1", time.Now()).Build()
+	srcPath := filepath.Join(t.TempDir(), "large.bin")
+	if err := os.WriteFile(srcPath, testPayload(int(2*MinPartSize)), 0o600); err != nil {
+		t.Fatalf("write source file failed: %v", err)
+	}
+	if err := client.UploadFile(context.Background(), "bucket", "large.bin", srcPath); err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+
+	dir := t.TempDir()
+	dstPath := filepath.Join(dir, "large.bin")
+	if err := os.WriteFile(dstPath, []byte("local copy"), 0o600); err != nil {
+		t.Fatalf("write local file failed: %v", err)
+	}
+
+	opts := transferOptions([]func(*TransferOptions){smallParts})
+	if err := downloadFile(context.Background(), failingRangeAPI{client}, "bucket", "large.bin", dstPath, opts); err == nil {
+		t.Fatal("expected download error")
+	}
+	data, err := os.ReadFile(dstPath)
+	if err != nil || string(data) != "local copy" {
+		t.Fatalf("expected local file to be kept, got %q %v", data, err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Fatalf("expected no leftover temporary files, got %v", entries)
+	}
+
+	if err := downloadFile(context.Background(), client, "bucket", "large.bin", dstPath, opts); err != nil {
+		t.Fatalf("download failed: %v", err)
+	}
+	if data, _ := os.ReadFile(dstPath); !bytes.Equal(data, testPayload(int(2*MinPartSize))) {
+		t.Fatal("expected local file to be replaced")
+	}
+	if info, err := os.Stat(dstPath); err != nil {
+		t.Fatalf("stat failed: %v", err)
+	} else if info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected the mode of the replaced file to be kept, got %v", info.Mode())
+	}
+
+	newPath := filepath.Join(dir, "new.bin")
+	if err := downloadFile(context.Background(), client, "bucket", "large.bin", newPath, opts); err != nil {
+		t.Fatalf("download failed: %v", err)
+	}
+	if info, err := os.Stat(newPath); err != nil {
+		t.Fatalf("stat failed: %v", err)
+	} else if info.Mode().Perm() != 0o644 {
+		t.Fatalf("expected new files to be readable by everyone, got %v", info.Mode())
+	}
+}
+
+func TestTransferOptionsNormalization(t *testing.T) {
+	opts := transferOptions([]func(*TransferOptions){func(o *TransferOptions) {
+		o.PartSize = 1
+		o.Concurrency = 0
+	}})
+	if opts.PartSize != MinPartSize || opts.Concurrency != 1 {
+		t.Fatalf("unexpected normalized options: %#v", opts)
+	}
+
+	size := int64(MaxParts)*MinPartSize + 1
+	if partSize := opts.partSizeFor(size); (size+partSize-1)/partSize > MaxParts {
+		t.Fatalf("part size %d exceeds the part limit for %d bytes", partSize, size)
+	}
+}
+
+func TestTransferOptionsPartSizeAndConcurrency(t *testing.T) {
+	opts := transferOptions([]func(*TransferOptions){WithPartSize(16 << 20), WithConcurrency(8)})
+	if opts.PartSize != 16<<20 || opts.Concurrency != 8 {
+		t.Fatalf("unexpected options: %#v", opts)
+	}
+
+	opts = transferOptions([]func(*TransferOptions){WithPartSize(0), WithConcurrency(0)})
+	if opts.PartSize != DefaultPartSize || opts.Concurrency != DefaultConcurrency {
+		t.Fatalf("expected defaults for unset options, got %#v", opts)
+	}
+}
+
+func TestTransferProgressReportsAllBytes(t *testing.T) {
+	client := NewMemoryClientFactory().WithBucket("bucket", "us-east-1", time.Now()).Build()
+
+	size := int64(2*MinPartSize) + 42
+	srcPath := filepath.Join(t.TempDir(), "large.bin")
+	if err := os.WriteFile(srcPath, testPayload(int(size)), 0o600); err != nil {
+		t.Fatalf("write source file failed: %v", err)
+	}
+
+	var last atomic.Int64
+	withProgress := func(opts *TransferOptions) {
+		smallParts(opts)
+		opts.Progress = func(transferred, total int64) {
+			if total != size {
+				t.Errorf("expected total %d, got %d", size, total)
+			}
+			last.Store(max(last.Load(), transferred))
+		}
+	}
+
+	if err := client.UploadFile(context.Background(), "bucket", "large.bin", srcPath, withProgress); err != nil {
+		t.Fatalf("upload failed: %v", err)
+	}
+	if last.Load() != size {
+		t.Fatalf("expected upload progress %d, got %d", size, last.Load())
+	}
+
+	last.Store(0)
+	if err := client.DownloadFile(context.Background(), "bucket", "large.bin", filepath.Join(t.TempDir(), "large.bin"), withProgress); err != nil {
+		t.Fatalf("download failed: %v", err)
+	}
+	if last.Load() != size {
+		t.Fatalf("expected download progress %d, got %d", size, last.Load())
+	}
+}
+
+func TestMemoryClientCopyObjectKeepsMetadataAndTags(t *testing.T) {
+	payload := testPayload(int(2*MinPartSize) + 99)
+	metadata := map[string]string{"owner": "team-a"}
+	tags := map[string]string{"project": "s3tool"}
+	client := NewMemoryClientFactory().
+		WithBucket("src", "us-east-1", time.Now()).
+		WithBucket("dst", "us-east-1", time.Now()).
+		WithObject("src", "small.txt", 5, time.Now(), "etag", "STANDARD", []byte("small")).
+		WithObjectMetadata("src", "small.txt", metadata, tags).
+		WithObject("src", "large.bin", int64(len(payload)), time.Now(), md5Hex(payload), "STANDARD", payload).
+		WithObjectMetadata("src", "large.bin", metadata, tags).
+		Build()
+	ctx := context.Background()
+
+	for _, key := range []string{"small.txt", "large.bin"} {
+		if err := client.CopyObject(ctx, "src", key, "dst", "copy/"+key, smallParts); err != nil {
+			t.Fatalf("copy %s failed: %v", key, err)
+		}
+
+		copied, err := client.GetObject(ctx, "dst", "copy/"+key)
+		if err != nil {
+			t.Fatalf("get copied object failed: %v", err)
+		}
+		if copied.Metadata["owner"] != "team-a" || copied.Tags["project"] != "s3tool" || *copied.Type != "image/png" {
+			t.Fatalf("expected metadata and tags of %s to be kept, got %#v", key, copied)
+		}
+	}
+
+	_, etag, err := client.headObject(ctx, "dst", "copy/large.bin", "")
+	if err != nil {
+		t.Fatalf("head object failed: %v", err)
+	}
+	if !strings.HasSuffix(etag, "-3") {
+		t.Fatalf("expected large object to be copied in 3 parts, got etag %s", etag)
+	}
+
+	dstPath := filepath.Join(t.TempDir(), "large.bin")
+	if err := client.DownloadFile(ctx, "dst", "copy/large.bin", dstPath); err != nil {
+		t.Fatalf("download failed: %v", err)
+	}
+	if got, _ := os.ReadFile(dstPath); !bytes.Equal(got, payload) {
+		t.Fatal("copied content differs from the source")
+	}
+}
+
+func TestMemoryClientUploadPartCopyChecksSourceETag(t *testing.T) {
+	client := NewMemoryClientFactory().
+		WithBucket("bucket", "us-east-1", time.Now()).
+		WithObject("bucket", "key", 10, time.Now(), "etag-1", "STANDARD", []byte("0123456789")).
+		Build()
+	ctx := context.Background()
+
+	uploadID, err := client.createMultipartUpload(ctx, "bucket", "copy", objectAttributes{})
+	if err != nil {
+		t.Fatalf("create multipart upload failed: %v", err)
+	}
+
+	src := objectSource{bucket: "bucket", key: "key", etag: "etag-1"}
+	if _, err := client.uploadPartCopy(ctx, "bucket", "copy", uploadID, 1, src, 0, 4); err != nil {
+		t.Fatalf("upload part copy failed: %v", err)
+	}
+	src.etag = "etag-2"
+	if _, err := client.uploadPartCopy(ctx, "bucket", "copy", uploadID, 2, src, 5, 9); err == nil {
+		t.Fatal("expected precondition error for changed etag")
+	}
+}
+
+func TestTransferObjectStreamsBetweenClients(t *testing.T) {
+	payload := testPayload(int(2*MinPartSize) + 7)
+	src := NewMemoryClientFactory().
+		WithBucket("src", "us-east-1", time.Now()).
+		WithObject("src", "empty.txt", 0, time.Now(), md5Hex(nil), "STANDARD", nil).
+		WithObject("src", "large.bin", int64(len(payload)), time.Now(), md5Hex(payload), "STANDARD", payload).
+		WithObjectMetadata("src", "large.bin", map[string]string{"owner": "team-a"}, map[string]string{"project": "s3tool"}).
+		Build()
+	dst := NewMemoryClientFactory().WithBucket("dst", "eu-west-1", time.Now()).Build()
+	ctx := context.Background()
+
+	var last atomic.Int64
+	progress := func(opts *TransferOptions) {
+		smallParts(opts)
+		opts.Progress = func(transferred, total int64) { last.Store(transferred) }
+	}
+	for _, key := range []string{"empty.txt", "large.bin"} {
+		if err := TransferObject(ctx, src, "src", key, dst, "dst", key, progress); err != nil {
+			t.Fatalf("transfer %s failed: %v", key, err)
+		}
+	}
+	if last.Load() != int64(len(payload)) {
+		t.Fatalf("expected progress %d, got %d", len(payload), last.Load())
+	}
+
+	copied, err := dst.GetObject(ctx, "dst", "large.bin")
+	if err != nil {
+		t.Fatalf("get copied object failed: %v", err)
+	}
+	if copied.Metadata["owner"] != "team-a" || copied.Tags["project"] != "s3tool" || *copied.Type != "image/png" {
+		t.Fatalf("expected metadata and tags to be kept, got %#v", copied)
+	}
+	if len(dst.buckets["dst"].uploads) != 0 {
+		t.Fatalf("expected no pending uploads, got %d", len(dst.buckets["dst"].uploads))
+	}
+
+	dstPath := filepath.Join(t.TempDir(), "large.bin")
+	if err := dst.DownloadFile(ctx, "dst", "large.bin", dstPath); err != nil {
+		t.Fatalf("download failed: %v", err)
+	}
+	if got, _ := os.ReadFile(dstPath); !bytes.Equal(got, payload) {
+		t.Fatal("streamed content differs from the source")
+	}
+	if _, err := dst.GetObject(ctx, "dst", "empty.txt"); err != nil {
+		t.Fatalf("expected empty object to be copied: %v", err)
+	}
+}
@@ -8,6 +8,10 @@ import (
 type Object struct {
 	Kind   ObjectKind
 	Object types.Object
+
+	// VersionID and IsLatest are only set for objects listed by ListObjectVersions.
+	VersionID string
+	IsLatest  bool
 }
 
 func NewObjectFile(obj types.Object) Object {
@@ -26,11 +30,39 @@ func NewObjectDirectory(name string) Object {
 	}
 }
 
+func NewObjectVersion(version types.ObjectVersion) Object {
+	return Object{
+		Kind: ObjectKindFile,
+		Object: types.Object{
+			Key:          version.Key,
+			Size:         version.Size,
+			LastModified: version.LastModified,
+			ETag:         version.ETag,
+			StorageClass: types.ObjectStorageClass(version.StorageClass),
+		},
+		VersionID: aws.ToString(version.VersionId),
+		IsLatest:  aws.ToBool(version.IsLatest),
+	}
+}
+
+func NewObjectDeleteMarker(marker types.DeleteMarkerEntry) Object {
+	return Object{
+		Kind: ObjectKindDeleteMarker,
+		Object: types.Object{
+			Key:          marker.Key,
+			LastModified: marker.LastModified,
+		},
+		VersionID: aws.ToString(marker.VersionId),
+		IsLatest:  aws.ToBool(marker.IsLatest),
+	}
+}
+
 type ObjectKind int
 
 const (
 	ObjectKindFile ObjectKind = iota
 	ObjectKindDirectory
+	ObjectKindDeleteMarker
 )
 
 func (o Object) IsFile() bool {
@@ -39,3 +71,6 @@ func (o Object) IsFile() bool {
 func (o Object) IsDirectory() bool {
 	return o.Kind == ObjectKindDirectory
 }
+func (o Object) IsDeleteMarker() bool {
+	return o.Kind == ObjectKindDeleteMarker
+}
@@ -16,4 +16,10 @@ type ObjectMetadata struct {
 	LegalHold    string
 	ETag         *string
 	Metadata     map[string]string
+
+	// CacheControl, ContentDisposition and ContentEncoding are nil if the
+	// object has no such header.
+	CacheControl       *string
+	ContentDisposition *string
+	ContentEncoding    *string
 }
@@ -0,0 +1,38 @@
+package s3lib
+
+import (
+	"context"
+	"io"
+)
+
+// readObjectRange reads up to length bytes of an object starting at offset
+// and returns them together with the size of the object. A negative offset
+// counts from the end of the object. Ranges outside of the object are empty.
+func readObjectRange(ctx context.Context, api multipartAPI, bucket, key, versionID string, offset, length int64) ([]byte, int64, error) {
+	size, etag, err := api.headObject(ctx, bucket, key, versionID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if offset < 0 {
+		offset = max(size+offset, 0)
+	}
+	end := min(offset+length, size)
+	if offset >= end {
+		return []byte{}, size, nil
+	}
+
+	body, err := api.getObjectRange(ctx, bucket, key, versionID, etag, offset, end-1)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() {
+		_ = body.Close()
+	}()
+
+	data, err := io.ReadAll(io.LimitReader(body, end-offset))
+	if err != nil {
+		return nil, 0, err
+	}
+	return data, size, nil
+}
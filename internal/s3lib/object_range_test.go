@@ -0,0 +1,46 @@
+package s3lib
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestReadObjectRange(t *testing.T) {
+	client := NewMemoryClientFactory().
+		WithBucket("bucket", "us-east-1", time.Now()).
+		WithObject("bucket", "key", 10, time.Now(), "etag", "STANDARD", []byte("0123456789")).
+		WithObject("bucket", "empty", 0, time.Now(), "etag", "STANDARD", []byte{}).
+		Build()
+	ctx := context.Background()
+
+	tests := []struct {
+		key            string
+		offset, length int64
+		want           string
+	}{
+		{"key", 0, 4, "0123"},
+		{"key", 8, 10, "89"},
+		{"key", -3, 10, "789"},
+		{"key", -20, 2, "01"},
+		{"key", 12, 2, ""},
+		{"empty", 0, 10, ""},
+	}
+	for _, test := range tests {
+		data, _, err := client.ReadObjectRange(ctx, "bucket", test.key, "", test.offset, test.length)
+		if err != nil {
+			t.Fatalf("read %s at %d failed: %v", test.key, test.offset, err)
+		}
+		if string(data) != test.want {
+			t.Errorf("read %s at %d: expected %q, got %q", test.key, test.offset, test.want, string(data))
+		}
+	}
+
+	_, size, err := client.ReadObjectRange(ctx, "bucket", "key", "", 0, 1)
+	if err != nil || size != 10 {
+		t.Fatalf("expected size 10, got %d, %v", size, err)
+	}
+	if _, _, err := client.ReadObjectRange(ctx, "bucket", "missing", "", 0, 1); err == nil {
+		t.Fatal("expected error for missing object, got nil")
+	}
+}
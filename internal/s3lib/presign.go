@@ -0,0 +1,46 @@
+package s3lib
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// PresignMethod is the request a presigned URL allows.
+type PresignMethod string
+
+const (
+	PresignGet PresignMethod = "GET"
+	PresignPut PresignMethod = "PUT"
+)
+
+// MaxPresignExpiry is the longest validity of a presigned URL accepted by S3.
+const MaxPresignExpiry = 7 * 24 * time.Hour
+
+// DefaultPresignExpiry is the validity of presigned URLs if none is given.
+const DefaultPresignExpiry = time.Hour
+
+// ParsePresignMethod parses a method name case insensitively.
+func ParsePresignMethod(method string) (PresignMethod, error) {
+	switch PresignMethod(strings.ToUpper(method)) {
+	case PresignGet:
+		return PresignGet, nil
+	case PresignPut:
+		return PresignPut, nil
+	}
+	return "", fmt.Errorf("unsupported method %q, expected GET or PUT", method)
+}
+
+func validatePresign(method PresignMethod, versionID string, expires time.Duration) error {
+	if method != PresignGet && method != PresignPut {
+		return fmt.Errorf("unsupported method %q, expected GET or PUT", method)
+	}
+	if method == PresignPut && versionID != "" {
+		return errors.New("upload URLs cannot address a version")
+	}
+	if expires <= 0 || expires > MaxPresignExpiry {
+		return errors.New("expiry must be between 1 second and 7 days")
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package s3lib
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+func TestSdkClientPresignObject(t *testing.T) {
+	client := NewSdkClient(s3.New(s3.Options{
+		Region:       "eu-central-1",
+		BaseEndpoint: aws.String("http://localhost:9000"),
+		UsePathStyle: true,
+		Credentials: aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+			return aws.Credentials{AccessKeyID: "key", SecretAccessKey: "secret"}, nil
+		}),
+	}))
+
+	for _, method := range []PresignMethod{PresignGet, PresignPut} {
+		rawURL, err := client.PresignObject(context.Background(), "bucket", "dir/a b.txt", "", method, 90*time.Minute)
+		if err != nil {
+			t.Fatalf("presign %s failed: %v", method, err)
+		}
+
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			t.Fatalf("parse url failed: %v", err)
+		}
+		if u.Host != "localhost:9000" || u.Path != "/bucket/dir/a b.txt" {
+			t.Fatalf("unexpected url %s", rawURL)
+		}
+		if got := u.Query().Get("X-Amz-Expires"); got != "5400" {
+			t.Fatalf("expected X-Amz-Expires 5400, got %q", got)
+		}
+		if u.Query().Get("X-Amz-Signature") == "" {
+			t.Fatalf("expected a signature in %s", rawURL)
+		}
+	}
+
+	rawURL, err := client.PresignObject(context.Background(), "bucket", "a.txt", "v1", PresignGet, time.Hour)
+	if err != nil {
+		t.Fatalf("presign version failed: %v", err)
+	}
+	if u, _ := url.Parse(rawURL); u.Query().Get("versionId") != "v1" {
+		t.Fatalf("expected versionId in %s", rawURL)
+	}
+	if _, err := client.PresignObject(context.Background(), "bucket", "a.txt", "v1", PresignPut, time.Hour); err == nil {
+		t.Fatal("expected error for upload URL of a version, got nil")
+	}
+}
+
+func TestMemoryClientPresignObject(t *testing.T) {
+	client := NewMemoryClientFactory().
+		WithBucket("bucket", "eu-central-1", time.Now()).
+		WithObject("bucket", "a.txt", 1, time.Now(), "etag", "STANDARD", []byte("a")).
+		Build()
+	ctx := context.Background()
+
+	rawURL, err := client.PresignObject(ctx, "bucket", "a.txt", "", PresignGet, time.Hour)
+	if err != nil {
+		t.Fatalf("presign failed: %v", err)
+	}
+	if rawURL != "memory://bucket/a.txt?Method=GET&X-Amz-Expires=3600" {
+		t.Fatalf("unexpected url %s", rawURL)
+	}
+
+	if _, err := client.PresignObject(ctx, "bucket", "missing.txt", "", PresignGet, time.Hour); err == nil {
+		t.Fatal("expected error for missing object, got nil")
+	}
+	if _, err := client.PresignObject(ctx, "bucket", "missing.txt", "", PresignPut, time.Hour); err != nil {
+		t.Fatalf("presign put of new object failed: %v", err)
+	}
+	if _, err := client.PresignObject(ctx, "bucket", "a.txt", "", PresignGet, 8*24*time.Hour); err == nil {
+		t.Fatal("expected error for expiry above 7 days, got nil")
+	}
+	if _, err := client.PresignObject(ctx, "bucket", "a.txt", "", "DELETE", time.Hour); err == nil {
+		t.Fatal("expected error for unsupported method, got nil")
+	}
+}
+
+func TestParsePresignMethod(t *testing.T) {
+	method, err := ParsePresignMethod("put")
+	if err != nil || method != PresignPut {
+		t.Fatalf("expected PUT, got %q, %v", method, err)
+	}
+	if _, err := ParsePresignMethod("post"); err == nil {
+		t.Fatal("expected error for POST, got nil")
+	}
+}
@@ -0,0 +1,45 @@
+package s3lib
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+)
+
+// Prompter asks the user for what signing in needs, like MFA codes or the
+// confirmation of a device login.
+type Prompter interface {
+	// Prompt shows message and returns the value entered for label. With an
+	// empty label it only waits for the user to continue. It returns
+	// context.Canceled if the user cancels.
+	Prompt(ctx context.Context, message, label string) (string, error)
+}
+
+type prompterKey struct{}
+
+// WithPrompter returns a context that makes connectors ask prompter when
+// they need input from the user while creating a client.
+func WithPrompter(ctx context.Context, prompter Prompter) context.Context {
+	return context.WithValue(ctx, prompterKey{}, prompter)
+}
+
+func prompterFrom(ctx context.Context) Prompter {
+	prompter, _ := ctx.Value(prompterKey{}).(Prompter)
+	return prompter
+}
+
+// mfaTokenProvider returns the token provider of role profiles with an MFA
+// device. It only asks for codes while active is set, that is while the client
+// is created; expired sessions of a client fail instead.
+func mfaTokenProvider(ctx context.Context, profile, serial string, active *atomic.Bool) func() (string, error) {
+	return func() (string, error) {
+		prompter := prompterFrom(ctx)
+		if prompter == nil {
+			return "", fmt.Errorf("profile %s needs an MFA code, but cannot ask for one", profile)
+		}
+		if !active.Load() {
+			return "", fmt.Errorf("the MFA session of profile %s expired, select the profile again", profile)
+		}
+		return prompter.Prompt(ctx, fmt.Sprintf("Enter the MFA code of %s for profile %s.", serial, profile), "MFA Code")
+	}
+}
@@ -0,0 +1,40 @@
+package s3lib
+
+import (
+	"context"
+	"maps"
+)
+
+// ObjectProperties are the headers and user metadata of an object that can
+// be changed without uploading the object again.
+type ObjectProperties struct {
+	ContentType        string
+	CacheControl       string
+	ContentDisposition string
+	Metadata           map[string]string
+}
+
+// setObjectProperties replaces the properties of key by copying the object
+// onto itself. Tags, Content-Encoding and Content-Language are kept. The copy
+// fails if the object changes in the meantime.
+func setObjectProperties(ctx context.Context, api multipartAPI, bucket, key string, props ObjectProperties) error {
+	size, etag, err := api.headObject(ctx, bucket, key, "")
+	if err != nil {
+		return err
+	}
+	attrs, err := api.objectAttributes(ctx, bucket, key, "")
+	if err != nil {
+		return err
+	}
+
+	attrs.contentType = props.ContentType
+	attrs.cacheControl = props.CacheControl
+	attrs.contentDisposition = props.ContentDisposition
+	attrs.metadata = maps.Clone(props.Metadata)
+
+	src := objectSource{bucket: bucket, key: key, etag: etag}
+	if size <= MaxCopyObjectSize {
+		return api.copyObjectReplacing(ctx, src, bucket, key, attrs)
+	}
+	return copyParts(ctx, api, src, size, bucket, key, attrs, DefaultTransferOptions(), newProgressCounter(size, nil))
+}
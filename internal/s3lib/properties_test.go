@@ -0,0 +1,91 @@
+package s3lib
+
+import (
+	"context"
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+func TestMemoryClientSetObjectProperties(t *testing.T) {
+	client := NewMemoryClientFactory().
+		WithBucket("bucket", "us-east-1", time.Now()).
+		WithObject("bucket", "page.html", 4, time.Now(), "etag", "STANDARD", []byte("html")).
+		WithObjectMetadata("bucket", "page.html", map[string]string{"old": "value"}, map[string]string{"team": "web"}).
+		Build()
+	ctx := context.Background()
+
+	err := client.SetObjectProperties(ctx, "bucket", "page.html", ObjectProperties{
+		ContentType:        "text/html",
+		CacheControl:       "max-age=60",
+		ContentDisposition: "inline",
+		Metadata:           map[string]string{"owner": "team-a"},
+	})
+	if err != nil {
+		t.Fatalf("set properties failed: %v", err)
+	}
+
+	obj, err := client.GetObject(ctx, "bucket", "page.html")
+	if err != nil {
+		t.Fatalf("get object failed: %v", err)
+	}
+	if aws.ToString(obj.Type) != "text/html" || aws.ToString(obj.CacheControl) != "max-age=60" || aws.ToString(obj.ContentDisposition) != "inline" {
+		t.Fatalf("expected replaced headers, got %#v", obj)
+	}
+	if !maps.Equal(obj.Metadata, map[string]string{"owner": "team-a"}) {
+		t.Fatalf("expected replaced metadata, got %v", obj.Metadata)
+	}
+	if !maps.Equal(obj.Tags, map[string]string{"team": "web"}) {
+		t.Fatalf("expected tags to be kept, got %v", obj.Tags)
+	}
+	if *obj.Size != 4 {
+		t.Fatalf("expected content to be kept, got size %d", *obj.Size)
+	}
+
+	if err := client.SetObjectProperties(ctx, "bucket", "missing", ObjectProperties{}); err == nil {
+		t.Fatal("expected error for missing object, got nil")
+	}
+}
+
+func TestMemoryClientObjectTags(t *testing.T) {
+	client := NewMemoryClientFactory().
+		WithBucket("bucket", "us-east-1", time.Now()).
+		WithVersioning("bucket").
+		WithObject("bucket", "key", 1, time.Now(), "etag", "STANDARD", []byte("a")).
+		Build()
+	ctx := context.Background()
+
+	tags := map[string]string{"env": "prod", "team": "data"}
+	if err := client.PutObjectTags(ctx, "bucket", "key", tags); err != nil {
+		t.Fatalf("put tags failed: %v", err)
+	}
+	got, err := client.GetObjectTags(ctx, "bucket", "key")
+	if err != nil {
+		t.Fatalf("get tags failed: %v", err)
+	}
+	if !maps.Equal(got, tags) {
+		t.Fatalf("expected tags %v, got %v", tags, got)
+	}
+
+	// tagging does not create a new version
+	versions, err := client.ListObjectVersions(ctx, "bucket", "").NextPage(ctx)
+	if err != nil {
+		t.Fatalf("list versions failed: %v", err)
+	}
+	if len(versions) != 1 {
+		t.Fatalf("expected 1 version, got %d", len(versions))
+	}
+
+	if err := client.PutObjectTags(ctx, "bucket", "key", nil); err != nil {
+		t.Fatalf("remove tags failed: %v", err)
+	}
+	if got, _ := client.GetObjectTags(ctx, "bucket", "key"); len(got) != 0 {
+		t.Fatalf("expected no tags, got %v", got)
+	}
+
+	if err := client.PutObjectTags(ctx, "bucket", "missing", tags); err == nil {
+		t.Fatal("expected error for missing object, got nil")
+	}
+}
@@ -2,10 +2,16 @@ package s3lib
 
 import (
 	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/processcreds"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 )
 
 type S3ToolConnectorParameters struct {
@@ -15,11 +21,31 @@ type S3ToolConnectorParameters struct {
 	Region          string `yaml:"region"`
 	BaseEndpoint    string `yaml:"base_endpoint,omitempty"`
 	UsePathStyle    *bool  `yaml:"use_path_style,omitempty"`
+
+	// CredentialProcess is a command printing credentials as JSON, like
+	// credential_process of AWS profiles. It replaces the static keys.
+	CredentialProcess string `yaml:"credential_process,omitempty"`
+	// SourceProfile names the s3tool profile whose credentials assume the
+	// role instead of the own keys. A profile naming itself uses its keys.
+	SourceProfile string `yaml:"source_profile,omitempty"`
+	// RoleARN is the role assumed with the credentials of the profile.
+	RoleARN    string `yaml:"role_arn,omitempty"`
+	ExternalID string `yaml:"external_id,omitempty"`
+	// MFASerial is the MFA device whose code is asked for when the role is
+	// assumed.
+	MFASerial string `yaml:"mfa_serial,omitempty"`
+	// Duration is the duration of role sessions, like 2h.
+	Duration time.Duration `yaml:"duration,omitempty"`
+	// STSEndpoint replaces the endpoint of STS the role is assumed with.
+	STSEndpoint string `yaml:"sts_endpoint,omitempty"`
 }
 
 type S3ProfileConnector struct {
 	name       string
 	parameters S3ToolConnectorParameters
+	// profiles are the parameters of all s3tool profiles by name, which
+	// source_profile refers to
+	profiles map[string]S3ToolConnectorParameters
 }
 
 func (c *S3ProfileConnector) Name() string {
@@ -30,15 +56,36 @@ func (c *S3ProfileConnector) Type() string {
 	return "s3tool"
 }
 
+// CredentialType returns the kind of credentials of the profile.
+func (c *S3ProfileConnector) CredentialType() string {
+	switch {
+	case c.parameters.RoleARN != "":
+		return CredentialsAssumeRole
+	case c.parameters.CredentialProcess != "":
+		return CredentialsProcess
+	}
+	return CredentialsStatic
+}
+
+// CreateClient creates a client for the profile. Profiles assuming a role
+// with an MFA device sign in right away, asking the Prompter of ctx for the
+// code.
 func (c *S3ProfileConnector) CreateClient(ctx context.Context) (Client, error) {
+	var prompting atomic.Bool
+	prompting.Store(true)
+	defer prompting.Store(false)
+
+	profiles := c.profiles
+	if profiles == nil {
+		profiles = map[string]S3ToolConnectorParameters{c.name: c.parameters}
+	}
+	credentials, mfa, err := profileCredentials(ctx, profiles, c.name, &prompting, map[string]bool{})
+	if err != nil {
+		return nil, err
+	}
+
 	cfg, err := config.LoadDefaultConfig(ctx,
-		config.WithCredentialsProvider(aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
-			return aws.Credentials{
-				AccessKeyID:     c.parameters.AccessKeyID,
-				SecretAccessKey: c.parameters.SecretAccessKey,
-				SessionToken:    c.parameters.SessionToken,
-			}, nil
-		})),
+		config.WithCredentialsProvider(credentials),
 		config.WithBaseEndpoint(c.parameters.BaseEndpoint),
 		config.WithRegion(c.parameters.Region),
 	)
@@ -46,6 +93,12 @@ func (c *S3ProfileConnector) CreateClient(ctx context.Context) (Client, error) {
 		return nil, err
 	}
 
+	if mfa {
+		if _, err := cfg.Credentials.Retrieve(ctx); err != nil {
+			return nil, fmt.Errorf("failed to sign in with profile %s: %w", c.name, err)
+		}
+	}
+
 	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
 		if c.parameters.UsePathStyle != nil {
 			o.UsePathStyle = *c.parameters.UsePathStyle
@@ -54,3 +107,70 @@ func (c *S3ProfileConnector) CreateClient(ctx context.Context) (Client, error) {
 
 	return NewSdkClient(client), nil
 }
+
+// profileCredentials builds the credential chain of the s3tool profile name.
+// Its base credentials are the output of the credential process, the
+// credentials of the source profile or the static keys; with a role they
+// assume it. mfa reports whether assuming a role of the chain needs an MFA
+// code. seen holds the profiles of the chain to detect cycles.
+func profileCredentials(ctx context.Context, profiles map[string]S3ToolConnectorParameters, name string, prompting *atomic.Bool, seen map[string]bool) (provider aws.CredentialsProvider, mfa bool, err error) {
+	parameters, ok := profiles[name]
+	if !ok {
+		return nil, false, fmt.Errorf("unknown source profile %s", name)
+	}
+	if seen[name] {
+		return nil, false, fmt.Errorf("source profiles of %s form a cycle", name)
+	}
+	seen[name] = true
+
+	switch {
+	case parameters.CredentialProcess != "":
+		provider = processcreds.NewProvider(parameters.CredentialProcess)
+	case parameters.SourceProfile != "" && parameters.SourceProfile != name:
+		provider, mfa, err = profileCredentials(ctx, profiles, parameters.SourceProfile, prompting, seen)
+		if err != nil {
+			return nil, false, fmt.Errorf("profile %s: %w", name, err)
+		}
+	default:
+		provider = aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+			return aws.Credentials{
+				AccessKeyID:     parameters.AccessKeyID,
+				SecretAccessKey: parameters.SecretAccessKey,
+				SessionToken:    parameters.SessionToken,
+			}, nil
+		})
+	}
+	if parameters.RoleARN == "" {
+		return provider, mfa, nil
+	}
+
+	region := parameters.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	cfg, err := config.LoadDefaultConfig(ctx,
+		config.WithCredentialsProvider(provider),
+		config.WithRegion(region),
+	)
+	if err != nil {
+		return nil, false, err
+	}
+	client := sts.NewFromConfig(cfg, func(o *sts.Options) {
+		if parameters.STSEndpoint != "" {
+			o.BaseEndpoint = aws.String(parameters.STSEndpoint)
+		}
+	})
+
+	provider = stscreds.NewAssumeRoleProvider(client, parameters.RoleARN, func(o *stscreds.AssumeRoleOptions) {
+		o.ExternalID = optionalString(parameters.ExternalID)
+		o.Duration = parameters.Duration
+		if o.Duration == 0 {
+			o.Duration = assumeRoleDuration
+		}
+		if parameters.MFASerial != "" {
+			o.SerialNumber = aws.String(parameters.MFASerial)
+			o.TokenProvider = mfaTokenProvider(ctx, name, parameters.MFASerial, prompting)
+		}
+	})
+	return aws.NewCredentialsCache(provider), mfa || parameters.MFASerial != "", nil
+}
@@ -0,0 +1,118 @@
+package s3lib
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// isolateAWSConfig keeps the config of the environment out of clients.
+func isolateAWSConfig(t *testing.T) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(home, "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(home, "credentials"))
+	t.Setenv("AWS_PROFILE", "")
+}
+
+func TestS3ProfileConnectorAssumesRoleWithMFA(t *testing.T) {
+	isolateAWSConfig(t)
+
+	var requests []map[string]string
+	server := newSTSStandIn(t, &requests)
+
+	profiles := map[string]S3ToolConnectorParameters{
+		"base": {AccessKeyID: "BASEKEY", SecretAccessKey: "secret", Region: "eu-central-1"},
+		"admin": {
+			Region:        "eu-central-1",
+			SourceProfile: "base",
+			RoleARN:       "arn:aws:iam::123456789012:role/admin",
+			ExternalID:    "ext",
+			MFASerial:     "arn:aws:iam::123456789012:mfa/user",
+			Duration:      2 * time.Hour,
+			STSEndpoint:   server.URL,
+		},
+	}
+	connector := &S3ProfileConnector{name: "admin", parameters: profiles["admin"], profiles: profiles}
+
+	prompter := &testPrompter{answers: []string{"654321"}}
+	if _, err := connector.CreateClient(WithPrompter(context.Background(), prompter)); err != nil {
+		t.Fatalf("create client failed: %v", err)
+	}
+
+	if len(prompter.messages) != 1 || !strings.Contains(prompter.messages[0], "profile admin") {
+		t.Fatalf("expected one MFA prompt, got %q", prompter.messages)
+	}
+	if len(requests) != 1 {
+		t.Fatalf("expected one assume role request, got %v", requests)
+	}
+	request := requests[0]
+	if request["RoleArn"] != "arn:aws:iam::123456789012:role/admin" || request["ExternalId"] != "ext" || request["TokenCode"] != "654321" || request["DurationSeconds"] != "7200" {
+		t.Fatalf("unexpected assume role request %v", request)
+	}
+	if !strings.Contains(request["Authorization"], "Credential=BASEKEY/") {
+		t.Fatalf("expected request signed with the source profile, got %q", request["Authorization"])
+	}
+
+	if _, err := connector.CreateClient(WithPrompter(context.Background(), &testPrompter{})); err == nil {
+		t.Fatal("expected error when the MFA prompt is cancelled")
+	}
+}
+
+func TestS3ProfileConnectorCredentialProcess(t *testing.T) {
+	isolateAWSConfig(t)
+
+	var requests []map[string]string
+	server := newSTSStandIn(t, &requests)
+
+	profiles := map[string]S3ToolConnectorParameters{
+		"process": {CredentialProcess: `echo '{"Version": 1, "AccessKeyId": "PROCKEY", "SecretAccessKey": "secret"}'`},
+		"role": {
+			SourceProfile: "process",
+			RoleARN:       "arn:aws:iam::123456789012:role/reader",
+			STSEndpoint:   server.URL,
+		},
+	}
+
+	provider, mfa, err := profileCredentials(context.Background(), profiles, "role", nil, map[string]bool{})
+	if err != nil || mfa {
+		t.Fatalf("expected chain without MFA, got %v %v", mfa, err)
+	}
+	credentials, err := provider.Retrieve(context.Background())
+	if err != nil {
+		t.Fatalf("retrieve failed: %v", err)
+	}
+	if credentials.AccessKeyID != "ASSUMED" {
+		t.Fatalf("expected assumed credentials, got %s", credentials.AccessKeyID)
+	}
+	if len(requests) != 1 || !strings.Contains(requests[0]["Authorization"], "Credential=PROCKEY/") || requests[0]["DurationSeconds"] != "3600" {
+		t.Fatalf("unexpected assume role requests %v", requests)
+	}
+}
+
+func TestS3ProfileConnectorSourceProfileErrors(t *testing.T) {
+	profiles := map[string]S3ToolConnectorParameters{
+		"a":       {SourceProfile: "b", RoleARN: "arn:aws:iam::123456789012:role/a"},
+		"b":       {SourceProfile: "a", RoleARN: "arn:aws:iam::123456789012:role/b"},
+		"missing": {SourceProfile: "nope", RoleARN: "arn:aws:iam::123456789012:role/c"},
+		"self":    {AccessKeyID: "SELF", SourceProfile: "self"},
+	}
+
+	if _, _, err := profileCredentials(context.Background(), profiles, "a", nil, map[string]bool{}); err == nil || !strings.Contains(err.Error(), "form a cycle") {
+		t.Fatalf("expected cycle error, got %v", err)
+	}
+	if _, _, err := profileCredentials(context.Background(), profiles, "missing", nil, map[string]bool{}); err == nil || !strings.Contains(err.Error(), "unknown source profile nope") {
+		t.Fatalf("expected unknown profile error, got %v", err)
+	}
+
+	provider, _, err := profileCredentials(context.Background(), profiles, "self", nil, map[string]bool{})
+	if err != nil {
+		t.Fatalf("expected a profile to be its own source, got %v", err)
+	}
+	if credentials, _ := provider.Retrieve(context.Background()); credentials.AccessKeyID != "SELF" {
+		t.Fatalf("expected own keys, got %s", credentials.AccessKeyID)
+	}
+}
@@ -17,6 +17,7 @@ func (l *S3ToolLoader) Load() ([]Connector, error) {
 	}
 
 	var profiles []Connector
+	parametersByName := make(map[string]S3ToolConnectorParameters)
 	for _, file := range files {
 		var profileName = file.Name()
 
@@ -44,9 +45,11 @@ func (l *S3ToolLoader) Load() ([]Connector, error) {
 			return nil, err
 		}
 
+		parametersByName[profileName] = parameters
 		profiles = append(profiles, &S3ProfileConnector{
 			name:       profileName,
 			parameters: parameters,
+			profiles:   parametersByName,
 		})
 	}
 
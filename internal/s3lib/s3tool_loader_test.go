@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/schidstorm/s3tool/internal/cli"
 )
@@ -91,3 +92,49 @@ func mustMkdir(t *testing.T, path string) {
 		t.Fatalf("failed to create directory %s: %v", path, err)
 	}
 }
+
+func TestS3ToolLoaderLoadRoleProfiles(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	originalConfig := cli.Config
+	cli.Config = &cli.S3ToolCliConfig{ProfilesDirectory: tmpDir}
+	t.Cleanup(func() {
+		cli.Config = originalConfig
+	})
+
+	mustWriteFile(t, filepath.Join(tmpDir, "base.yaml"), "access_key_id: base\nsecret_access_key: secret\nregion: eu-central-1\n")
+	mustWriteFile(t, filepath.Join(tmpDir, "admin.yaml"), `region: eu-central-1
+role_arn: arn:aws:iam::123456789012:role/admin
+external_id: ext
+source_profile: base
+mfa_serial: arn:aws:iam::123456789012:mfa/user
+duration: 2h
+sts_endpoint: http://localhost:4566
+`)
+	mustWriteFile(t, filepath.Join(tmpDir, "process.yaml"), "credential_process: /usr/bin/creds\n")
+
+	profiles, err := (&S3ToolLoader{}).Load()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	connectors := map[string]*S3ProfileConnector{}
+	for _, profile := range profiles {
+		connectors[profile.Name()] = profile.(*S3ProfileConnector)
+	}
+
+	admin := connectors["admin"]
+	if admin == nil || admin.parameters.SourceProfile != "base" || admin.parameters.Duration != 2*time.Hour || admin.parameters.STSEndpoint != "http://localhost:4566" {
+		t.Fatalf("unexpected admin profile %+v", admin)
+	}
+	if _, ok := admin.profiles["base"]; !ok {
+		t.Fatal("expected the source profile to be known to the connector")
+	}
+
+	expected := map[string]string{"base": CredentialsStatic, "admin": CredentialsAssumeRole, "process": CredentialsProcess}
+	for name, credentialType := range expected {
+		if got := connectors[name].CredentialType(); got != credentialType {
+			t.Fatalf("expected credential type %s for %s, got %s", credentialType, name, got)
+		}
+	}
+}
@@ -0,0 +1,277 @@
+package s3lib
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// GetBucketConfiguration fetches all settings of bucket. Settings that fail
+// are reported with their error instead of failing the whole call.
+func (c SdkClient) GetBucketConfiguration(ctx context.Context, bucket string) ([]BucketSetting, error) {
+	_, err := c.HeadBucket(ctx, &s3.HeadBucketInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return fetchBucketSettings(ctx, bucket, []bucketSettingFetcher{
+		{BucketSettingRegion, c.bucketRegion},
+		{BucketSettingVersioning, c.bucketVersioning},
+		{BucketSettingEncryption, c.bucketEncryption},
+		{BucketSettingLifecycle, c.bucketLifecycle},
+		{BucketSettingCORS, c.bucketCORS},
+		{BucketSettingPolicy, c.bucketPolicy},
+		{BucketSettingPublicAccessBlock, c.bucketPublicAccessBlock},
+		{BucketSettingObjectLock, c.bucketObjectLock},
+		{BucketSettingTags, c.bucketTags},
+		{BucketSettingLogging, c.bucketLogging},
+		{BucketSettingReplication, c.bucketReplication},
+	}), nil
+}
+
+func (c SdkClient) bucketRegion(ctx context.Context, bucket string) ([]string, error) {
+	output, err := c.GetBucketLocation(ctx, &s3.GetBucketLocationInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		return nil, err
+	}
+	// buckets in us-east-1 have no location constraint
+	if output.LocationConstraint == "" {
+		return []string{"us-east-1"}, nil
+	}
+	return []string{string(output.LocationConstraint)}, nil
+}
+
+func (c SdkClient) bucketVersioning(ctx context.Context, bucket string) ([]string, error) {
+	output, err := c.GetBucketVersioning(ctx, &s3.GetBucketVersioningInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil || output.Status == "" {
+		return nil, err
+	}
+
+	values := []string{string(output.Status)}
+	if output.MFADelete != "" {
+		values = append(values, "MFA delete "+string(output.MFADelete))
+	}
+	return values, nil
+}
+
+func (c SdkClient) bucketEncryption(ctx context.Context, bucket string) ([]string, error) {
+	output, err := c.GetBucketEncryption(ctx, &s3.GetBucketEncryptionInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil || output.ServerSideEncryptionConfiguration == nil {
+		return nil, err
+	}
+
+	var values []string
+	for _, rule := range output.ServerSideEncryptionConfiguration.Rules {
+		if rule.ApplyServerSideEncryptionByDefault == nil {
+			continue
+		}
+		value := string(rule.ApplyServerSideEncryptionByDefault.SSEAlgorithm)
+		if keyID := aws.ToString(rule.ApplyServerSideEncryptionByDefault.KMSMasterKeyID); keyID != "" {
+			value += " (" + keyID + ")"
+		}
+		if aws.ToBool(rule.BucketKeyEnabled) {
+			value += ", bucket key"
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+func (c SdkClient) bucketLifecycle(ctx context.Context, bucket string) ([]string, error) {
+	output, err := c.GetBucketLifecycleConfiguration(ctx, &s3.GetBucketLifecycleConfigurationInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]string, 0, len(output.Rules))
+	for _, rule := range output.Rules {
+		values = append(values, lifecycleRuleSummary(rule))
+	}
+	return values, nil
+}
+
+// lifecycleRuleSummary describes the most common parts of a lifecycle rule.
+func lifecycleRuleSummary(rule types.LifecycleRule) string {
+	parts := []string{string(rule.Status)}
+
+	prefix := aws.ToString(rule.Prefix)
+	if rule.Filter != nil && rule.Filter.Prefix != nil {
+		prefix = aws.ToString(rule.Filter.Prefix)
+	}
+	if prefix != "" {
+		parts = append(parts, "prefix "+prefix)
+	}
+	for _, transition := range rule.Transitions {
+		if transition.Days != nil {
+			parts = append(parts, fmt.Sprintf("to %s after %d days", transition.StorageClass, aws.ToInt32(transition.Days)))
+		}
+	}
+	if rule.Expiration != nil && rule.Expiration.Days != nil {
+		parts = append(parts, fmt.Sprintf("expire after %d days", aws.ToInt32(rule.Expiration.Days)))
+	}
+	if rule.NoncurrentVersionExpiration != nil && rule.NoncurrentVersionExpiration.NoncurrentDays != nil {
+		parts = append(parts, fmt.Sprintf("expire noncurrent versions after %d days", aws.ToInt32(rule.NoncurrentVersionExpiration.NoncurrentDays)))
+	}
+	if rule.AbortIncompleteMultipartUpload != nil && rule.AbortIncompleteMultipartUpload.DaysAfterInitiation != nil {
+		parts = append(parts, fmt.Sprintf("abort uploads after %d days", aws.ToInt32(rule.AbortIncompleteMultipartUpload.DaysAfterInitiation)))
+	}
+
+	summary := strings.Join(parts, ", ")
+	if id := aws.ToString(rule.ID); id != "" {
+		summary = id + ": " + summary
+	}
+	return summary
+}
+
+func (c SdkClient) bucketCORS(ctx context.Context, bucket string) ([]string, error) {
+	output, err := c.GetBucketCors(ctx, &s3.GetBucketCorsInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]string, 0, len(output.CORSRules))
+	for _, rule := range output.CORSRules {
+		values = append(values, corsRuleSummary(rule))
+	}
+	return values, nil
+}
+
+func corsRuleSummary(rule types.CORSRule) string {
+	summary := strings.Join(rule.AllowedMethods, ",") + " from " + strings.Join(rule.AllowedOrigins, ",")
+	if len(rule.AllowedHeaders) > 0 {
+		summary += ", headers " + strings.Join(rule.AllowedHeaders, ",")
+	}
+	if rule.MaxAgeSeconds != nil {
+		summary += ", max age " + strconv.Itoa(int(aws.ToInt32(rule.MaxAgeSeconds))) + "s"
+	}
+	return summary
+}
+
+func (c SdkClient) bucketPolicy(ctx context.Context, bucket string) ([]string, error) {
+	output, err := c.GetBucketPolicy(ctx, &s3.GetBucketPolicyInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil || aws.ToString(output.Policy) == "" {
+		return nil, err
+	}
+
+	return []string{policySummary(aws.ToString(output.Policy))}, nil
+}
+
+// policySummary shows a policy on a single line.
+func policySummary(policy string) string {
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, []byte(policy)); err != nil {
+		return policy
+	}
+	return compact.String()
+}
+
+func (c SdkClient) bucketPublicAccessBlock(ctx context.Context, bucket string) ([]string, error) {
+	output, err := c.GetPublicAccessBlock(ctx, &s3.GetPublicAccessBlockInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil || output.PublicAccessBlockConfiguration == nil {
+		return nil, err
+	}
+
+	config := output.PublicAccessBlockConfiguration
+	return []string{
+		"BlockPublicAcls=" + strconv.FormatBool(aws.ToBool(config.BlockPublicAcls)),
+		"IgnorePublicAcls=" + strconv.FormatBool(aws.ToBool(config.IgnorePublicAcls)),
+		"BlockPublicPolicy=" + strconv.FormatBool(aws.ToBool(config.BlockPublicPolicy)),
+		"RestrictPublicBuckets=" + strconv.FormatBool(aws.ToBool(config.RestrictPublicBuckets)),
+	}, nil
+}
+
+func (c SdkClient) bucketObjectLock(ctx context.Context, bucket string) ([]string, error) {
+	output, err := c.GetObjectLockConfiguration(ctx, &s3.GetObjectLockConfigurationInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil || output.ObjectLockConfiguration == nil {
+		return nil, err
+	}
+
+	config := output.ObjectLockConfiguration
+	values := []string{string(config.ObjectLockEnabled)}
+	if config.Rule != nil && config.Rule.DefaultRetention != nil {
+		retention := config.Rule.DefaultRetention
+		switch {
+		case retention.Days != nil:
+			values = append(values, fmt.Sprintf("%s retention of %d days", retention.Mode, aws.ToInt32(retention.Days)))
+		case retention.Years != nil:
+			values = append(values, fmt.Sprintf("%s retention of %d years", retention.Mode, aws.ToInt32(retention.Years)))
+		}
+	}
+	return values, nil
+}
+
+func (c SdkClient) bucketTags(ctx context.Context, bucket string) ([]string, error) {
+	output, err := c.GetBucketTagging(ctx, &s3.GetBucketTaggingInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]string, 0, len(output.TagSet))
+	for _, tag := range output.TagSet {
+		values = append(values, aws.ToString(tag.Key)+"="+aws.ToString(tag.Value))
+	}
+	return values, nil
+}
+
+func (c SdkClient) bucketLogging(ctx context.Context, bucket string) ([]string, error) {
+	output, err := c.GetBucketLogging(ctx, &s3.GetBucketLoggingInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil || output.LoggingEnabled == nil {
+		return nil, err
+	}
+	return []string{"to " + aws.ToString(output.LoggingEnabled.TargetBucket) + "/" + aws.ToString(output.LoggingEnabled.TargetPrefix)}, nil
+}
+
+func (c SdkClient) bucketReplication(ctx context.Context, bucket string) ([]string, error) {
+	output, err := c.GetBucketReplication(ctx, &s3.GetBucketReplicationInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil || output.ReplicationConfiguration == nil {
+		return nil, err
+	}
+
+	config := output.ReplicationConfiguration
+	var values []string
+	if role := aws.ToString(config.Role); role != "" {
+		values = append(values, "role "+role)
+	}
+	for _, rule := range config.Rules {
+		value := string(rule.Status)
+		if rule.Destination != nil {
+			value += " to " + aws.ToString(rule.Destination.Bucket)
+		}
+		if id := aws.ToString(rule.ID); id != "" {
+			value = id + ": " + value
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
@@ -0,0 +1,109 @@
+package s3lib
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// GetBucketDocument returns the document of a bucket setting as indented
+// JSON, or an empty string if the setting is not configured.
+func (c SdkClient) GetBucketDocument(ctx context.Context, bucket string, doc BucketDocument) (string, error) {
+	var document string
+	var err error
+	switch doc {
+	case BucketPolicyDocument:
+		var output *s3.GetBucketPolicyOutput
+		output, err = c.GetBucketPolicy(ctx, &s3.GetBucketPolicyInput{
+			Bucket: aws.String(bucket),
+		})
+		if err == nil && aws.ToString(output.Policy) != "" {
+			document, err = formatDocument([]byte(aws.ToString(output.Policy)))
+		}
+	case BucketLifecycleDocument:
+		var output *s3.GetBucketLifecycleConfigurationOutput
+		output, err = c.GetBucketLifecycleConfiguration(ctx, &s3.GetBucketLifecycleConfigurationInput{
+			Bucket: aws.String(bucket),
+		})
+		if err == nil {
+			document, err = marshalDocument(lifecycleDocument{Rules: output.Rules})
+		}
+	case BucketCORSDocument:
+		var output *s3.GetBucketCorsOutput
+		output, err = c.GetBucketCors(ctx, &s3.GetBucketCorsInput{
+			Bucket: aws.String(bucket),
+		})
+		if err == nil {
+			document, err = marshalDocument(corsDocument{CORSRules: output.CORSRules})
+		}
+	default:
+		return "", fmt.Errorf("unknown bucket document %q", doc)
+	}
+
+	// settings that are not configured are an empty document
+	return document, bucketSettingError(err)
+}
+
+// PutBucketDocument replaces a bucket setting by document, which is checked
+// with ParseBucketDocument first. An empty document removes the setting.
+func (c SdkClient) PutBucketDocument(ctx context.Context, bucket string, doc BucketDocument, document string) error {
+	document, err := ParseBucketDocument(doc, []byte(document))
+	if err != nil {
+		return err
+	}
+	if document == "" {
+		return c.deleteBucketDocument(ctx, bucket, doc)
+	}
+
+	switch doc {
+	case BucketPolicyDocument:
+		_, err = c.PutBucketPolicy(ctx, &s3.PutBucketPolicyInput{
+			Bucket: aws.String(bucket),
+			Policy: aws.String(document),
+		})
+	case BucketLifecycleDocument:
+		var lifecycle lifecycleDocument
+		if err = json.Unmarshal([]byte(document), &lifecycle); err != nil {
+			return err
+		}
+		_, err = c.PutBucketLifecycleConfiguration(ctx, &s3.PutBucketLifecycleConfigurationInput{
+			Bucket:                 aws.String(bucket),
+			LifecycleConfiguration: &types.BucketLifecycleConfiguration{Rules: lifecycle.Rules},
+			ChecksumAlgorithm:      types.ChecksumAlgorithmCrc32,
+		})
+	case BucketCORSDocument:
+		var cors corsDocument
+		if err = json.Unmarshal([]byte(document), &cors); err != nil {
+			return err
+		}
+		_, err = c.PutBucketCors(ctx, &s3.PutBucketCorsInput{
+			Bucket:            aws.String(bucket),
+			CORSConfiguration: &types.CORSConfiguration{CORSRules: cors.CORSRules},
+			ChecksumAlgorithm: types.ChecksumAlgorithmCrc32,
+		})
+	}
+	return err
+}
+
+func (c SdkClient) deleteBucketDocument(ctx context.Context, bucket string, doc BucketDocument) error {
+	var err error
+	switch doc {
+	case BucketPolicyDocument:
+		_, err = c.DeleteBucketPolicy(ctx, &s3.DeleteBucketPolicyInput{
+			Bucket: aws.String(bucket),
+		})
+	case BucketLifecycleDocument:
+		_, err = c.DeleteBucketLifecycle(ctx, &s3.DeleteBucketLifecycleInput{
+			Bucket: aws.String(bucket),
+		})
+	case BucketCORSDocument:
+		_, err = c.DeleteBucketCors(ctx, &s3.DeleteBucketCorsInput{
+			Bucket: aws.String(bucket),
+		})
+	}
+	return err
+}
@@ -3,12 +3,14 @@ package s3lib
 import (
 	"context"
 	"errors"
-	"io"
+	"fmt"
 	"log"
-	"os"
-	"path"
+	"net/url"
+	"slices"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 )
@@ -65,32 +67,35 @@ func (c SdkClient) ListObjects(ctx context.Context, bucket string, prefix string
 	}
 }
 
-func (c SdkClient) CreateBucket(ctx context.Context, bucket, region string) error {
-	_, err := c.Client.CreateBucket(ctx, &s3.CreateBucketInput{
+func (c SdkClient) ListObjectsRecursive(ctx context.Context, bucket string, prefix string) Paginator[Object] {
+	objectPaginator := s3.NewListObjectsV2Paginator(c.Client, &s3.ListObjectsV2Input{
 		Bucket: aws.String(bucket),
-		CreateBucketConfiguration: &types.CreateBucketConfiguration{
-			LocationConstraint: types.BucketLocationConstraint(region),
-		},
+		Prefix: aws.String(prefix),
 	})
-	if err != nil {
-		return err
+
+	return ListObjectsPaginator{
+		ListObjectsV2Paginator: objectPaginator,
 	}
-	return nil
 }
 
-func (c SdkClient) UploadFile(ctx context.Context, bucket, key, filePath string) error {
-	f, err := os.Open(filePath)
-	if err != nil {
-		return err
+func (c SdkClient) ListObjectVersions(ctx context.Context, bucket string, prefix string) Paginator[Object] {
+	versionPaginator := s3.NewListObjectVersionsPaginator(c.Client, &s3.ListObjectVersionsInput{
+		Bucket:    aws.String(bucket),
+		Prefix:    aws.String(prefix),
+		Delimiter: aws.String("/"),
+	})
+
+	return ListObjectVersionsPaginator{
+		ListObjectVersionsPaginator: versionPaginator,
 	}
-	defer func() {
-		_ = f.Close()
-	}()
+}
 
-	_, err = c.PutObject(ctx, &s3.PutObjectInput{
+func (c SdkClient) CreateBucket(ctx context.Context, bucket, region string) error {
+	_, err := c.Client.CreateBucket(ctx, &s3.CreateBucketInput{
 		Bucket: aws.String(bucket),
-		Key:    aws.String(key),
-		Body:   f,
+		CreateBucketConfiguration: &types.CreateBucketConfiguration{
+			LocationConstraint: types.BucketLocationConstraint(region),
+		},
 	})
 	if err != nil {
 		return err
@@ -98,45 +103,27 @@ func (c SdkClient) UploadFile(ctx context.Context, bucket, key, filePath string)
 	return nil
 }
 
-func (c SdkClient) DownloadFile(ctx context.Context, bucket, key, filePath string) error {
-	result, err := c.Client.GetObject(ctx, &s3.GetObjectInput{
-		Bucket: aws.String(bucket),
-		Key:    aws.String(key),
-	})
+func (c SdkClient) UploadFile(ctx context.Context, bucket, key, filePath string, optFns ...func(*TransferOptions)) error {
+	return uploadFile(ctx, c, bucket, key, filePath, transferOptions(optFns))
+}
+
+func (c SdkClient) DownloadFile(ctx context.Context, bucket, key, filePath string, optFns ...func(*TransferOptions)) error {
+	err := downloadFile(ctx, c, bucket, key, filePath, transferOptions(optFns))
 	if err != nil {
 		var noKey *types.NoSuchKey
-		if errors.As(err, &noKey) {
+		var notFound *types.NotFound
+		if errors.As(err, &noKey) || errors.As(err, &notFound) {
 			log.Printf("Can't get object %s from bucket %s. No such key exists.\n", key, bucket)
-			err = noKey
 		} else {
 			log.Printf("Couldn't get object %v:%v. Here's why: %v\n", bucket, key, err)
 		}
 		return err
 	}
-	defer func() {
-		_ = result.Body.Close()
-	}()
-
-	_ = os.Remove(filePath)
-	err = os.MkdirAll(path.Dir(filePath), 0755)
-	if err != nil {
-		return err
-	}
+	return nil
+}
 
-	file, err := os.Create(filePath)
-	if err != nil {
-		log.Printf("Couldn't create file %v. Here's why: %v\n", filePath, err)
-		return err
-	}
-	defer func() {
-		_ = file.Close()
-	}()
-	body, err := io.ReadAll(result.Body)
-	if err != nil {
-		log.Printf("Couldn't read object body from %v. Here's why: %v\n", key, err)
-	}
-	_, err = file.Write(body)
-	return err
+func (c SdkClient) ReadObjectRange(ctx context.Context, bucket, key, versionID string, offset, length int64) ([]byte, int64, error) {
+	return readObjectRange(ctx, c, bucket, key, versionID, offset, length)
 }
 
 func (c SdkClient) GetObject(ctx context.Context, bucket, key string) (ObjectMetadata, error) {
@@ -183,6 +170,9 @@ func (c SdkClient) GetObject(ctx context.Context, bucket, key string) (ObjectMet
 	if err == nil {
 		_ = attr.Body.Close()
 		result.Type = attr.ContentType
+		result.CacheControl = attr.CacheControl
+		result.ContentDisposition = attr.ContentDisposition
+		result.ContentEncoding = attr.ContentEncoding
 		result.Size = attr.ContentLength
 		result.LastModified = attr.LastModified
 		result.Metadata = attr.Metadata
@@ -193,6 +183,49 @@ func (c SdkClient) GetObject(ctx context.Context, bucket, key string) (ObjectMet
 	return result, err
 }
 
+func (c SdkClient) GetObjectTags(ctx context.Context, bucket, key string) (map[string]string, error) {
+	tagging, err := c.GetObjectTagging(ctx, &s3.GetObjectTaggingInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tags := map[string]string{}
+	for _, tag := range tagging.TagSet {
+		tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return tags, nil
+}
+
+// PutObjectTags replaces the tags of key. Empty tags remove all tags.
+func (c SdkClient) PutObjectTags(ctx context.Context, bucket, key string, tags map[string]string) error {
+	if len(tags) == 0 {
+		_, err := c.DeleteObjectTagging(ctx, &s3.DeleteObjectTaggingInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+		})
+		return err
+	}
+
+	tagSet := make([]types.Tag, 0, len(tags))
+	for k, v := range tags {
+		tagSet = append(tagSet, types.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+	_, err := c.PutObjectTagging(ctx, &s3.PutObjectTaggingInput{
+		Bucket:            aws.String(bucket),
+		Key:               aws.String(key),
+		Tagging:           &types.Tagging{TagSet: tagSet},
+		ChecksumAlgorithm: types.ChecksumAlgorithmCrc32,
+	})
+	return err
+}
+
+func (c SdkClient) SetObjectProperties(ctx context.Context, bucket, key string, props ObjectProperties) error {
+	return setObjectProperties(ctx, c, bucket, key, props)
+}
+
 func (c SdkClient) DeleteBucket(ctx context.Context, bucket string) error {
 	_, err := c.Client.DeleteBucket(ctx, &s3.DeleteBucketInput{
 		Bucket: aws.String(bucket),
@@ -207,3 +240,88 @@ func (c SdkClient) DeleteObject(ctx context.Context, bucket, key string) error {
 	})
 	return err
 }
+
+// maxDeleteObjects is the maximum number of keys of a DeleteObjects request.
+const maxDeleteObjects = 1000
+
+// DeleteObjects deletes keys in batches. Keys that could not be deleted are
+// reported together in the returned error.
+func (c SdkClient) DeleteObjects(ctx context.Context, bucket string, keys []string) error {
+	var errs []error
+	for batch := range slices.Chunk(keys, maxDeleteObjects) {
+		identifiers := make([]types.ObjectIdentifier, 0, len(batch))
+		for _, key := range batch {
+			identifiers = append(identifiers, types.ObjectIdentifier{Key: aws.String(key)})
+		}
+
+		output, err := c.Client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+			Bucket: aws.String(bucket),
+			Delete: &types.Delete{
+				Objects: identifiers,
+				Quiet:   aws.Bool(true),
+			},
+			ChecksumAlgorithm: types.ChecksumAlgorithmCrc32,
+		})
+		if err != nil {
+			// the keys of earlier batches that failed are reported as well
+			return errors.Join(append(errs, err)...)
+		}
+		for _, deleteErr := range output.Errors {
+			errs = append(errs, fmt.Errorf("%s: %s", aws.ToString(deleteErr.Key), aws.ToString(deleteErr.Message)))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (c SdkClient) DeleteObjectVersion(ctx context.Context, bucket, key, versionID string) error {
+	_, err := c.Client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket:    aws.String(bucket),
+		Key:       aws.String(key),
+		VersionId: aws.String(versionID),
+	})
+	return err
+}
+
+func (c SdkClient) CopyObject(ctx context.Context, srcBucket, srcKey, bucket, key string, optFns ...func(*TransferOptions)) error {
+	return copyObject(ctx, c, srcBucket, srcKey, bucket, key, transferOptions(optFns))
+}
+
+// RestoreObjectVersion copies a version over the current version of key.
+func (c SdkClient) RestoreObjectVersion(ctx context.Context, bucket, key, versionID string) error {
+	return c.CopyObject(ctx, bucket, key, bucket, key, WithVersion(versionID))
+}
+
+func (c SdkClient) PresignObject(ctx context.Context, bucket, key, versionID string, method PresignMethod, expires time.Duration) (string, error) {
+	if err := validatePresign(method, versionID, expires); err != nil {
+		return "", err
+	}
+
+	presignClient := s3.NewPresignClient(c.Client, s3.WithPresignExpires(expires))
+	var request *v4.PresignedHTTPRequest
+	var err error
+	if method == PresignPut {
+		request, err = presignClient.PresignPutObject(ctx, &s3.PutObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+		})
+	} else {
+		request, err = presignClient.PresignGetObject(ctx, &s3.GetObjectInput{
+			Bucket:    aws.String(bucket),
+			Key:       aws.String(key),
+			VersionId: optionalString(versionID),
+		})
+	}
+	if err != nil {
+		return "", err
+	}
+	return request.URL, nil
+}
+
+// copySource builds the url encoded CopySource parameter of copy requests.
+func copySource(bucket, key, versionID string) string {
+	source := url.PathEscape(bucket + "/" + key)
+	if versionID != "" {
+		source += "?versionId=" + url.QueryEscape(versionID)
+	}
+	return source
+}
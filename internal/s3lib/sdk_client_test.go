@@ -0,0 +1,49 @@
+package s3lib
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+func TestSdkClientDeleteObjectsKeepsErrorsOfEarlierBatches(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/xml")
+		if requests > 1 {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = fmt.Fprint(w, `<Error><Code>AccessDenied</Code><Message>Access Denied</Message></Error>`)
+			return
+		}
+		_, _ = fmt.Fprint(w, `<DeleteResult><Error><Key>locked.txt</Key><Code>AccessDenied</Code><Message>Access Denied</Message></Error></DeleteResult>`)
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewSdkClient(s3.New(s3.Options{
+		Region:       "eu-central-1",
+		BaseEndpoint: aws.String(server.URL),
+		UsePathStyle: true,
+		Credentials: aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+			return aws.Credentials{AccessKeyID: "key", SecretAccessKey: "secret"}, nil
+		}),
+	}))
+
+	keys := make([]string, maxDeleteObjects+1)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("%d.txt", i)
+	}
+	err := client.DeleteObjects(context.Background(), "bucket", keys)
+	if requests != 2 {
+		t.Fatalf("expected two batches, got %d requests", requests)
+	}
+	if err == nil || !strings.Contains(err.Error(), "locked.txt: Access Denied") || !strings.Contains(err.Error(), "AccessDenied") {
+		t.Fatalf("expected the errors of both batches, got %v", err)
+	}
+}
@@ -0,0 +1,255 @@
+package s3lib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+func (c SdkClient) putObject(ctx context.Context, bucket, key string, body io.ReadSeeker, attrs objectAttributes, ifMatch string) error {
+	_, err := c.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:             aws.String(bucket),
+		Key:                aws.String(key),
+		Body:               body,
+		ContentType:        optionalString(attrs.contentType),
+		CacheControl:       optionalString(attrs.cacheControl),
+		ContentDisposition: optionalString(attrs.contentDisposition),
+		ContentEncoding:    optionalString(attrs.contentEncoding),
+		ContentLanguage:    optionalString(attrs.contentLanguage),
+		Metadata:           attrs.metadata,
+		Tagging:            encodeTags(attrs.tags),
+		IfMatch:            optionalString(ifMatch),
+	})
+	return preconditionError(err)
+}
+
+func (c SdkClient) createMultipartUpload(ctx context.Context, bucket, key string, attrs objectAttributes) (string, error) {
+	input := &s3.CreateMultipartUploadInput{
+		Bucket:             aws.String(bucket),
+		Key:                aws.String(key),
+		ChecksumAlgorithm:  types.ChecksumAlgorithmCrc32,
+		ContentType:        optionalString(attrs.contentType),
+		CacheControl:       optionalString(attrs.cacheControl),
+		ContentDisposition: optionalString(attrs.contentDisposition),
+		ContentEncoding:    optionalString(attrs.contentEncoding),
+		ContentLanguage:    optionalString(attrs.contentLanguage),
+		Metadata:           attrs.metadata,
+		Tagging:            encodeTags(attrs.tags),
+	}
+
+	output, err := c.CreateMultipartUpload(ctx, input)
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(output.UploadId), nil
+}
+
+func (c SdkClient) uploadPart(ctx context.Context, bucket, key, uploadID string, partNumber int32, body io.ReadSeeker) (completedPart, error) {
+	output, err := c.UploadPart(ctx, &s3.UploadPartInput{
+		Bucket:            aws.String(bucket),
+		Key:               aws.String(key),
+		UploadId:          aws.String(uploadID),
+		PartNumber:        aws.Int32(partNumber),
+		Body:              body,
+		ChecksumAlgorithm: types.ChecksumAlgorithmCrc32,
+	})
+	if err != nil {
+		return completedPart{}, err
+	}
+	return completedPart{
+		partNumber:    partNumber,
+		etag:          aws.ToString(output.ETag),
+		checksumCRC32: aws.ToString(output.ChecksumCRC32),
+	}, nil
+}
+
+func (c SdkClient) copyObject(ctx context.Context, src objectSource, bucket, key string) error {
+	_, err := c.Client.CopyObject(ctx, &s3.CopyObjectInput{
+		Bucket:            aws.String(bucket),
+		Key:               aws.String(key),
+		CopySource:        aws.String(copySource(src.bucket, src.key, src.versionID)),
+		CopySourceIfMatch: optionalString(src.etag),
+		ChecksumAlgorithm: types.ChecksumAlgorithmCrc32,
+	})
+	return err
+}
+
+func (c SdkClient) copyObjectReplacing(ctx context.Context, src objectSource, bucket, key string, attrs objectAttributes) error {
+	_, err := c.Client.CopyObject(ctx, &s3.CopyObjectInput{
+		Bucket:             aws.String(bucket),
+		Key:                aws.String(key),
+		CopySource:         aws.String(copySource(src.bucket, src.key, src.versionID)),
+		CopySourceIfMatch:  optionalString(src.etag),
+		ChecksumAlgorithm:  types.ChecksumAlgorithmCrc32,
+		MetadataDirective:  types.MetadataDirectiveReplace,
+		ContentType:        optionalString(attrs.contentType),
+		CacheControl:       optionalString(attrs.cacheControl),
+		ContentDisposition: optionalString(attrs.contentDisposition),
+		ContentEncoding:    optionalString(attrs.contentEncoding),
+		ContentLanguage:    optionalString(attrs.contentLanguage),
+		Metadata:           attrs.metadata,
+	})
+	return err
+}
+
+func (c SdkClient) uploadPartCopy(ctx context.Context, bucket, key, uploadID string, partNumber int32, src objectSource, start, end int64) (completedPart, error) {
+	output, err := c.UploadPartCopy(ctx, &s3.UploadPartCopyInput{
+		Bucket:            aws.String(bucket),
+		Key:               aws.String(key),
+		UploadId:          aws.String(uploadID),
+		PartNumber:        aws.Int32(partNumber),
+		CopySource:        aws.String(copySource(src.bucket, src.key, src.versionID)),
+		CopySourceIfMatch: optionalString(src.etag),
+		CopySourceRange:   aws.String(fmt.Sprintf("bytes=%d-%d", start, end)),
+	})
+	if err != nil {
+		return completedPart{}, err
+	}
+
+	part := completedPart{partNumber: partNumber}
+	if output.CopyPartResult != nil {
+		part.etag = aws.ToString(output.CopyPartResult.ETag)
+		part.checksumCRC32 = aws.ToString(output.CopyPartResult.ChecksumCRC32)
+	}
+	return part, nil
+}
+
+func (c SdkClient) objectAttributes(ctx context.Context, bucket, key, versionID string) (objectAttributes, error) {
+	head, err := c.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket:    aws.String(bucket),
+		Key:       aws.String(key),
+		VersionId: optionalString(versionID),
+	})
+	if err != nil {
+		return objectAttributes{}, err
+	}
+
+	tagging, err := c.GetObjectTagging(ctx, &s3.GetObjectTaggingInput{
+		Bucket:    aws.String(bucket),
+		Key:       aws.String(key),
+		VersionId: optionalString(versionID),
+	})
+	if err != nil {
+		return objectAttributes{}, err
+	}
+
+	attrs := objectAttributes{
+		contentType:        aws.ToString(head.ContentType),
+		cacheControl:       aws.ToString(head.CacheControl),
+		contentDisposition: aws.ToString(head.ContentDisposition),
+		contentEncoding:    aws.ToString(head.ContentEncoding),
+		contentLanguage:    aws.ToString(head.ContentLanguage),
+		metadata:           head.Metadata,
+		tags:               map[string]string{},
+	}
+	for _, tag := range tagging.TagSet {
+		attrs.tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return attrs, nil
+}
+
+func (c SdkClient) completeMultipartUpload(ctx context.Context, bucket, key, uploadID string, parts []completedPart, ifMatch string) error {
+	completed := make([]types.CompletedPart, 0, len(parts))
+	for _, part := range parts {
+		p := types.CompletedPart{
+			PartNumber: aws.Int32(part.partNumber),
+			ETag:       aws.String(part.etag),
+		}
+		if part.checksumCRC32 != "" {
+			p.ChecksumCRC32 = aws.String(part.checksumCRC32)
+		}
+		completed = append(completed, p)
+	}
+
+	_, err := c.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:   aws.String(bucket),
+		Key:      aws.String(key),
+		UploadId: aws.String(uploadID),
+		MultipartUpload: &types.CompletedMultipartUpload{
+			Parts: completed,
+		},
+		IfMatch: optionalString(ifMatch),
+	})
+	return preconditionError(err)
+}
+
+// preconditionError marks the errors of failed If-Match conditions with
+// ErrPreconditionFailed.
+func preconditionError(err error) error {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "PreconditionFailed", "ConditionalRequestConflict":
+			return fmt.Errorf("%w: %w", ErrPreconditionFailed, err)
+		}
+	}
+	return err
+}
+
+func (c SdkClient) abortMultipartUpload(ctx context.Context, bucket, key, uploadID string) error {
+	_, err := c.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(bucket),
+		Key:      aws.String(key),
+		UploadId: aws.String(uploadID),
+	})
+	return err
+}
+
+func (c SdkClient) headObject(ctx context.Context, bucket, key, versionID string) (int64, string, error) {
+	output, err := c.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket:    aws.String(bucket),
+		Key:       aws.String(key),
+		VersionId: optionalString(versionID),
+	})
+	if err != nil {
+		return 0, "", err
+	}
+	return aws.ToInt64(output.ContentLength), aws.ToString(output.ETag), nil
+}
+
+func (c SdkClient) getObjectRange(ctx context.Context, bucket, key, versionID, etag string, start, end int64) (io.ReadCloser, error) {
+	input := &s3.GetObjectInput{
+		Bucket:    aws.String(bucket),
+		Key:       aws.String(key),
+		VersionId: optionalString(versionID),
+		Range:     aws.String(fmt.Sprintf("bytes=%d-%d", start, end)),
+	}
+	if etag != "" {
+		input.IfMatch = aws.String(etag)
+	}
+
+	output, err := c.Client.GetObject(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	return output.Body, nil
+}
+
+// encodeTags encodes tags as the query string expected by the Tagging
+// header, or returns nil without tags.
+func encodeTags(tags map[string]string) *string {
+	if len(tags) == 0 {
+		return nil
+	}
+	tagging := url.Values{}
+	for k, v := range tags {
+		tagging.Set(k, v)
+	}
+	return aws.String(tagging.Encode())
+}
+
+// optionalString returns nil for empty strings, so optional request
+// parameters are left out.
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return aws.String(s)
+}
@@ -2,6 +2,8 @@ package s3lib
 
 import (
 	"context"
+	"slices"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
@@ -49,3 +51,45 @@ func (p ListObjectsPaginator) NextPage(ctx context.Context, optFns ...func(*s3.O
 func (p ListObjectsPaginator) HasMorePages() bool {
 	return p.ListObjectsV2Paginator.HasMorePages()
 }
+
+type ListObjectVersionsPaginator struct {
+	*s3.ListObjectVersionsPaginator
+}
+
+func (p ListObjectVersionsPaginator) NextPage(ctx context.Context, optFns ...func(*s3.Options)) ([]Object, error) {
+	output, err := p.ListObjectVersionsPaginator.NextPage(ctx, optFns...)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]Object, 0, len(output.Versions)+len(output.DeleteMarkers))
+	for _, version := range output.Versions {
+		versions = append(versions, NewObjectVersion(version))
+	}
+	for _, marker := range output.DeleteMarkers {
+		versions = append(versions, NewObjectDeleteMarker(marker))
+	}
+	sortVersions(versions)
+
+	result := make([]Object, 0, len(output.CommonPrefixes)+len(versions))
+	for _, prefix := range output.CommonPrefixes {
+		result = append(result, NewObjectDirectory(aws.ToString(prefix.Prefix)))
+	}
+	result = append(result, versions...)
+
+	return result, nil
+}
+
+func (p ListObjectVersionsPaginator) HasMorePages() bool {
+	return p.ListObjectVersionsPaginator.HasMorePages()
+}
+
+// sortVersions orders versions by key and, per key, from newest to oldest.
+func sortVersions(versions []Object) {
+	slices.SortStableFunc(versions, func(a, b Object) int {
+		if byKey := strings.Compare(aws.ToString(a.Object.Key), aws.ToString(b.Object.Key)); byKey != 0 {
+			return byKey
+		}
+		return aws.ToTime(b.Object.LastModified).Compare(aws.ToTime(a.Object.LastModified))
+	})
+}
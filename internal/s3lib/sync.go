@@ -0,0 +1,380 @@
+package s3lib
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// SyncLocation is one side of a sync, either a local directory or a prefix of
+// a bucket.
+type SyncLocation struct {
+	// Client is nil for local directories.
+	Client Client
+	Bucket string
+	Prefix string
+	Dir    string
+}
+
+func LocalSyncLocation(dir string) SyncLocation {
+	return SyncLocation{Dir: dir}
+}
+
+func RemoteSyncLocation(client Client, bucket, prefix string) SyncLocation {
+	return SyncLocation{Client: client, Bucket: bucket, Prefix: prefix}
+}
+
+func (l SyncLocation) IsRemote() bool {
+	return l.Client != nil
+}
+
+func (l SyncLocation) String() string {
+	if l.IsRemote() {
+		return l.Bucket + "/" + l.Prefix
+	}
+	return l.Dir
+}
+
+type SyncOptions struct {
+	// Delete removes files from the destination that do not exist in the
+	// source.
+	Delete bool
+	// Include limits the sync to paths matching one of the globs. Globs
+	// without a slash are matched against the file name.
+	Include []string
+	// Exclude skips paths matching one of the globs, also when deleting.
+	Exclude []string
+}
+
+type SyncActionKind string
+
+const (
+	SyncCopy   SyncActionKind = "copy"
+	SyncUpdate SyncActionKind = "update"
+	SyncDelete SyncActionKind = "delete"
+)
+
+// SyncAction is a change to a single file, addressed by its path relative to
+// the synced directories.
+type SyncAction struct {
+	Kind SyncActionKind
+	Path string
+	Size int64
+}
+
+// SyncPlan lists the changes that make the destination mirror the source.
+type SyncPlan struct {
+	Source      SyncLocation
+	Destination SyncLocation
+	Actions     []SyncAction
+	Unchanged   int
+}
+
+type syncEntry struct {
+	size    int64
+	modTime time.Time
+	// etag is the md5 of remote objects uploaded in one part, empty for
+	// local files and multipart uploads.
+	etag string
+}
+
+// PlanSync compares src and dst by size, ETag and modification time and
+// returns the changes needed to make dst mirror src.
+func PlanSync(ctx context.Context, src, dst SyncLocation, opts SyncOptions) (SyncPlan, error) {
+	plan := SyncPlan{Source: src, Destination: dst}
+	if !src.IsRemote() && !dst.IsRemote() {
+		return plan, errors.New("at least one location must be remote")
+	}
+
+	for _, glob := range slices.Concat(opts.Include, opts.Exclude) {
+		if _, err := path.Match(glob, ""); err != nil {
+			return plan, fmt.Errorf("invalid glob %q: %w", glob, err)
+		}
+	}
+
+	srcEntries, err := syncEntries(ctx, src, opts)
+	if err != nil {
+		return plan, err
+	}
+	dstEntries, err := syncEntries(ctx, dst, opts)
+	if err != nil {
+		return plan, err
+	}
+
+	for _, relPath := range slices.Sorted(maps.Keys(srcEntries)) {
+		srcEntry := srcEntries[relPath]
+		dstEntry, exists := dstEntries[relPath]
+		switch {
+		case !exists:
+			plan.Actions = append(plan.Actions, SyncAction{Kind: SyncCopy, Path: relPath, Size: srcEntry.size})
+		default:
+			changed, err := syncChanged(src, dst, relPath, srcEntry, dstEntry)
+			if err != nil {
+				return plan, err
+			}
+			if changed {
+				plan.Actions = append(plan.Actions, SyncAction{Kind: SyncUpdate, Path: relPath, Size: srcEntry.size})
+			} else {
+				plan.Unchanged++
+			}
+		}
+	}
+
+	if opts.Delete {
+		for _, relPath := range slices.Sorted(maps.Keys(dstEntries)) {
+			if _, exists := srcEntries[relPath]; !exists {
+				plan.Actions = append(plan.Actions, SyncAction{Kind: SyncDelete, Path: relPath, Size: dstEntries[relPath].size})
+			}
+		}
+	}
+
+	return plan, nil
+}
+
+// Count returns the number of actions of kind.
+func (p SyncPlan) Count(kind SyncActionKind) int {
+	var count int
+	for _, action := range p.Actions {
+		if action.Kind == kind {
+			count++
+		}
+	}
+	return count
+}
+
+// TransferSize returns the number of bytes copied by the plan.
+func (p SyncPlan) TransferSize() int64 {
+	var size int64
+	for _, action := range p.Actions {
+		if action.Kind != SyncDelete {
+			size += action.Size
+		}
+	}
+	return size
+}
+
+// WithoutDeletes returns the plan without the delete actions.
+func (p SyncPlan) WithoutDeletes() SyncPlan {
+	p.Actions = slices.DeleteFunc(slices.Clone(p.Actions), func(action SyncAction) bool {
+		return action.Kind == SyncDelete
+	})
+	return p
+}
+
+func (p SyncPlan) Summary() string {
+	return fmt.Sprintf("%d new, %d changed, %d deleted, %d unchanged, %d bytes to transfer",
+		p.Count(SyncCopy), p.Count(SyncUpdate), p.Count(SyncDelete), p.Unchanged, p.TransferSize())
+}
+
+// Run performs the actions of the plan, copying with optFns. progress is
+// called with the bytes copied so far and may be nil.
+func (p SyncPlan) Run(ctx context.Context, progress func(transferred, total int64), optFns ...func(*TransferOptions)) error {
+	if progress == nil {
+		progress = func(transferred, total int64) {}
+	}
+	total := p.TransferSize()
+
+	var finished int64
+	var deletes []string
+	for _, action := range p.Actions {
+		if action.Kind == SyncDelete {
+			deletes = append(deletes, action.Path)
+			continue
+		}
+
+		err := p.copy(ctx, action.Path, append(slices.Clone(optFns), WithProgress(func(transferred, _ int64) {
+			progress(finished+transferred, total)
+		}))...)
+		if err != nil {
+			return fmt.Errorf("%s %s: %w", action.Kind, action.Path, err)
+		}
+		finished += action.Size
+		progress(finished, total)
+	}
+
+	return p.delete(ctx, deletes)
+}
+
+func (p SyncPlan) copy(ctx context.Context, relPath string, optFns ...func(*TransferOptions)) error {
+	src, dst := p.Source, p.Destination
+	switch {
+	case !src.IsRemote():
+		return dst.Client.UploadFile(ctx, dst.Bucket, dst.Prefix+relPath, src.localPath(relPath), optFns...)
+	case !dst.IsRemote():
+		if !filepath.IsLocal(filepath.FromSlash(relPath)) {
+			return fmt.Errorf("cannot download %s outside of %s", relPath, dst.Dir)
+		}
+		return src.Client.DownloadFile(ctx, src.Bucket, src.Prefix+relPath, dst.localPath(relPath), optFns...)
+	default:
+		return TransferObject(ctx, src.Client, src.Bucket, src.Prefix+relPath, dst.Client, dst.Bucket, dst.Prefix+relPath, optFns...)
+	}
+}
+
+func (p SyncPlan) delete(ctx context.Context, relPaths []string) error {
+	if len(relPaths) == 0 {
+		return nil
+	}
+
+	dst := p.Destination
+	if dst.IsRemote() {
+		keys := make([]string, 0, len(relPaths))
+		for _, relPath := range relPaths {
+			keys = append(keys, dst.Prefix+relPath)
+		}
+		return dst.Client.DeleteObjects(ctx, dst.Bucket, keys)
+	}
+
+	for _, relPath := range relPaths {
+		if err := os.Remove(dst.localPath(relPath)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l SyncLocation) localPath(relPath string) string {
+	return filepath.Join(l.Dir, filepath.FromSlash(relPath))
+}
+
+// syncEntries lists the files of l that pass the filters of opts by their
+// path relative to l.
+func syncEntries(ctx context.Context, l SyncLocation, opts SyncOptions) (map[string]syncEntry, error) {
+	entries := map[string]syncEntry{}
+	if l.IsRemote() {
+		paginator := l.Client.ListObjectsRecursive(ctx, l.Bucket, l.Prefix)
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, err
+			}
+			for _, object := range page {
+				relPath := strings.TrimPrefix(aws.ToString(object.Object.Key), l.Prefix)
+				// directory placeholders have no counterpart in local directories
+				if relPath == "" || strings.HasSuffix(relPath, "/") || !syncIncluded(relPath, opts) {
+					continue
+				}
+				entries[relPath] = syncEntry{
+					size:    aws.ToInt64(object.Object.Size),
+					modTime: aws.ToTime(object.Object.LastModified),
+					etag:    singlePartETag(aws.ToString(object.Object.ETag)),
+				}
+			}
+		}
+		return entries, nil
+	}
+
+	err := filepath.WalkDir(l.Dir, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if filePath == l.Dir && errors.Is(err, fs.ErrNotExist) {
+				// a missing destination directory is created by the downloads
+				return filepath.SkipAll
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(l.Dir, filePath)
+		if err != nil {
+			return err
+		}
+		relPath = filepath.ToSlash(relPath)
+		if !syncIncluded(relPath, opts) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		entries[relPath] = syncEntry{size: info.Size(), modTime: info.ModTime()}
+		return nil
+	})
+	return entries, err
+}
+
+// syncChanged reports whether the file at relPath differs between src and
+// dst. Files of different size differ. Otherwise the md5 of files is compared
+// if known on both sides, else files changed if the source is newer.
+func syncChanged(src, dst SyncLocation, relPath string, srcEntry, dstEntry syncEntry) (bool, error) {
+	if srcEntry.size != dstEntry.size {
+		return true, nil
+	}
+
+	srcETag, dstETag := srcEntry.etag, dstEntry.etag
+	var err error
+	if srcETag == "" && dstETag != "" && !src.IsRemote() {
+		srcETag, err = fileMD5(src.localPath(relPath))
+	}
+	if dstETag == "" && srcETag != "" && !dst.IsRemote() {
+		dstETag, err = fileMD5(dst.localPath(relPath))
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if srcETag != "" && dstETag != "" {
+		return srcETag != dstETag, nil
+	}
+	return srcEntry.modTime.After(dstEntry.modTime), nil
+}
+
+func syncIncluded(relPath string, opts SyncOptions) bool {
+	if len(opts.Include) > 0 && !matchesAnyGlob(relPath, opts.Include) {
+		return false
+	}
+	return !matchesAnyGlob(relPath, opts.Exclude)
+}
+
+func matchesAnyGlob(relPath string, globs []string) bool {
+	for _, glob := range globs {
+		name := relPath
+		if !strings.Contains(glob, "/") {
+			name = path.Base(relPath)
+		}
+		if ok, _ := path.Match(glob, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// singlePartETag returns the md5 contained in etag, or an empty string for
+// the etags of multipart uploads, which are not the md5 of the content.
+func singlePartETag(etag string) string {
+	etag = strings.Trim(etag, `"`)
+	if strings.Contains(etag, "-") {
+		return ""
+	}
+	return etag
+}
+
+func fileMD5(filePath string) (string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	hash := md5.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,157 @@
+package s3lib
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeSyncFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		filePath := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+			t.Fatalf("create directory failed: %v", err)
+		}
+		if err := os.WriteFile(filePath, []byte(content), 0o600); err != nil {
+			t.Fatalf("write file failed: %v", err)
+		}
+	}
+}
+
+func syncKeys(t *testing.T, client Client, bucket, prefix string) map[string]string {
+	t.Helper()
+	keys := map[string]string{}
+	objects, err := client.ListObjectsRecursive(context.Background(), bucket, prefix).NextPage(context.Background())
+	if err != nil {
+		t.Fatalf("list objects failed: %v", err)
+	}
+	for _, object := range objects {
+		keys[*object.Object.Key] = *object.Object.ETag
+	}
+	return keys
+}
+
+func TestSyncLocalToRemote(t *testing.T) {
+	dir := t.TempDir()
+	writeSyncFiles(t, dir, map[string]string{
+		"a.txt":       "a",
+		"sub/b.txt":   "bb",
+		"sub/c.log":   "log",
+		"changed.txt": "new content",
+		"same.txt":    "same",
+	})
+	client := NewMemoryClientFactory().
+		WithBucket("bucket", "us-east-1", time.Now()).
+		WithObject("bucket", "backup/changed.txt", 11, time.Now(), md5Hex([]byte("old content")), "STANDARD", []byte("old content")).
+		WithObject("bucket", "backup/same.txt", 4, time.Now(), md5Hex([]byte("same")), "STANDARD", []byte("same")).
+		WithObject("bucket", "backup/stale.txt", 5, time.Now(), "etag", "STANDARD", []byte("stale")).
+		WithObject("bucket", "backup/keep.log", 4, time.Now(), "etag", "STANDARD", []byte("keep")).
+		Build()
+	ctx := context.Background()
+
+	src := LocalSyncLocation(dir)
+	dst := RemoteSyncLocation(client, "bucket", "backup/")
+	opts := SyncOptions{Delete: true, Exclude: []string{"*.log"}}
+
+	plan, err := PlanSync(ctx, src, dst, opts)
+	if err != nil {
+		t.Fatalf("plan failed: %v", err)
+	}
+	want := []SyncAction{
+		{Kind: SyncCopy, Path: "a.txt", Size: 1},
+		{Kind: SyncUpdate, Path: "changed.txt", Size: 11},
+		{Kind: SyncCopy, Path: "sub/b.txt", Size: 2},
+		{Kind: SyncDelete, Path: "stale.txt", Size: 5},
+	}
+	if len(plan.Actions) != len(want) {
+		t.Fatalf("unexpected actions %#v", plan.Actions)
+	}
+	for i := range want {
+		if plan.Actions[i] != want[i] {
+			t.Fatalf("unexpected action %d: %#v", i, plan.Actions[i])
+		}
+	}
+	if plan.Summary() != "2 new, 1 changed, 1 deleted, 1 unchanged, 14 bytes to transfer" {
+		t.Fatalf("unexpected summary %q", plan.Summary())
+	}
+	if len(plan.WithoutDeletes().Actions) != 3 || len(plan.Actions) != 4 {
+		t.Fatal("expected WithoutDeletes to drop the delete action only")
+	}
+
+	var last int64
+	if err := plan.Run(ctx, func(transferred, total int64) { last = transferred }); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if last != 14 {
+		t.Fatalf("expected progress 14, got %d", last)
+	}
+
+	keys := syncKeys(t, client, "bucket", "backup/")
+	for _, key := range []string{"backup/a.txt", "backup/sub/b.txt", "backup/changed.txt", "backup/same.txt", "backup/keep.log"} {
+		if _, ok := keys[key]; !ok {
+			t.Fatalf("expected %s in %v", key, keys)
+		}
+	}
+	if _, ok := keys["backup/stale.txt"]; ok {
+		t.Fatal("expected stale.txt to be deleted")
+	}
+	if keys["backup/changed.txt"] != md5Hex([]byte("new content")) {
+		t.Fatal("expected changed.txt to be updated")
+	}
+
+	plan, err = PlanSync(ctx, src, dst, opts)
+	if err != nil {
+		t.Fatalf("plan failed: %v", err)
+	}
+	if len(plan.Actions) != 0 || plan.Unchanged != 4 {
+		t.Fatalf("expected nothing to sync after run, got %#v", plan)
+	}
+}
+
+func TestSyncRemoteToLocalAndRemote(t *testing.T) {
+	now := time.Now()
+	src := NewMemoryClientFactory().
+		WithBucket("src", "us-east-1", now).
+		WithObject("src", "data/a.txt", 1, now, "e1", "STANDARD", []byte("a")).
+		WithObject("src", "data/dir/", 0, now, "e2", "STANDARD", nil).
+		WithObject("src", "data/dir/b.txt", 2, now, "e3", "STANDARD", []byte("bb")).
+		Build()
+	dst := NewMemoryClientFactory().WithBucket("dst", "us-east-1", now).Build()
+	ctx := context.Background()
+
+	dir := filepath.Join(t.TempDir(), "missing")
+	plan, err := PlanSync(ctx, RemoteSyncLocation(src, "src", "data/"), LocalSyncLocation(dir), SyncOptions{Include: []string{"dir/*"}})
+	if err != nil {
+		t.Fatalf("plan failed: %v", err)
+	}
+	if err := plan.Run(ctx, nil); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if got, err := os.ReadFile(filepath.Join(dir, "dir", "b.txt")); err != nil || string(got) != "bb" {
+		t.Fatalf("expected downloaded file, got %q, %v", got, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "a.txt")); err == nil {
+		t.Fatal("expected a.txt not to be included")
+	}
+
+	plan, err = PlanSync(ctx, RemoteSyncLocation(src, "src", "data/"), RemoteSyncLocation(dst, "dst", "copy/"), SyncOptions{})
+	if err != nil {
+		t.Fatalf("plan failed: %v", err)
+	}
+	if err := plan.Run(ctx, nil); err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if keys := syncKeys(t, dst, "dst", ""); len(keys) != 2 {
+		t.Fatalf("expected two copied objects, got %v", keys)
+	}
+
+	if _, err := PlanSync(ctx, LocalSyncLocation(dir), LocalSyncLocation(dir), SyncOptions{}); err == nil {
+		t.Fatal("expected error for local to local sync")
+	}
+	if _, err := PlanSync(ctx, LocalSyncLocation(dir), RemoteSyncLocation(dst, "dst", ""), SyncOptions{Exclude: []string{"["}}); err == nil {
+		t.Fatal("expected error for invalid glob")
+	}
+}
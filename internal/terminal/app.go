@@ -1,15 +1,38 @@
 package terminal
 
 import (
+	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
+	"github.com/schidstorm/s3tool/internal/cli"
 	"github.com/schidstorm/s3tool/internal/s3lib"
 )
 
 // var activeApp *App
 
+// transferConcurrency returns the number of transfers running at the same
+// time.
+func transferConcurrency() int {
+	return max(cli.Config.Transfers.Concurrency, 1)
+}
+
+// partOptions returns optFns after the transfer options setting the part size
+// and concurrency of the config.
+func partOptions(optFns ...func(*s3lib.TransferOptions)) []func(*s3lib.TransferOptions) {
+	return append([]func(*s3lib.TransferOptions){
+		s3lib.WithPartSize(int64(cli.Config.Transfers.PartSize)),
+		s3lib.WithConcurrency(cli.Config.Transfers.PartConcurrency),
+	}, optFns...)
+}
+
+// transfersKey opens the transfers page from every page.
+var transfersKey = EventKey(tcell.KeyCtrlT, 't', tcell.ModCtrl)
+
 type App struct {
 	*tview.Application
-	root *RootPage
+	root      *RootPage
+	transfers *TransferManager
+	clipboard *Clipboard
+	screen    tcell.Screen
 }
 
 func NewApp(page PageContent, loaders ...s3lib.ConnectorLoader) *App {
@@ -17,7 +40,10 @@ func NewApp(page PageContent, loaders ...s3lib.ConnectorLoader) *App {
 	app := &App{
 		root:        root,
 		Application: tview.NewApplication(),
+		transfers:   NewTransferManager(transferConcurrency()),
+		clipboard:   NewClipboard(),
 	}
+	app.setupTransfers()
 
 	// activeApp = app
 	if page == nil {
@@ -34,13 +60,61 @@ func (a *App) CreateContext() Context {
 		WithOpenPageFunc(a.OpenPage).
 		WithErrorFunc(a.SetError).
 		WithModalFunc(a.Modal).
-		WithSuspendAppFunc(a.Suspend)
+		WithSuspendAppFunc(a.Suspend).
+		WithTransfers(a.transfers).
+		WithClipboard(a.clipboard).
+		WithSystemClipboardFunc(a.SetSystemClipboard).
+		WithQueueUpdateFunc(func(f func()) {
+			a.QueueUpdateDraw(f)
+		})
+}
+
+func (a *App) setupTransfers() {
+	a.transfers.SetQueueUpdateFunc(func(f func()) {
+		a.QueueUpdateDraw(f)
+	})
+
+	var transfersPage *TransfersPage
+	a.root.SetGlobalHotkey(transfersKey, Hotkey{
+		Title: "Transfers",
+		Handler: func(event *tcell.EventKey) *tcell.EventKey {
+			if page := a.root.currentPage(); page != nil && page.content == transfersPage {
+				return nil
+			}
+			if transfersPage == nil {
+				transfersPage = NewTransfersPage(a.CreateContext())
+			}
+			a.OpenPage(transfersPage)
+			return nil
+		},
+	})
 }
 
 func (a *App) Run() error {
+	// the screen is created here rather than by tview to reach its clipboard
+	if a.screen == nil {
+		screen, err := tcell.NewScreen()
+		if err != nil {
+			return err
+		}
+		a.screen = screen
+		a.SetScreen(screen)
+	}
 	return a.Application.SetRoot(a.root, true).Run()
 }
 
+// SetSystemClipboard sends text to the clipboard of the terminal with an
+// OSC 52 escape sequence, which also works over SSH. It reports false if
+// there is no screen yet. Whether the text arrives cannot be known, as
+// terminals without support for the sequence ignore it.
+func (a *App) SetSystemClipboard(text string) bool {
+	if a.screen == nil {
+		return false
+	}
+	a.screen.SetClipboard([]byte(text))
+	return true
+}
+
 func (a *App) Modal(p ModalBuilder) {
 	a.root.Modal(p)
 }
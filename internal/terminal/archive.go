@@ -0,0 +1,64 @@
+package terminal
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/schidstorm/s3tool/internal/s3lib"
+)
+
+// viewArchiveMember extracts the member key of archive in the background and
+// shows it. Compressed members are shown decompressed.
+func viewArchiveMember(c Context, archive *s3lib.Archive, key string) {
+	name := archive.MemberName(key)
+
+	var tmpDir, tmpFilePath string
+	c.Transfers().Submit(NewTransfer(TransferDownload, c.Bucket(), key,
+		func(ctx context.Context, progress func(transferred, total int64)) error {
+			var err error
+			tmpDir, err = os.MkdirTemp("", "s3tool")
+			if err != nil {
+				return err
+			}
+			tmpFilePath = filepath.Join(tmpDir, filepath.Base(name))
+			err = archive.ExtractMember(ctx, name, tmpFilePath, progress)
+			if err != nil {
+				_ = os.RemoveAll(tmpDir)
+			}
+			return err
+		},
+		func(err error) {
+			if err == nil {
+				err = showExtractedMember(c, tmpDir, name, tmpFilePath)
+			}
+			if err != nil && !errors.Is(err, context.Canceled) {
+				c.SetError(err)
+			}
+		},
+	))
+}
+
+func showExtractedMember(c Context, tmpDir, name, tmpFilePath string) error {
+	defer func() {
+		_ = os.RemoveAll(tmpDir)
+	}()
+
+	filePath, _, err := decompressFile(tmpDir, name, tmpFilePath)
+	if err != nil {
+		return err
+	}
+	return ShowFile(c, filePath)
+}
+
+// saveArchiveMember extracts the member key of archive to filePath in the
+// background.
+func saveArchiveMember(c Context, archive *s3lib.Archive, key, filePath string, done func(err error)) {
+	c.Transfers().Submit(NewTransfer(TransferDownload, c.Bucket(), key,
+		func(ctx context.Context, progress func(transferred, total int64)) error {
+			return archive.ExtractMember(ctx, archive.MemberName(key), filePath, progress)
+		},
+		transferDone(c, done),
+	))
+}
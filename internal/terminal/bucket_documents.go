@@ -0,0 +1,107 @@
+package terminal
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/schidstorm/s3tool/internal/s3lib"
+)
+
+// maxDiffLines limits the changes shown before a bucket document is applied.
+const maxDiffLines = 20
+
+// editBucketDocument opens a bucket document of the bucket of c in the editor
+// and applies it after the user confirmed the changes. done is called after
+// the document was applied.
+func editBucketDocument(c Context, doc s3lib.BucketDocument, done func()) {
+	current, err := c.S3Client().GetBucketDocument(context.Background(), c.Bucket(), doc)
+	if err != nil {
+		c.SetError(err)
+		return
+	}
+
+	tmpDir, err := os.MkdirTemp("", "s3tool")
+	if err != nil {
+		c.SetError(err)
+		return
+	}
+	tmpFilePath := filepath.Join(tmpDir, c.Bucket()+"-"+string(doc)+".json")
+	if err := os.WriteFile(tmpFilePath, []byte(current), 0o600); err != nil {
+		_ = os.RemoveAll(tmpDir)
+		c.SetError(err)
+		return
+	}
+
+	editBucketDocumentFile(c, doc, current, tmpDir, tmpFilePath, done)
+}
+
+// editBucketDocumentFile opens the editor until the document in tmpFilePath
+// is valid or the user discards it. It takes over tmpDir.
+func editBucketDocumentFile(c Context, doc s3lib.BucketDocument, current, tmpDir, tmpFilePath string, done func()) {
+	discard := func() {
+		_ = os.RemoveAll(tmpDir)
+	}
+
+	if err := EditFile(c, tmpFilePath); err != nil {
+		discard()
+		c.SetError(err)
+		return
+	}
+
+	data, err := os.ReadFile(tmpFilePath)
+	if err != nil {
+		discard()
+		c.SetError(err)
+		return
+	}
+
+	document, err := s3lib.ParseBucketDocument(doc, data)
+	if err != nil {
+		choiceModal(c, fmt.Sprintf("The %s is invalid:\n%s", doc, err), []string{"Edit", "Discard"}, func(buttonLabel string) {
+			if buttonLabel == "Edit" {
+				editBucketDocumentFile(c, doc, current, tmpDir, tmpFilePath, done)
+				return
+			}
+			discard()
+		})
+		return
+	}
+
+	diff := lineDiff(current, document)
+	if len(diff) == 0 {
+		discard()
+		return
+	}
+
+	message := fmt.Sprintf("Apply changes to the %s of %s?", doc, c.Bucket())
+	if document == "" {
+		message = fmt.Sprintf("Remove the %s of %s?", doc, c.Bucket())
+	}
+	more, lines := limitSlice(diff, maxDiffLines)
+	message += "\n\n" + strings.Join(lines, "\n")
+	if more {
+		message += fmt.Sprintf("\n... %d more changes", len(diff)-maxDiffLines)
+	}
+
+	choiceModal(c, message, []string{"Apply", "Edit", "Discard"}, func(buttonLabel string) {
+		switch buttonLabel {
+		case "Apply":
+			discard()
+			err := c.S3Client().PutBucketDocument(context.Background(), c.Bucket(), doc, document)
+			if err != nil {
+				c.SetError(err)
+				return
+			}
+			if done != nil {
+				done()
+			}
+		case "Edit":
+			editBucketDocumentFile(c, doc, current, tmpDir, tmpFilePath, done)
+		default:
+			discard()
+		}
+	})
+}
@@ -0,0 +1,122 @@
+package terminal
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/schidstorm/s3tool/internal/s3lib"
+)
+
+// BucketPage shows the configuration of a bucket.
+type BucketPage struct {
+	*tview.Table
+
+	context    Context
+	searchTerm string
+}
+
+func NewBucketPage(context Context) *BucketPage {
+	table := tview.NewTable().SetSelectable(true, false)
+
+	return &BucketPage{
+		Table:   table,
+		context: context,
+	}
+}
+
+func (b *BucketPage) SetSearch(search string) {
+	b.searchTerm = search
+	err := b.Load()
+	if err != nil {
+		b.context.SetError(err)
+	}
+}
+
+func (b *BucketPage) Context() Context {
+	return b.context
+}
+
+func (b *BucketPage) Title() string {
+	return "Bucket - " + b.context.Bucket()
+}
+
+// bucketHotkeyKeys are the keys of the hotkeys of BucketPage.
+var bucketHotkeyKeys = hotkeyKeys{
+	"Reload":         EventKey(tcell.KeyRune, 'r', 0),
+	"Edit Policy":    EventKey(tcell.KeyRune, 'p', 0),
+	"Edit Lifecycle": EventKey(tcell.KeyRune, 'l', 0),
+	"Edit CORS":      EventKey(tcell.KeyRune, 'c', 0),
+}
+
+func (b *BucketPage) Hotkeys() map[tcell.EventKey]Hotkey {
+	return bucketHotkeyKeys.bind(map[string]func(*tcell.EventKey) *tcell.EventKey{
+		"Reload": func(event *tcell.EventKey) *tcell.EventKey {
+			if err := b.Load(); err != nil {
+				b.context.SetError(err)
+			}
+			return nil
+		},
+		"Edit Policy":    b.editDocumentHandler(s3lib.BucketPolicyDocument),
+		"Edit Lifecycle": b.editDocumentHandler(s3lib.BucketLifecycleDocument),
+		"Edit CORS":      b.editDocumentHandler(s3lib.BucketCORSDocument),
+	})
+}
+
+func (b *BucketPage) editDocumentHandler(doc s3lib.BucketDocument) func(*tcell.EventKey) *tcell.EventKey {
+	return func(event *tcell.EventKey) *tcell.EventKey {
+		editBucketDocument(b.context, doc, func() {
+			if err := b.Load(); err != nil {
+				b.context.SetError(err)
+			}
+		})
+		return nil
+	}
+}
+
+func (b *BucketPage) Load() error {
+	settings, err := b.context.S3Client().GetBucketConfiguration(context.Background(), b.context.Bucket())
+	if err != nil {
+		return err
+	}
+
+	b.Clear()
+	var rowIndex int
+	for _, setting := range settings {
+		values, style := bucketSettingValues(setting)
+		if !matchAnyItems(b.searchTerm, append([]string{setting.Name}, values...)) {
+			continue
+		}
+
+		for i, value := range values {
+			title := ""
+			if i == 0 {
+				title = setting.Name
+			}
+			b.SetCell(rowIndex, 0, tview.NewTableCell(title).
+				SetStyle(DefaultStyle.Foreground(DefaultTheme.LabelColor).Bold(true)).
+				SetAlign(tview.AlignLeft))
+			b.SetCell(rowIndex, 1, tview.NewTableCell(value).
+				SetStyle(style).
+				SetSelectedStyle(DefaultStyle.Foreground(DefaultTheme.PrimaryColor)).
+				SetAlign(tview.AlignLeft))
+			rowIndex++
+		}
+	}
+
+	return nil
+}
+
+// bucketSettingValues returns the rows shown for setting and their style.
+func bucketSettingValues(setting s3lib.BucketSetting) ([]string, tcell.Style) {
+	switch {
+	case errors.Is(setting.Err, s3lib.ErrNotSupported):
+		return []string{"not supported"}, DefaultStyle.Foreground(DefaultTheme.SecondaryColor)
+	case setting.Err != nil:
+		return []string{setting.Err.Error()}, DefaultStyle.Foreground(DefaultTheme.ErrorColor)
+	case len(setting.Values) == 0:
+		return []string{"not configured"}, DefaultStyle.Foreground(DefaultTheme.SecondaryColor)
+	}
+	return setting.Values, DefaultStyle
+}
@@ -0,0 +1,90 @@
+package terminal
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/schidstorm/s3tool/internal/s3lib"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBucketPage(t *testing.T) {
+	client := s3lib.NewMemoryClientFactory().
+		WithBucket("test-bucket", "eu-central-1", time.Now()).
+		WithVersioning("test-bucket").
+		Build()
+
+	var opened PageContent
+	buckets := NewBucketsPage(NewContext().WithClient(client).WithErrorFunc(func(err error) {
+		t.Error(err)
+	}).WithOpenPageFunc(func(page PageContent) {
+		opened = page
+	}))
+	assert.NoError(t, buckets.Load())
+	buckets.tviewTable.Select(1, 0)
+	pressHotkey(t, buckets, tcell.NewEventKey(tcell.KeyRune, 'i', 0))
+
+	page, ok := opened.(*BucketPage)
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.Equal(t, "Bucket - test-bucket", page.Title())
+	assert.NoError(t, page.Load())
+	rows := getTableRows(page.Table)
+	assert.Equal(t, []string{"Region", "eu-central-1"}, rows[0])
+	assert.Equal(t, []string{"Versioning", "Enabled"}, rows[1])
+	assert.Equal(t, []string{"Encryption", "not configured"}, rows[2])
+	assert.Len(t, rows, 11)
+
+	page.SetSearch("version")
+	assert.Equal(t, [][]string{{"Versioning", "Enabled"}}, getTableRows(page.Table))
+}
+
+func TestBucketSettingValues(t *testing.T) {
+	values, _ := bucketSettingValues(s3lib.BucketSetting{Name: "CORS", Err: s3lib.ErrNotSupported})
+	assert.Equal(t, []string{"not supported"}, values)
+
+	values, style := bucketSettingValues(s3lib.BucketSetting{Name: "Policy", Err: errors.New("access denied")})
+	assert.Equal(t, []string{"access denied"}, values)
+	fg, _, _ := style.Decompose()
+	assert.Equal(t, DefaultTheme.ErrorColor, fg)
+
+	values, _ = bucketSettingValues(s3lib.BucketSetting{Name: "Lifecycle", Values: []string{"a", "b"}})
+	assert.Equal(t, []string{"a", "b"}, values)
+}
+
+func TestBucketPageEditDocument(t *testing.T) {
+	client := s3lib.NewMemoryClientFactory().
+		WithBucket("bucket", "eu-central-1", time.Now()).
+		Build()
+
+	useEditor(t, `sh -c 'printf "CORSRules:\n  - AllowedMethods: [GET]\n    AllowedOrigins: [\"*\"]\n" > "$1"' sh`)
+
+	var message string
+	page := NewBucketPage(formTestContext(t, client, "Apply", nil).
+		WithSuspendAppFunc(func(f func()) bool {
+			f()
+			return true
+		}).
+		WithModalFunc(func(build ModalBuilder) {
+			modal := build(func() {}).(*Modal)
+			message = modal.text
+			modal.done("Apply", nil)
+		}))
+	assert.NoError(t, page.Load())
+	pressHotkey(t, page, tcell.NewEventKey(tcell.KeyRune, 'c', 0))
+
+	assert.Contains(t, message, `+        "GET"`)
+	document, err := client.GetBucketDocument(t.Context(), "bucket", s3lib.BucketCORSDocument)
+	assert.NoError(t, err)
+	assert.Contains(t, document, `"AllowedOrigins"`)
+	assert.Contains(t, getTableRows(page.Table), []string{"CORS", "GET from *"})
+}
+
+func TestLineDiff(t *testing.T) {
+	assert.Equal(t, []string{"-b", "+c", "+d"}, lineDiff("a\nb\n", "a\nc\nd\n"))
+	assert.Equal(t, []string{"+a"}, lineDiff("", "a\n"))
+	assert.Empty(t, lineDiff("a\nb", "a\nb\n"))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
@@ -19,9 +20,21 @@ type BucketsPage struct {
 
 func NewBucketsPage(context Context) *BucketsPage {
 	listPage := NewListPage[types.Bucket]()
-	listPage.AddColumn("Bucket Name", func(item types.Bucket) string { return aws.ToString(item.Name) })
-	listPage.AddColumn("Region", func(item types.Bucket) string { return aws.ToString(item.BucketRegion) })
-	listPage.AddColumn("Created At", func(item types.Bucket) string { return humanizeTime(item.CreationDate) })
+	listPage.AddSortableColumn("Bucket Name", func(item types.Bucket) string {
+		return aws.ToString(item.Name)
+	}, func(a, b types.Bucket) int {
+		return strings.Compare(aws.ToString(a.Name), aws.ToString(b.Name))
+	})
+	listPage.AddSortableColumn("Region", func(item types.Bucket) string {
+		return aws.ToString(item.BucketRegion)
+	}, func(a, b types.Bucket) int {
+		return strings.Compare(aws.ToString(a.BucketRegion), aws.ToString(b.BucketRegion))
+	})
+	listPage.AddSortableColumn("Created At", func(item types.Bucket) string {
+		return humanizeTime(item.CreationDate)
+	}, func(a, b types.Bucket) int {
+		return aws.ToTime(a.CreationDate).Compare(aws.ToTime(b.CreationDate))
+	})
 
 	box := &BucketsPage{
 		ListPage: listPage,
@@ -43,81 +56,85 @@ func (b *BucketsPage) Context() Context {
 	return b.context
 }
 
+// bucketsHotkeyKeys are the keys of the hotkeys of BucketsPage.
+var bucketsHotkeyKeys = hotkeyKeys{
+	"New Bucket":    EventKey(tcell.KeyRune, 'n', 0),
+	"Sort":          EventKey(tcell.KeyRune, 'o', 0),
+	"Bucket Info":   EventKey(tcell.KeyRune, 'i', 0),
+	"Delete Bucket": EventKey(tcell.KeyRune, 'd', 0),
+}
+
 func (b *BucketsPage) Hotkeys() map[tcell.EventKey]Hotkey {
-	return map[tcell.EventKey]Hotkey{
-		EventKey(tcell.KeyRune, 'n', 0): {
-			Title:   "New Bucket",
-			Handler: func(event *tcell.EventKey) *tcell.EventKey { b.newBucketForm(); return nil },
+	return bucketsHotkeyKeys.bind(map[string]func(*tcell.EventKey) *tcell.EventKey{
+		"New Bucket": func(event *tcell.EventKey) *tcell.EventKey { b.newBucketForm(); return nil },
+		"Sort":       b.SortHotkey().Handler,
+		"Bucket Info": func(event *tcell.EventKey) *tcell.EventKey {
+			if bucket, ok := b.GetSelectedRow(); ok {
+				b.context.OpenPage(NewBucketPage(b.context.WithBucket(aws.ToString(bucket.Name))))
+			}
+			return nil
 		},
-		EventKey(tcell.KeyRune, 'd', 0): {
-			Title: "Delete Bucket",
-			Handler: func(event *tcell.EventKey) *tcell.EventKey {
-				items := b.table.GetHighlightedItems()
-				if len(items) == 0 {
-					if obj, ok := b.GetSelectedRow(); ok {
-						items = []types.Bucket{obj}
-					}
+		"Delete Bucket": func(event *tcell.EventKey) *tcell.EventKey {
+			items := b.table.GetHighlightedItems()
+			if len(items) == 0 {
+				if obj, ok := b.GetSelectedRow(); ok {
+					items = []types.Bucket{obj}
 				}
+			}
 
-				if len(items) == 0 {
-					return nil
-				}
-
-				bucketKey := "bucket"
-				if len(items) != 1 {
-					bucketKey = "buckets\n"
+			if len(items) == 0 {
+				return nil
+			}
+
+			bucketKey := "bucket"
+			if len(items) != 1 {
+				bucketKey = "buckets\n"
+			}
+			modalMessage := fmt.Sprintf(
+				"Are you sure you want to delete %d %s? \n%s",
+				len(items),
+				bucketKey,
+				limitedItemsAsString(
+					items,
+					func(item types.Bucket) string {
+						return aws.ToString(item.Name)
+					},
+				),
+			)
+
+			b.context.Modal(ConfirmModal(modalMessage, func() {
+				for _, item := range items {
+					b.deleteBucket(item)
 				}
-				modalMessage := fmt.Sprintf(
-					"Are you sure you want to delete %d %s? \n%s",
-					len(items),
-					bucketKey,
-					limitedItemsAsString(
-						items,
-						func(item types.Bucket) string {
-							return aws.ToString(item.Name)
-						},
-					),
-				)
-
-				b.context.Modal(ConfirmModal(modalMessage, func() {
-					for _, item := range items {
-						b.deleteBucket(item)
-					}
-				}))
+			}))
 
-				return nil
-			},
+			return nil
 		},
-	}
+	})
 }
 
 func (b *BucketsPage) deleteBucket(bucket types.Bucket) {
-	err := b.context.S3Client().DeleteBucket(context.Background(), aws.ToString(bucket.Name))
-	if err != nil {
-		b.context.SetError(err)
-	}
+	client, name := b.context.S3Client(), aws.ToString(bucket.Name)
 
-	err = b.Load()
-	if err != nil {
-		b.context.SetError(err)
-	}
+	b.context.Transfers().Submit(NewTransfer(TransferDelete, name, "",
+		func(ctx context.Context, progress func(transferred, total int64)) error {
+			return client.DeleteBucket(ctx, name)
+		},
+		func(err error) {
+			if err != nil && !errors.Is(err, context.Canceled) {
+				b.context.SetError(err)
+			}
+
+			err = b.Load()
+			if err != nil {
+				b.context.SetError(err)
+			}
+		},
+	))
 }
 
 func (b *BucketsPage) Load() error {
-	b.ClearRows()
-
-	paginator := b.context.S3Client().ListBuckets(context.Background())
-	var buckets []types.Bucket
-	for paginator.HasMorePages() {
-		page, err := paginator.NextPage(context.Background())
-		if err != nil {
-			return err
-		}
-		buckets = append(buckets, page...)
-	}
-
-	b.AddAll(buckets)
-	return nil
+	return b.LoadPages(b.context, b.context.S3Client().ListBuckets(context.Background()))
 }
 
 func (b *BucketsPage) newBucketForm() {
@@ -0,0 +1,80 @@
+package terminal
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/schidstorm/s3tool/internal/s3lib"
+)
+
+// MarkedObjects are objects marked to be pasted into another location,
+// possibly of another profile.
+type MarkedObjects struct {
+	Client s3lib.Client
+	Bucket string
+	// Prefix is the part of the keys that is replaced by the destination
+	// prefix when pasting.
+	Prefix  string
+	Objects []s3lib.Object
+}
+
+// Clipboard holds the marked objects. It is shared by all pages, so objects
+// marked in one profile can be pasted in another.
+type Clipboard struct {
+	mu     sync.Mutex
+	marked *MarkedObjects
+}
+
+func NewClipboard() *Clipboard {
+	return &Clipboard{}
+}
+
+// Mark replaces the marked objects.
+func (c *Clipboard) Mark(marked MarkedObjects) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.marked = &marked
+}
+
+// Marked returns the marked objects, if any.
+func (c *Clipboard) Marked() (MarkedObjects, bool) {
+	if c == nil {
+		return MarkedObjects{}, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.marked == nil {
+		return MarkedObjects{}, false
+	}
+	return *c.marked, true
+}
+
+// pasteObjects copies marked into dstPrefix of the bucket of c in the
+// background. Objects are copied on the server side within one client and
+// streamed between clients.
+func pasteObjects(c Context, marked MarkedObjects, dstPrefix string, done func(err error)) {
+	client, bucket, total := c.S3Client(), c.Bucket(), objectsSize(marked.Objects)
+
+	c.Transfers().Submit(NewTransfer(TransferCopy, bucket, dstPrefix,
+		func(ctx context.Context, progress func(transferred, total int64)) error {
+			return forEachObject(marked.Objects, total, progress, func(key string, objectProgress func(int64)) error {
+				dstKey := dstPrefix + strings.TrimPrefix(key, marked.Prefix)
+				return s3lib.TransferObject(ctx, marked.Client, marked.Bucket, key, client, bucket, dstKey, partOptions(s3lib.WithProgress(func(transferred, _ int64) {
+					objectProgress(transferred)
+				}))...)
+			})
+		},
+		transferDone(c, done),
+	))
+}
+
+// markedLabel describes the origin of marked for confirmations.
+func markedLabel(marked MarkedObjects) string {
+	if len(marked.Objects) == 1 {
+		return marked.Bucket + "/" + aws.ToString(marked.Objects[0].Object.Key)
+	}
+	return marked.Bucket + "/" + marked.Prefix
+}
@@ -0,0 +1,53 @@
+package terminal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/schidstorm/s3tool/internal/s3lib"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClipboardMarked(t *testing.T) {
+	var nilClipboard *Clipboard
+	_, ok := nilClipboard.Marked()
+	assert.False(t, ok)
+
+	clipboard := NewClipboard()
+	_, ok = clipboard.Marked()
+	assert.False(t, ok)
+
+	clipboard.Mark(MarkedObjects{Bucket: "bucket", Prefix: "photos/"})
+	marked, ok := clipboard.Marked()
+	assert.True(t, ok)
+	assert.Equal(t, "photos/", marked.Prefix)
+}
+
+func TestPasteObjectsBetweenProfiles(t *testing.T) {
+	src := prefixTestContext(t)
+	objects, err := expandObjects(context.Background(), src, []s3lib.Object{s3lib.NewObjectDirectory("photos/2024/")})
+	assert.NoError(t, err)
+
+	dstClient := s3lib.NewMemoryClientFactory().
+		WithBucket("backup", "us-east-1", time.Now()).
+		Build()
+	dst := NewContext().
+		WithClient(dstClient).
+		WithBucket("backup").
+		WithObjectKey("archive/").
+		WithErrorFunc(func(err error) { t.Error(err) })
+
+	marked := MarkedObjects{Client: src.S3Client(), Bucket: "bucket", Prefix: "photos/", Objects: objects}
+	assert.Equal(t, "bucket/photos/", markedLabel(marked))
+
+	transfers := NewTransferManager(1)
+	done := make(chan error, 1)
+	pasteObjects(dst.WithTransfers(transfers), marked, "archive/", func(err error) { done <- err })
+	assert.NoError(t, <-done)
+
+	assert.Equal(t, TransferCopy, transfers.Transfers()[0].Kind)
+	assert.Equal(t, int64(3), transfers.Transfers()[0].Snapshot().Transferred)
+	assert.Equal(t, []string{"archive/2024/a.jpg", "archive/2024/b.jpg"}, listKeys(t, dst, "archive/"))
+	assert.Len(t, listKeys(t, src, "photos/2024/"), 2)
+}
@@ -0,0 +1,201 @@
+package terminal
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// codec is a compression format that objects are transparently decompressed
+// from when they are viewed or edited.
+type codec struct {
+	name  string
+	ext   string
+	magic []byte
+	// decompress and compress stream src into dst.
+	decompress func(dst io.Writer, src io.Reader) error
+	compress   func(dst io.Writer, src io.Reader) error
+	// compressCommand is the program compress runs, if any
+	compressCommand string
+}
+
+var codecs = []codec{
+	{
+		name:  "gzip",
+		ext:   ".gz",
+		magic: []byte{0x1f, 0x8b},
+		decompress: func(dst io.Writer, src io.Reader) error {
+			r, err := gzip.NewReader(src)
+			if err != nil {
+				return err
+			}
+			defer func() {
+				_ = r.Close()
+			}()
+			_, err = io.Copy(dst, r)
+			return err
+		},
+		compress: func(dst io.Writer, src io.Reader) error {
+			w := gzip.NewWriter(dst)
+			if _, err := io.Copy(w, src); err != nil {
+				return err
+			}
+			return w.Close()
+		},
+	},
+	{
+		name:  "zstd",
+		ext:   ".zst",
+		magic: []byte{0x28, 0xb5, 0x2f, 0xfd},
+		decompress: func(dst io.Writer, src io.Reader) error {
+			r, err := zstd.NewReader(src)
+			if err != nil {
+				return err
+			}
+			defer r.Close()
+			_, err = io.Copy(dst, r)
+			return err
+		},
+		compress: func(dst io.Writer, src io.Reader) error {
+			w, err := zstd.NewWriter(dst)
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(w, src); err != nil {
+				return err
+			}
+			return w.Close()
+		},
+	},
+	{
+		name:  "bzip2",
+		ext:   ".bz2",
+		magic: []byte("BZh"),
+		decompress: func(dst io.Writer, src io.Reader) error {
+			_, err := io.Copy(dst, bzip2.NewReader(src))
+			return err
+		},
+		// the standard library only reads bzip2
+		compressCommand: "bzip2",
+		compress: func(dst io.Writer, src io.Reader) error {
+			cmd := exec.Command("bzip2", "-c")
+			cmd.Stdin = src
+			cmd.Stdout = dst
+			if err := cmd.Run(); err != nil {
+				return fmt.Errorf("recompressing with bzip2: %w", err)
+			}
+			return nil
+		},
+	},
+}
+
+// checkCompress returns an error if the program compress runs is not
+// installed, so that it shows before an object is edited rather than when it
+// is saved.
+func (c *codec) checkCompress() error {
+	if c.compressCommand == "" {
+		return nil
+	}
+	if _, err := exec.LookPath(c.compressCommand); err != nil {
+		return fmt.Errorf("%s is not installed to compress %s objects again", c.compressCommand, c.name)
+	}
+	return nil
+}
+
+// detectCodec returns the compression of the file at filePath, recognized by
+// the extension of key or the magic bytes at the start of the file.
+func detectCodec(key, filePath string) (*codec, error) {
+	ext := strings.ToLower(filepath.Ext(key))
+	for i := range codecs {
+		if codecs[i].ext == ext {
+			return &codecs[i], nil
+		}
+	}
+
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	head := make([]byte, 4)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+	for i := range codecs {
+		if bytes.HasPrefix(head[:n], codecs[i].magic) {
+			return &codecs[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// decompressFile decompresses the downloaded object key at filePath into a
+// new file in tmpDir, named like the object without the compression
+// extension. Files that are not compressed are returned as they are, with a
+// nil codec, and so are files whose codec was only guessed from their magic
+// bytes but fail to decompress.
+func decompressFile(tmpDir, key, filePath string) (string, *codec, error) {
+	c, err := detectCodec(key, filePath)
+	if err != nil || c == nil {
+		return filePath, nil, err
+	}
+
+	name := filepath.Base(key)
+	byExtension := strings.EqualFold(filepath.Ext(name), c.ext)
+	if byExtension {
+		name = name[:len(name)-len(c.ext)]
+	}
+	dir, err := os.MkdirTemp(tmpDir, "decompressed")
+	if err != nil {
+		return "", nil, err
+	}
+	decompressedPath := filepath.Join(dir, name)
+
+	if err := convertFile(decompressedPath, filePath, c.decompress); err != nil {
+		if !byExtension {
+			_ = os.RemoveAll(dir)
+			return filePath, nil, nil
+		}
+		return "", nil, fmt.Errorf("decompressing %s with %s: %w", key, c.name, err)
+	}
+	return decompressedPath, c, nil
+}
+
+// convertFile writes src converted by convert to dst.
+func convertFile(dst, src string, convert func(dst io.Writer, src io.Reader) error) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = in.Close()
+	}()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(out)
+	err = convert(w, bufio.NewReader(in))
+	if err == nil {
+		err = w.Flush()
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,166 @@
+package terminal
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/schidstorm/s3tool/internal/s3lib"
+	"github.com/stretchr/testify/assert"
+)
+
+func compressed(t *testing.T, c *codec, data string) []byte {
+	var out bytes.Buffer
+	assert.NoError(t, c.compress(&out, bytes.NewReader([]byte(data))))
+	return out.Bytes()
+}
+
+func decompressed(t *testing.T, c *codec, data []byte) string {
+	var out bytes.Buffer
+	assert.NoError(t, c.decompress(&out, bytes.NewReader(data)))
+	return out.String()
+}
+
+func TestCodecsRoundTrip(t *testing.T) {
+	for i := range codecs {
+		c := &codecs[i]
+		if c.name == "bzip2" {
+			if _, err := exec.LookPath("bzip2"); err != nil {
+				t.Log("bzip2 not installed")
+				continue
+			}
+		}
+
+		data := compressed(t, c, "hello\n")
+		assert.Equal(t, "hello\n", decompressed(t, c, data), c.name)
+
+		// the magic bytes are recognized without extension
+		filePath := filepath.Join(t.TempDir(), "object")
+		assert.NoError(t, os.WriteFile(filePath, data, 0o600))
+		detected, err := detectCodec("object", filePath)
+		assert.NoError(t, err)
+		if assert.NotNil(t, detected, c.name) {
+			assert.Equal(t, c.name, detected.name)
+		}
+	}
+
+	filePath := filepath.Join(t.TempDir(), "plain.txt")
+	assert.NoError(t, os.WriteFile(filePath, []byte("plain"), 0o600))
+	detected, err := detectCodec("plain.txt", filePath)
+	assert.NoError(t, err)
+	assert.Nil(t, detected)
+}
+
+func TestEditCompressedObject(t *testing.T) {
+	gz := &codecs[0]
+	client := s3lib.NewMemoryClientFactory().
+		WithBucket("bucket", "eu-central-1", time.Now()).
+		WithObject("bucket", "logs/app.log.gz", 0, time.Now(), "etag", "STANDARD", compressed(t, gz, "hello\n")).
+		WithContentEncoding("bucket", "logs/app.log.gz", "gzip").
+		Build()
+
+	useEditor(t, `sh -c 'case "$1" in *.gz) exit 1;; esac; echo changed >> "$1"' sh`)
+
+	c := testContextWithClient(client).
+		WithObjectKey("logs/app.log.gz").
+		WithErrorFunc(func(err error) { t.Error(err) })
+	editObject(c)
+
+	filePath := filepath.Join(t.TempDir(), "app.log.gz")
+	assert.NoError(t, client.DownloadFile(context.Background(), "bucket", "logs/app.log.gz", filePath))
+	data, err := os.ReadFile(filePath)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello\nchanged\n", decompressed(t, gz, data))
+
+	obj, err := client.GetObject(context.Background(), "bucket", "logs/app.log.gz")
+	assert.NoError(t, err)
+	assert.Equal(t, "gzip", aws.ToString(obj.ContentEncoding))
+}
+
+func TestViewCompressedObject(t *testing.T) {
+	zst := &codecs[1]
+	client := s3lib.NewMemoryClientFactory().
+		WithBucket("bucket", "eu-central-1", time.Now()).
+		WithObject("bucket", "data", 0, time.Now(), "etag", "STANDARD", compressed(t, zst, "plain text")).
+		Build()
+
+	shown := filepath.Join(t.TempDir(), "shown")
+	useViewer(t, `sh -c 'cat "$1" > "$0"' '`+shown+`'`)
+
+	viewObject(testContextWithClient(client).
+		WithObjectKey("data").
+		WithErrorFunc(func(err error) { t.Error(err) }), "")
+
+	f, err := os.Open(shown)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	data, _ := io.ReadAll(f)
+	assert.Equal(t, "plain text", string(data))
+}
+
+func TestDecompressFileGuessedFromMagicBytes(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "notes")
+	assert.NoError(t, os.WriteFile(filePath, []byte("BZh is how this note starts"), 0o600))
+
+	// the magic bytes were a guess, the file is shown as it is
+	decompressedPath, c, err := decompressFile(dir, "notes", filePath)
+	assert.NoError(t, err)
+	assert.Nil(t, c)
+	assert.Equal(t, filePath, decompressedPath)
+
+	// the extension is not
+	_, _, err = decompressFile(dir, "notes.bz2", filePath)
+	assert.Error(t, err)
+}
+
+func TestEditCompressedObjectWithoutCompressor(t *testing.T) {
+	// bzip2 of "hello\n"
+	data, _ := hex.DecodeString("425a6839314159265359c1c080e2000001410000100244a00030cd00c3462997177245385090c1c080e2")
+	client := s3lib.NewMemoryClientFactory().
+		WithBucket("bucket", "eu-central-1", time.Now()).
+		WithObject("bucket", "notes.txt.bz2", 0, time.Now(), "etag", "STANDARD", data).
+		Build()
+
+	// only sh is installed
+	bin := t.TempDir()
+	sh, err := exec.LookPath("sh")
+	assert.NoError(t, err)
+	assert.NoError(t, os.Symlink(sh, filepath.Join(bin, "sh")))
+	t.Setenv("PATH", bin)
+
+	edited := filepath.Join(t.TempDir(), "edited")
+	shown := filepath.Join(t.TempDir(), "shown")
+	useEditor(t, `sh -c 'echo edited > "$0"' '`+edited+`'`)
+	useViewer(t, `sh -c 'while read -r line; do echo "$line"; done < "$1" > "$0"' '`+shown+`'`)
+
+	var messages []string
+	c := testContextWithClient(client).
+		WithObjectKey("notes.txt.bz2").
+		WithErrorFunc(func(err error) { t.Error(err) }).
+		WithModalFunc(func(build ModalBuilder) {
+			modal := build(func() {}).(*Modal)
+			messages = append(messages, modal.text)
+			modal.done("View", nil)
+		})
+	editObject(c)
+
+	if assert.Len(t, messages, 1) {
+		assert.Contains(t, messages[0], "bzip2 is not installed")
+	}
+	assert.NoFileExists(t, edited)
+	got, err := os.ReadFile(shown)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello\n", string(got))
+}
@@ -17,3 +17,19 @@ func ConfirmModal(message string, onConfirm func()) ModalBuilder {
 		return modal
 	}
 }
+
+// choiceModal shows message with a button for every choice and calls done
+// with the label of the pressed button.
+func choiceModal(c Context, message string, buttons []string, done func(buttonLabel string)) {
+	c.Modal(func(close func()) tview.Primitive {
+		modal := NewModal().
+			SetText(tview.Escape(message)).
+			AddButtons(buttons).
+			SetDoneFunc(func(buttonLabel string, values map[string]string) {
+				close()
+				done(buttonLabel)
+			})
+		modal.SetTitleAlign(tview.AlignLeft)
+		return modal
+	})
+}
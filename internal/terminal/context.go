@@ -15,6 +15,14 @@ type Context interface {
 	SetError(err error)
 	OpenPage(page PageContent)
 	SuspendApp(f func()) bool
+	Transfers() *TransferManager
+	Clipboard() *Clipboard
+	// SetSystemClipboard sends text to the clipboard of the terminal and
+	// reports whether it was sent. Terminals may ignore it.
+	SetSystemClipboard(text string) bool
+	// QueueUpdateFunc returns the function that runs updates on the goroutine
+	// of the UI, or nil if there is no UI.
+	QueueUpdateFunc() func(f func())
 
 	WithClient(client s3lib.Client) Context
 	WithBucket(bucket string) Context
@@ -23,6 +31,10 @@ type Context interface {
 	WithErrorFunc(f func(err error)) Context
 	WithOpenPageFunc(f func(page PageContent)) Context
 	WithSuspendAppFunc(f func(func()) bool) Context
+	WithTransfers(transfers *TransferManager) Context
+	WithClipboard(clipboard *Clipboard) Context
+	WithSystemClipboardFunc(f func(text string) bool) Context
+	WithQueueUpdateFunc(f func(f func())) Context
 }
 
 type contextImpl struct {
@@ -33,6 +45,10 @@ type contextImpl struct {
 	errorFunc  func(err error)
 	openFunc   func(page PageContent)
 	suspendApp func(func()) bool
+	transfers  *TransferManager
+	clipboard  *Clipboard
+	copyFunc   func(text string) bool
+	queueFunc  func(f func())
 }
 
 func NewContext() Context {
@@ -76,6 +92,22 @@ func (c contextImpl) SuspendApp(f func()) bool {
 	return false
 }
 
+func (c contextImpl) Transfers() *TransferManager {
+	return c.transfers
+}
+
+func (c contextImpl) Clipboard() *Clipboard {
+	return c.clipboard
+}
+
+func (c contextImpl) SetSystemClipboard(text string) bool {
+	return c.copyFunc != nil && c.copyFunc(text)
+}
+
+func (c contextImpl) QueueUpdateFunc() func(f func()) {
+	return c.queueFunc
+}
+
 func (c contextImpl) WithClient(client s3lib.Client) Context {
 	c.client = client
 	return c
@@ -110,3 +142,23 @@ func (c contextImpl) WithSuspendAppFunc(f func(func()) bool) Context {
 	c.suspendApp = f
 	return c
 }
+
+func (c contextImpl) WithTransfers(transfers *TransferManager) Context {
+	c.transfers = transfers
+	return c
+}
+
+func (c contextImpl) WithClipboard(clipboard *Clipboard) Context {
+	c.clipboard = clipboard
+	return c
+}
+
+func (c contextImpl) WithSystemClipboardFunc(f func(text string) bool) Context {
+	c.copyFunc = f
+	return c
+}
+
+func (c contextImpl) WithQueueUpdateFunc(f func(f func())) Context {
+	c.queueFunc = f
+	return c
+}
@@ -0,0 +1,49 @@
+package terminal
+
+import "strings"
+
+// lineDiff compares two texts line by line and returns the removed lines
+// prefixed with "-" and the added lines prefixed with "+", in order.
+func lineDiff(oldText, newText string) []string {
+	oldLines, newLines := splitLines(oldText), splitLines(newText)
+
+	// lengths of the longest common subsequences of the line suffixes
+	common := make([][]int, len(oldLines)+1)
+	for i := range common {
+		common[i] = make([]int, len(newLines)+1)
+	}
+	for i := len(oldLines) - 1; i >= 0; i-- {
+		for j := len(newLines) - 1; j >= 0; j-- {
+			if oldLines[i] == newLines[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	var diff []string
+	i, j := 0, 0
+	for i < len(oldLines) || j < len(newLines) {
+		switch {
+		case i < len(oldLines) && j < len(newLines) && oldLines[i] == newLines[j]:
+			i++
+			j++
+		case j < len(newLines) && (i == len(oldLines) || common[i][j+1] > common[i+1][j]):
+			diff = append(diff, "+"+newLines[j])
+			j++
+		default:
+			diff = append(diff, "-"+oldLines[i])
+			i++
+		}
+	}
+	return diff
+}
+
+func splitLines(text string) []string {
+	text = strings.TrimSuffix(text, "\n")
+	if text == "" {
+		return nil
+	}
+	return strings.Split(text, "\n")
+}
@@ -0,0 +1,340 @@
+package terminal
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/schidstorm/s3tool/internal/s3lib"
+)
+
+// DualPanePage shows a local directory next to the objects of a bucket like
+// a two panel file manager. Entries are copied or moved from the focused
+// pane into the directory shown by the other pane.
+type DualPanePage struct {
+	*tview.Flex
+
+	context      Context
+	local        *LocalPage
+	objects      *ObjectsPage
+	localFocused bool
+}
+
+func NewDualPanePage(context Context, dir string) *DualPanePage {
+	page := &DualPanePage{
+		Flex:         tview.NewFlex().SetDirection(tview.FlexColumn),
+		context:      context,
+		localFocused: true,
+	}
+
+	// directories of the objects pane are shown in the pane, other pages
+	// open as usual
+	page.objects = NewObjectsPage(context.WithOpenPageFunc(page.openPage))
+	page.local = NewLocalPage(context, dir)
+
+	for _, pane := range []*tview.Flex{page.local.Flex, page.objects.Flex} {
+		pane.SetBorder(true)
+		pane.SetTitleAlign(tview.AlignLeft)
+		page.AddItem(pane, 0, 1, false)
+	}
+
+	return page
+}
+
+func (p *DualPanePage) openPage(page PageContent) {
+	switch page := page.(type) {
+	case *ObjectsPage:
+		if page.archive != nil {
+			// archives are read only, they open as pages of their own
+			p.context.OpenPage(page)
+			return
+		}
+		p.objects.navigate(page.Context())
+	case *DualPanePage:
+		// already shown
+	default:
+		p.context.OpenPage(page)
+	}
+}
+
+func (p *DualPanePage) focusedPane() PageContent {
+	if p.localFocused {
+		return p.local
+	}
+	return p.objects
+}
+
+func (p *DualPanePage) Focus(delegate func(p tview.Primitive)) {
+	delegate(p.focusedPane())
+}
+
+// InputHandler switches the focus between the panes on tab and passes
+// everything else to the focused pane.
+func (p *DualPanePage) InputHandler() func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+	return p.WrapInputHandler(func(event *tcell.EventKey, setFocus func(p tview.Primitive)) {
+		if event.Key() == tcell.KeyTab {
+			p.localFocused = !p.localFocused
+			setFocus(p.focusedPane())
+			return
+		}
+
+		if handler := p.focusedPane().InputHandler(); handler != nil {
+			handler(event, setFocus)
+		}
+	})
+}
+
+func (p *DualPanePage) Draw(screen tcell.Screen) {
+	localColor, objectsColor := DefaultTheme.SecondaryColor, DefaultTheme.BorderColor
+	if !p.localFocused {
+		localColor, objectsColor = objectsColor, localColor
+	}
+	p.local.SetBorderColor(localColor)
+	p.objects.SetBorderColor(objectsColor)
+	p.local.SetTitle(" " + p.local.Dir() + " ")
+	p.objects.SetTitle(" " + p.objects.context.Bucket() + "/" + p.objects.context.ObjectKey() + " ")
+
+	p.Flex.Draw(screen)
+}
+
+func (p *DualPanePage) Title() string {
+	return "File Manager"
+}
+
+func (p *DualPanePage) Context() Context {
+	return p.objects.Context()
+}
+
+func (p *DualPanePage) SetSearch(term string) {
+	p.focusedPane().SetSearch(term)
+}
+
+func (p *DualPanePage) Load() error {
+	if err := p.local.Load(); err != nil {
+		return err
+	}
+	return p.objects.Load()
+}
+
+func (p *DualPanePage) Close() {
+	p.objects.Close()
+}
+
+// dualPaneHotkeyKeys are the keys of the hotkeys of DualPanePage besides the
+// ones of its panes.
+var dualPaneHotkeyKeys = hotkeyKeys{
+	"Switch Pane":        EventKey(tcell.KeyTab, 0, 0),
+	"Parent Directory":   EventKey(tcell.KeyBackspace, 0, 0),
+	"Copy to Other Pane": EventKey(tcell.KeyRune, 'c', 0),
+	"Move to Other Pane": EventKey(tcell.KeyRune, 'm', 0),
+	"Sync to Other Pane": EventKey(tcell.KeyRune, 'S', 0),
+}
+
+func (p *DualPanePage) Hotkeys() map[tcell.EventKey]Hotkey {
+	hotkeys := dualPaneHotkeyKeys.bind(map[string]func(*tcell.EventKey) *tcell.EventKey{
+		"Switch Pane": func(event *tcell.EventKey) *tcell.EventKey {
+			// the focus is moved by InputHandler, which can set it
+			return tcell.NewEventKey(tcell.KeyTab, 0, tcell.ModNone)
+		},
+		"Parent Directory": func(event *tcell.EventKey) *tcell.EventKey {
+			p.parentDirectory()
+			return nil
+		},
+		"Copy to Other Pane": func(event *tcell.EventKey) *tcell.EventKey {
+			p.transferForm(false)
+			return nil
+		},
+		"Move to Other Pane": func(event *tcell.EventKey) *tcell.EventKey {
+			p.transferForm(true)
+			return nil
+		},
+		"Sync to Other Pane": func(event *tcell.EventKey) *tcell.EventKey {
+			p.syncForm()
+			return nil
+		},
+	})
+
+	// the remaining hotkeys of both panes act on the focused pane
+	for _, pane := range []PageContent{p.local, p.objects} {
+		for key, hotkey := range pane.Hotkeys() {
+			if _, exists := findHotkey(hotkeys, &key); exists {
+				continue
+			}
+			// the keys may be rebound, so the hotkey is found by its title
+			title := hotkey.Title
+			hotkeys[key] = Hotkey{
+				Title: title,
+				Handler: func(event *tcell.EventKey) *tcell.EventKey {
+					for _, hk := range p.focusedPane().Hotkeys() {
+						if hk.Title == title {
+							return hk.Handler(event)
+						}
+					}
+					return nil
+				},
+			}
+		}
+	}
+	return hotkeys
+}
+
+func (p *DualPanePage) parentDirectory() {
+	if p.localFocused {
+		p.local.navigate(filepath.Dir(p.local.Dir()))
+		return
+	}
+
+	prefix := strings.TrimSuffix(p.objects.context.ObjectKey(), "/")
+	if prefix == "" {
+		return
+	}
+	parent := path.Dir(prefix) + "/"
+	if parent == "./" {
+		parent = ""
+	}
+	p.objects.navigate(p.objects.context.WithObjectKey(parent))
+}
+
+// transferForm confirms and starts copying or moving the selected entries of
+// the focused pane into the other pane.
+func (p *DualPanePage) transferForm(move bool) {
+	if p.localFocused {
+		p.uploadForm(move)
+	} else {
+		p.downloadForm(move)
+	}
+}
+
+func (p *DualPanePage) uploadForm(move bool) {
+	entries := p.local.selectedEntries()
+	if len(entries) == 0 {
+		return
+	}
+
+	objectsContext := p.objects.context
+	dstPrefix := objectsContext.ObjectKey()
+	verb := "Upload"
+	if move {
+		verb = "Move"
+	}
+
+	var files []localFile
+	p.objects.runBackground("Listing files…", func(ctx context.Context) error {
+		var err error
+		files, err = expandLocalEntries(ctx, entries, dstPrefix)
+		return err
+	}, func(err error) {
+		if err != nil {
+			p.context.SetError(err)
+			return
+		}
+
+		modalMessage := fmt.Sprintf("%s %s to %s/%s?", verb, localFilesSummary(files), objectsContext.Bucket(), dstPrefix)
+		p.context.Modal(ConfirmModal(modalMessage, func() {
+			uploadFiles(objectsContext, localEntriesLabel(entries), files, func(err error) {
+				if err == nil && move {
+					if err := removeLocalEntries(entries); err != nil {
+						p.context.SetError(err)
+					}
+				}
+				p.reload()
+			})
+		}))
+	})
+}
+
+func (p *DualPanePage) downloadForm(move bool) {
+	items := p.objects.selectedItems()
+	if len(items) == 0 {
+		return
+	}
+
+	objectsContext := p.objects.context
+	dir := p.local.Dir()
+	verb := "Download"
+	if move {
+		verb = "Move"
+	}
+	p.objects.expandSelection(items, func(objects []s3lib.Object) {
+		modalMessage := fmt.Sprintf("%s %s to %s?", verb, objectsSummary(objects), dir)
+		p.context.Modal(ConfirmModal(modalMessage, func() {
+			saveObjects(objectsContext, objectsContext.ObjectKey(), objects, dir, func(err error) {
+				if err == nil && move {
+					deleteObjects(objectsContext, selectionLabel(items), objects, func(err error) {
+						p.reload()
+					})
+					return
+				}
+				p.reload()
+			})
+		}))
+	})
+}
+
+// syncForm shows the changes that make the directory of the other pane mirror
+// the directory of the focused pane and performs them once confirmed.
+func (p *DualPanePage) syncForm() {
+	objectsContext := p.objects.context
+	src := s3lib.LocalSyncLocation(p.local.Dir())
+	dst := s3lib.RemoteSyncLocation(objectsContext.S3Client(), objectsContext.Bucket(), objectsContext.ObjectKey())
+	if !p.localFocused {
+		src, dst = dst, src
+	}
+
+	var plan s3lib.SyncPlan
+	p.objects.runBackground("Comparing directories…", func(ctx context.Context) error {
+		var err error
+		plan, err = s3lib.PlanSync(ctx, src, dst, s3lib.SyncOptions{Delete: true})
+		return err
+	}, func(err error) {
+		if err != nil {
+			p.context.SetError(err)
+			return
+		}
+		p.syncModal(objectsContext, src, dst, plan)
+	})
+}
+
+// syncModal asks whether to perform plan, which syncs src to dst.
+func (p *DualPanePage) syncModal(objectsContext Context, src, dst s3lib.SyncLocation, plan s3lib.SyncPlan) {
+	buttons := []string{"Cancel", "Sync"}
+	if plan.Count(s3lib.SyncDelete) > 0 {
+		buttons = append(buttons, "Sync and Delete")
+	}
+	modalMessage := fmt.Sprintf("Sync %s to %s?\n%s", src, dst, plan.Summary())
+	p.context.Modal(func(close func()) tview.Primitive {
+		return NewModal().
+			SetText(modalMessage).
+			AddButtons(buttons).
+			SetDoneFunc(func(buttonLabel string, values map[string]string) {
+				close()
+				switch buttonLabel {
+				case "Sync":
+					plan = plan.WithoutDeletes()
+				case "Sync and Delete":
+				default:
+					return
+				}
+				runSync(objectsContext, plan, func(err error) {
+					p.reload()
+				})
+			})
+	})
+}
+
+func (p *DualPanePage) reload() {
+	p.local.reload()
+	p.objects.reload()
+}
+
+// localEntriesLabel names entries in the transfers page.
+func localEntriesLabel(entries []LocalEntry) string {
+	if len(entries) == 1 {
+		return entries[0].Name
+	}
+	return fmt.Sprintf("%d items", len(entries))
+}
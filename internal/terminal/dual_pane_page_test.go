@@ -0,0 +1,161 @@
+package terminal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/schidstorm/s3tool/internal/s3lib"
+	"github.com/stretchr/testify/assert"
+)
+
+func pressHotkey(t *testing.T, page PageContent, event *tcell.EventKey) {
+	t.Helper()
+	hotkey, ok := findHotkey(page.Hotkeys(), event)
+	assert.True(t, ok)
+	hotkey.Handler(event)
+}
+
+func TestDualPanePageCopyAndMove(t *testing.T) {
+	dir := t.TempDir()
+	writeLocalFiles(t, dir, map[string]string{
+		"a.txt":     "a",
+		"sub/b.txt": "bb",
+	})
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "dest"), 0o755))
+
+	client := s3lib.NewMemoryClientFactory().
+		WithBucket("bucket", "eu-central-1", time.Now()).
+		Build()
+	c := NewContext().
+		WithClient(client).
+		WithBucket("bucket").
+		WithErrorFunc(func(err error) { t.Error(err) }).
+		WithModalFunc(func(build ModalBuilder) {
+			build(func() {}).(*Modal).done("Confirm", nil)
+		})
+
+	page := NewDualPanePage(c, dir)
+	assert.NoError(t, page.Load())
+
+	// rows are "..", "a.txt", "dest" and "sub"
+	page.local.table.ToggleHighlight(1)
+	page.local.table.ToggleHighlight(3)
+	pressHotkey(t, page, tcell.NewEventKey(tcell.KeyRune, 'c', 0))
+	assert.Equal(t, []string{"a.txt", "sub/b.txt"}, listKeys(t, c, ""))
+	assert.FileExists(t, filepath.Join(dir, "a.txt"))
+
+	page.local.navigate(filepath.Join(dir, "dest"))
+	page.InputHandler()(tcell.NewEventKey(tcell.KeyTab, 0, 0), func(tview.Primitive) {})
+	assert.False(t, page.localFocused)
+
+	// directories are listed first
+	assert.Equal(t, "sub/", getTableRows(page.objects.tviewTable)[1][0])
+	page.objects.tviewTable.Select(1, 0)
+	pressHotkey(t, page, tcell.NewEventKey(tcell.KeyRune, 'm', 0))
+	assert.Equal(t, []string{"a.txt"}, listKeys(t, c, ""))
+	got, err := os.ReadFile(filepath.Join(dir, "dest", "sub", "b.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "bb", string(got))
+
+	page.openPage(NewObjectsPage(page.objects.context.WithObjectKey("sub/")))
+	assert.Equal(t, "sub/", page.objects.context.ObjectKey())
+	pressHotkey(t, page, tcell.NewEventKey(tcell.KeyBackspace2, 0, 0))
+	assert.Equal(t, "", page.objects.context.ObjectKey())
+}
+
+func TestDualPanePageSync(t *testing.T) {
+	dir := t.TempDir()
+	writeLocalFiles(t, dir, map[string]string{"a.txt": "a", "sub/b.txt": "bb"})
+
+	client := s3lib.NewMemoryClientFactory().
+		WithBucket("bucket", "eu-central-1", time.Now()).
+		WithObject("bucket", "stale.txt", 5, time.Now(), "etag", "STANDARD", []byte("stale")).
+		Build()
+	var message string
+	c := NewContext().
+		WithClient(client).
+		WithBucket("bucket").
+		WithErrorFunc(func(err error) { t.Error(err) }).
+		WithModalFunc(func(build ModalBuilder) {
+			modal := build(func() {}).(*Modal)
+			message = modal.text
+			modal.done("Sync and Delete", nil)
+		})
+
+	page := NewDualPanePage(c, dir)
+	assert.NoError(t, page.Load())
+
+	pressHotkey(t, page, tcell.NewEventKey(tcell.KeyRune, 'S', 0))
+	assert.Contains(t, message, "2 new, 0 changed, 1 deleted")
+	assert.Equal(t, []string{"a.txt", "sub/b.txt"}, listKeys(t, c, ""))
+}
+
+func TestDualPanePageSyncPlansInBackground(t *testing.T) {
+	dir := t.TempDir()
+	writeLocalFiles(t, dir, map[string]string{"a.txt": "a"})
+
+	client := s3lib.NewMemoryClientFactory().
+		WithBucket("bucket", "eu-central-1", time.Now()).
+		Build()
+	queued := make(chan func(), 1)
+	var message string
+	c := NewContext().
+		WithClient(client).
+		WithBucket("bucket").
+		WithErrorFunc(func(err error) { t.Error(err) }).
+		WithModalFunc(func(build ModalBuilder) {
+			modal := build(func() {}).(*Modal)
+			message = modal.text
+			modal.done("Cancel", nil)
+		})
+
+	page := NewDualPanePage(c, dir)
+	assert.NoError(t, page.Load())
+	page.objects.context = page.objects.context.WithQueueUpdateFunc(func(f func()) { queued <- f })
+
+	pressHotkey(t, page, tcell.NewEventKey(tcell.KeyRune, 'S', 0))
+	assert.Equal(t, "Comparing directories…", page.objects.status.GetText(true))
+	assert.Empty(t, message)
+
+	(<-queued)()
+	assert.Contains(t, message, "1 new, 0 changed, 0 deleted")
+	assert.Empty(t, page.objects.status.GetText(true))
+}
+
+func TestDualPanePageUploadListsFilesInBackground(t *testing.T) {
+	dir := t.TempDir()
+	writeLocalFiles(t, dir, map[string]string{"sub/a.txt": "a", "sub/b.txt": "bb"})
+
+	client := s3lib.NewMemoryClientFactory().
+		WithBucket("bucket", "eu-central-1", time.Now()).
+		Build()
+	queued := make(chan func(), 1)
+	var message string
+	c := NewContext().
+		WithClient(client).
+		WithBucket("bucket").
+		WithErrorFunc(func(err error) { t.Error(err) }).
+		WithModalFunc(func(build ModalBuilder) {
+			modal := build(func() {}).(*Modal)
+			message = modal.text
+			modal.done("Cancel", nil)
+		})
+
+	page := NewDualPanePage(c, dir)
+	assert.NoError(t, page.Load())
+	page.objects.context = page.objects.context.WithQueueUpdateFunc(func(f func()) { queued <- f })
+
+	// rows are ".." and "sub"
+	page.local.table.ToggleHighlight(1)
+	pressHotkey(t, page, tcell.NewEventKey(tcell.KeyRune, 'c', 0))
+	assert.Equal(t, "Listing files…", page.objects.status.GetText(true))
+	assert.Empty(t, message)
+
+	(<-queued)()
+	assert.Contains(t, message, "2 files (3 B)")
+	assert.Empty(t, page.objects.status.GetText(true))
+}
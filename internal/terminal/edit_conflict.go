@@ -0,0 +1,152 @@
+package terminal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/rivo/tview"
+	"github.com/schidstorm/s3tool/internal/s3lib"
+)
+
+// maxDiffCells limits the product of the line counts of the texts compared
+// for an edit conflict, as lineDiff needs memory for each pair of lines.
+const maxDiffCells = 16 << 20
+
+// saveEditedObject uploads tmpFilePath, the edited object of c, keeping
+// headers like its Content-Encoding. The upload only replaces an object that
+// still has etag, the ETag it was downloaded with; otherwise the user decides
+// how to resolve the conflict. filePath is the file that was edited, which is
+// the decompressed tmpFilePath for compressed objects.
+func saveEditedObject(c Context, etag, tmpDir, tmpFilePath, filePath string) {
+	client, bucket, key := c.S3Client(), c.Bucket(), c.ObjectKey()
+
+	c.Transfers().Submit(NewTransfer(TransferUpload, bucket, key,
+		func(ctx context.Context, progress func(transferred, total int64)) error {
+			return client.UploadFile(ctx, bucket, key, tmpFilePath, partOptions(s3lib.WithKeepAttributes(), s3lib.WithIfMatch(etag), s3lib.WithProgress(progress))...)
+		},
+		func(err error) {
+			if errors.Is(err, s3lib.ErrPreconditionFailed) {
+				editConflictModal(c, tmpDir, tmpFilePath, filePath)
+				return
+			}
+			transferDone(c, removeAfterUpload(tmpDir))(err)
+		},
+	))
+}
+
+// removeAfterUpload removes tmpDir once the upload of an edited object
+// succeeded. A failed upload keeps its file so that it can be retried.
+func removeAfterUpload(tmpDir string) func(err error) {
+	return func(err error) {
+		if err == nil {
+			_ = os.RemoveAll(tmpDir)
+		}
+	}
+}
+
+// editConflictModal asks what to do with an edited object that was changed
+// or deleted by someone else since it was downloaded.
+func editConflictModal(c Context, tmpDir, tmpFilePath, filePath string) {
+	message := fmt.Sprintf("%s in %s was changed since it was opened for editing.", c.ObjectKey(), c.Bucket())
+	choiceModal(c, message, []string{"Overwrite", "Show Diff", "Save As", "Discard"}, func(buttonLabel string) {
+		switch buttonLabel {
+		case "Overwrite":
+			uploadObject(c, c.ObjectKey(), tmpFilePath, removeAfterUpload(tmpDir), s3lib.WithKeepAttributes())
+		case "Show Diff":
+			showEditConflictDiff(c, filePath, func() {
+				editConflictModal(c, tmpDir, tmpFilePath, filePath)
+			})
+		case "Save As":
+			saveEditedObjectAs(c, tmpDir, tmpFilePath, filePath)
+		default:
+			_ = os.RemoveAll(tmpDir)
+		}
+	})
+}
+
+// saveEditedObjectAs asks for a key and uploads the edited object to it
+// instead of replacing the changed object. The upload keeps the headers of the
+// changed object, like its Content-Encoding.
+func saveEditedObjectAs(c Context, tmpDir, tmpFilePath, filePath string) {
+	c.Modal(func(close func()) tview.Primitive {
+		return NewModal().
+			SetTitle("Save As").
+			AddInput().SetLabel("Key").SetText(c.ObjectKey()).
+			AddButtons([]string{"Save", "Cancel"}).
+			SetDoneFunc(func(buttonLabel string, values map[string]string) {
+				close()
+				key := strings.TrimSpace(values["Key"])
+				if buttonLabel == "Save" && key == "" {
+					c.SetError(errors.New("key cannot be empty"))
+				}
+				if buttonLabel != "Save" || key == "" {
+					editConflictModal(c, tmpDir, tmpFilePath, filePath)
+					return
+				}
+				uploadObject(c, key, tmpFilePath, removeAfterUpload(tmpDir), s3lib.WithAttributesFrom(c.ObjectKey()))
+			})
+	})
+}
+
+// showEditConflictDiff downloads the current object of c, shows the changes
+// of the edited file filePath to it and calls done afterwards, also if the
+// object could not be compared.
+func showEditConflictDiff(c Context, filePath string, done func()) {
+	client, bucket, key := c.S3Client(), c.Bucket(), c.ObjectKey()
+
+	var tmpDir, tmpFilePath string
+	c.Transfers().Submit(NewTransfer(TransferDownload, bucket, key,
+		func(ctx context.Context, progress func(transferred, total int64)) error {
+			var err error
+			tmpDir, tmpFilePath, err = downloadFileToTmp(ctx, client, bucket, key, s3lib.WithProgress(progress))
+			return err
+		},
+		func(err error) {
+			if err == nil {
+				err = showFileDiff(c, tmpDir, tmpFilePath, filePath)
+				_ = os.RemoveAll(tmpDir)
+			}
+			if err != nil && !errors.Is(err, context.Canceled) {
+				c.SetError(err)
+			}
+			done()
+		},
+	))
+}
+
+// showFileDiff shows the lines of filePath that differ from the downloaded
+// object of c at currentFilePath in the viewer.
+func showFileDiff(c Context, tmpDir, currentFilePath, filePath string) error {
+	currentFilePath, _, err := decompressFile(tmpDir, c.ObjectKey(), currentFilePath)
+	if err != nil {
+		return err
+	}
+	current, err := os.ReadFile(currentFilePath)
+	if err != nil {
+		return err
+	}
+	edited, err := os.ReadFile(filePath)
+	if err != nil {
+		return err
+	}
+	if len(splitLines(string(current)))*len(splitLines(string(edited))) > maxDiffCells {
+		return fmt.Errorf("%s is too large to compare", c.ObjectKey())
+	}
+
+	diff := lineDiff(string(current), string(edited))
+	text := fmt.Sprintf("--- %s/%s\n+++ edited\n", c.Bucket(), c.ObjectKey())
+	for _, line := range diff {
+		text += line + "\n"
+	}
+
+	diffPath := filepath.Join(tmpDir, path.Base(c.ObjectKey())+".diff")
+	if err := os.WriteFile(diffPath, []byte(text), 0o600); err != nil {
+		return err
+	}
+	return ShowFile(c, diffPath)
+}
@@ -0,0 +1,128 @@
+package terminal
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/schidstorm/s3tool/internal/s3lib"
+	"github.com/stretchr/testify/assert"
+)
+
+// editConflictContext returns a context whose editor appends to the object
+// while someone else replaces it with "theirs", keeping its attributes. The modals it opens are
+// answered with buttons in order.
+func editConflictContext(t *testing.T, client *s3lib.MemoryClient, buttons []string, values map[string]string) Context {
+	useEditor(t, `sh -c 'echo mine >> "$1"' sh`)
+
+	theirsPath := filepath.Join(t.TempDir(), "theirs")
+	assert.NoError(t, os.WriteFile(theirsPath, []byte("payload\ntheirs\n"), 0o600))
+
+	return testContextWithClient(client).
+		WithErrorFunc(func(err error) { t.Error(err) }).
+		WithSuspendAppFunc(func(f func()) bool {
+			f()
+			if client != nil {
+				assert.NoError(t, client.UploadFile(context.Background(), "bucket", "object.txt", theirsPath, s3lib.WithKeepAttributes()))
+				client = nil
+			}
+			return true
+		}).
+		WithModalFunc(func(build ModalBuilder) {
+			if !assert.NotEmpty(t, buttons, "unexpected modal") {
+				return
+			}
+			button := buttons[0]
+			buttons = buttons[1:]
+			build(func() {}).(*Modal).done(button, values)
+		})
+}
+
+func editConflictClient() *s3lib.MemoryClient {
+	return s3lib.NewMemoryClientFactory().
+		WithBucket("bucket", "eu-central-1", time.Now()).
+		WithObject("bucket", "object.txt", 8, time.Now(), "etag", "STANDARD", []byte("payload\n")).
+		Build()
+}
+
+func readTestObject(t *testing.T, client s3lib.Client, key string) string {
+	t.Helper()
+	data, _, err := client.ReadObjectRange(context.Background(), "bucket", key, "", 0, 1024)
+	assert.NoError(t, err)
+	return string(data)
+}
+
+func TestEditObjectWithoutConflictUploads(t *testing.T) {
+	useEditor(t, `sh -c 'echo mine >> "$1"' sh`)
+	client := editConflictClient()
+
+	editObject(testContextWithClient(client).WithErrorFunc(func(err error) { t.Error(err) }))
+	assert.Equal(t, "payload\nmine\n", readTestObject(t, client, "object.txt"))
+}
+
+func TestEditObjectConflictOverwrite(t *testing.T) {
+	client := editConflictClient()
+
+	editObject(editConflictContext(t, client, []string{"Overwrite"}, nil))
+	assert.Equal(t, "payload\nmine\n", readTestObject(t, client, "object.txt"))
+}
+
+func TestEditObjectConflictDiscard(t *testing.T) {
+	client := editConflictClient()
+
+	editObject(editConflictContext(t, client, []string{"Discard"}, nil))
+	assert.Equal(t, "payload\ntheirs\n", readTestObject(t, client, "object.txt"))
+}
+
+func TestEditObjectConflictSaveAs(t *testing.T) {
+	client := s3lib.NewMemoryClientFactory().
+		WithBucket("bucket", "eu-central-1", time.Now()).
+		WithObject("bucket", "object.txt", 8, time.Now(), "etag", "STANDARD", []byte("payload\n")).
+		WithObjectMetadata("bucket", "object.txt", map[string]string{"owner": "ops"}, nil).
+		Build()
+
+	editObject(editConflictContext(t, client, []string{"Save As", "Save"}, map[string]string{"Key": "object-mine.txt"}))
+	assert.Equal(t, "payload\ntheirs\n", readTestObject(t, client, "object.txt"))
+	assert.Equal(t, "payload\nmine\n", readTestObject(t, client, "object-mine.txt"))
+
+	metadata, err := client.GetObject(context.Background(), "bucket", "object-mine.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"owner": "ops"}, metadata.Metadata)
+}
+
+func TestEditObjectConflictShowDiff(t *testing.T) {
+	diffPath := filepath.Join(t.TempDir(), "shown.diff")
+	useViewer(t, "cp {} "+diffPath)
+	client := editConflictClient()
+
+	editObject(editConflictContext(t, client, []string{"Show Diff", "Discard"}, nil))
+
+	diff, err := os.ReadFile(diffPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "--- bucket/object.txt\n+++ edited\n-theirs\n+mine\n", string(diff))
+	assert.Equal(t, "payload\ntheirs\n", readTestObject(t, client, "object.txt"))
+}
+
+func TestEditObjectConflictDeletedObject(t *testing.T) {
+	useEditor(t, `sh -c 'echo mine >> "$1"' sh`)
+	client := editConflictClient()
+
+	var buttons []string
+	ctx := testContextWithClient(client).
+		WithErrorFunc(func(err error) { t.Error(err) }).
+		WithSuspendAppFunc(func(f func()) bool {
+			f()
+			assert.NoError(t, client.DeleteObject(context.Background(), "bucket", "object.txt"))
+			return true
+		}).
+		WithModalFunc(func(build ModalBuilder) {
+			buttons = append(buttons, "Overwrite")
+			build(func() {}).(*Modal).done("Overwrite", nil)
+		})
+
+	editObject(ctx)
+	assert.Equal(t, []string{"Overwrite"}, buttons)
+	assert.Equal(t, "payload\nmine\n", readTestObject(t, client, "object.txt"))
+}
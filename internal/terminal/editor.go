@@ -1,15 +1,31 @@
 package terminal
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
 	"os"
 	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/schidstorm/s3tool/internal/cli"
 )
 
-var editCommand = []string{"editor"}
+// editCommand and viewCommand are used if neither the config nor the
+// environment name an editor or viewer.
+var editCommand = []string{"vi"}
 var viewCommand = []string{"less"}
 
-func EditFile(c Context, filePath string) error {
+// filePlaceholder is replaced by the path of the file in commands. Commands
+// without it get the path as their last argument.
+const filePlaceholder = "{}"
 
+func EditFile(c Context, filePath string) error {
 	return openFile(c, filePath, false)
 }
 
@@ -18,24 +34,186 @@ func ShowFile(c Context, filePath string) error {
 }
 
 func openFile(c Context, filePath string, readonly bool) error {
-	args := []string{filePath}
-
-	var command []string
-	if readonly {
-		command = append(command, viewCommand...)
-	} else {
-		command = append(command, editCommand...)
+	command, err := fileCommand(filePath, readonly)
+	if err != nil {
+		return err
 	}
-	command = append(command, args...)
 
 	cmd := exec.Command(command[0], command[1:]...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	cmd.Stdin = os.Stdin
 
-	var err error
 	c.SuspendApp(func() {
 		err = cmd.Run()
 	})
 	return err
 }
+
+// fileCommand returns the command that edits or shows filePath. It is the
+// first of the override of the config for the extension or content type of
+// the file, the editor or viewer of the config, $VISUAL and $EDITOR or
+// $PAGER, and editCommand or viewCommand.
+func fileCommand(filePath string, readonly bool) ([]string, error) {
+	overrides, configured, envs, fallback := cli.Config.Editors, cli.Config.Editor, []string{"VISUAL", "EDITOR"}, editCommand
+	if readonly {
+		overrides, configured, envs, fallback = cli.Config.Viewers, cli.Config.Viewer, []string{"PAGER"}, viewCommand
+	}
+
+	var line string
+	if override, ok := matchOverride(overrides, filePath); ok {
+		line = override
+	} else if configured != "" {
+		line = configured
+	} else {
+		for _, env := range envs {
+			if value := os.Getenv(env); value != "" {
+				line = value
+				break
+			}
+		}
+	}
+
+	command := slices.Clone(fallback)
+	if line != "" {
+		words, err := splitShellWords(line)
+		if err != nil {
+			return nil, fmt.Errorf("command %q: %w", line, err)
+		}
+		command = words
+	}
+	if len(command) == 0 {
+		return nil, errors.New("no command to open " + filepath.Base(filePath))
+	}
+
+	replaced := false
+	for i, word := range command {
+		if strings.Contains(word, filePlaceholder) {
+			command[i] = strings.ReplaceAll(word, filePlaceholder, filePath)
+			replaced = true
+		}
+	}
+	if !replaced {
+		command = append(command, filePath)
+	}
+	return command, nil
+}
+
+// matchOverride returns the command of overrides for filePath. Overrides are
+// keyed by extensions like ".parquet" or content types like "image/*";
+// extensions take precedence, and longer patterns over shorter ones.
+func matchOverride(overrides map[string]string, filePath string) (string, bool) {
+	if len(overrides) == 0 {
+		return "", false
+	}
+
+	patterns := make([]string, 0, len(overrides))
+	for pattern := range overrides {
+		patterns = append(patterns, pattern)
+	}
+	slices.SortFunc(patterns, func(a, b string) int {
+		return len(b) - len(a)
+	})
+
+	name := strings.ToLower(filepath.Base(filePath))
+	for _, pattern := range patterns {
+		if strings.HasPrefix(pattern, ".") && strings.HasSuffix(name, strings.ToLower(pattern)) {
+			return overrides[pattern], true
+		}
+	}
+
+	contentType := detectContentType(filePath)
+	for _, pattern := range patterns {
+		if !strings.Contains(pattern, "/") {
+			continue
+		}
+		if ok, _ := path.Match(strings.ToLower(pattern), contentType); ok {
+			return overrides[pattern], true
+		}
+	}
+	return "", false
+}
+
+// detectContentType returns the media type of a file by its extension, or
+// by its first bytes if the extension is unknown.
+func detectContentType(filePath string) string {
+	contentType := mime.TypeByExtension(filepath.Ext(filePath))
+	if contentType == "" {
+		f, err := os.Open(filePath)
+		if err != nil {
+			return ""
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+
+		head := make([]byte, 512)
+		n, _ := io.ReadFull(f, head)
+		contentType = http.DetectContentType(head[:n])
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	return mediaType
+}
+
+// splitShellWords splits a command line into words like a POSIX shell, with
+// single and double quotes and backslash escapes, but without expansions.
+func splitShellWords(line string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	runes := []rune(line)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		case r == '\\':
+			inWord = true
+			if i+1 < len(runes) {
+				i++
+				word.WriteRune(runes[i])
+			}
+		case r == '\'':
+			inWord = true
+			end := slices.Index(runes[i+1:], '\'')
+			if end < 0 {
+				return nil, errors.New("unterminated single quote")
+			}
+			word.WriteString(string(runes[i+1 : i+1+end]))
+			i += end + 1
+		case r == '"':
+			inWord = true
+			closed := false
+			for i++; i < len(runes); i++ {
+				if runes[i] == '"' {
+					closed = true
+					break
+				}
+				// backslashes only escape the characters that are special
+				// in double quotes
+				if runes[i] == '\\' && i+1 < len(runes) && strings.ContainsRune("\"\\$`", runes[i+1]) {
+					i++
+				}
+				word.WriteRune(runes[i])
+			}
+			if !closed {
+				return nil, errors.New("unterminated double quote")
+			}
+		default:
+			inWord = true
+			word.WriteRune(r)
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
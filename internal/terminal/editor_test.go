@@ -0,0 +1,99 @@
+package terminal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/schidstorm/s3tool/internal/cli"
+	"github.com/stretchr/testify/assert"
+)
+
+// useEditor makes files open with command, regardless of the environment.
+func useEditor(t *testing.T, command string) {
+	t.Helper()
+	previous := cli.Config.Editor
+	cli.Config.Editor = command
+	t.Cleanup(func() { cli.Config.Editor = previous })
+}
+
+// useViewer makes files show with command, regardless of the environment.
+func useViewer(t *testing.T, command string) {
+	t.Helper()
+	previous := cli.Config.Viewer
+	cli.Config.Viewer = command
+	t.Cleanup(func() { cli.Config.Viewer = previous })
+}
+
+func TestSplitShellWords(t *testing.T) {
+	tests := map[string][]string{
+		"vim":                        {"vim"},
+		"  code   --wait ":           {"code", "--wait"},
+		`sh -c 'echo "$1"' sh`:       {"sh", "-c", `echo "$1"`, "sh"},
+		`less "-R" "a \"b\" \x"`:     {"less", "-R", `a "b" \x`},
+		`open /Applications/My\ App`: {"open", "/Applications/My App"},
+		`a''b "" c`:                  {"ab", "", "c"},
+	}
+	for line, expected := range tests {
+		words, err := splitShellWords(line)
+		assert.NoError(t, err, line)
+		assert.Equal(t, expected, words, line)
+	}
+
+	for _, line := range []string{`vim 'a`, `vim "a`} {
+		_, err := splitShellWords(line)
+		assert.Error(t, err, line)
+	}
+}
+
+func TestFileCommand(t *testing.T) {
+	dir := t.TempDir()
+	file := func(name string, content string) string {
+		filePath := filepath.Join(dir, name)
+		assert.NoError(t, os.WriteFile(filePath, []byte(content), 0o644))
+		return filePath
+	}
+	text := file("notes.txt", "hello")
+	data := file("data.PARQUET", "PAR1")
+	image := file("picture", "\x89PNG\r\n\x1a\n")
+
+	previous := *cli.Config
+	t.Cleanup(func() { *cli.Config = previous })
+	cli.Config.Editor, cli.Config.Viewer = "", ""
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "nano -w")
+	t.Setenv("PAGER", "")
+
+	command := func(filePath string, readonly bool) []string {
+		command, err := fileCommand(filePath, readonly)
+		assert.NoError(t, err)
+		return command
+	}
+
+	assert.Equal(t, []string{"nano", "-w", text}, command(text, false))
+	assert.Equal(t, []string{"less", text}, command(text, true))
+
+	t.Setenv("VISUAL", "code --wait")
+	t.Setenv("PAGER", "more")
+	assert.Equal(t, []string{"code", "--wait", text}, command(text, false))
+	assert.Equal(t, []string{"more", text}, command(text, true))
+
+	cli.Config.Editor = `vim -c "set ft=text"`
+	cli.Config.Viewer = "bat --paging=always {} --plain"
+	assert.Equal(t, []string{"vim", "-c", "set ft=text", text}, command(text, false))
+	assert.Equal(t, []string{"bat", "--paging=always", text, "--plain"}, command(text, true))
+
+	cli.Config.Viewers = map[string]string{
+		".parquet":  "parquet-tools show",
+		"image/*":   "feh",
+		"image/png": "feh --zoom fill",
+	}
+	assert.Equal(t, []string{"parquet-tools", "show", data}, command(data, true))
+	assert.Equal(t, []string{"feh", "--zoom", "fill", image}, command(image, true))
+	assert.Equal(t, []string{"bat", "--paging=always", text, "--plain"}, command(text, true))
+	assert.Equal(t, []string{"vim", "-c", "set ft=text", image}, command(image, false))
+
+	cli.Config.Editor = "vim 'unterminated"
+	_, err := fileCommand(text, false)
+	assert.ErrorContains(t, err, "unterminated single quote")
+}
@@ -1,6 +1,7 @@
 package terminal
 
 import (
+	"slices"
 	"strings"
 
 	"github.com/gdamore/tcell/v2"
@@ -25,27 +26,51 @@ func NewHotkeyInfoBox() *HotkeyInfoBox {
 	return info
 }
 
-func (info *HotkeyInfoBox) Update(pageContent PageContent) {
+// hotkeyInfoRows is the number of hotkeys shown per column, matching the
+// height of the header.
+const hotkeyInfoRows = 5
+
+func (info *HotkeyInfoBox) Update(pageContent PageContent, global map[tcell.EventKey]Hotkey) {
 	info.Clear()
 
 	if pageContent == nil {
 		return
 	}
 
-	hotkeys := pageContent.Hotkeys()
-	row := 0
-	for key, hk := range hotkeys {
-		keyCell := tview.NewTableCell(eventKeyToString(key))
+	type entry struct {
+		action string
+		key    string
+		title  string
+	}
+	var entries []entry
+	for _, hotkeys := range []map[tcell.EventKey]Hotkey{pageContent.Hotkeys(), global} {
+		// the keys of an action are shown together
+		var group []entry
+		for _, binding := range ActiveKeymap.Bindings(hotkeys) {
+			if n := len(group); n > 0 && group[n-1].action == binding.Action {
+				group[n-1].key += " " + binding.Keys.String()
+				continue
+			}
+			group = append(group, entry{action: binding.Action, key: binding.Keys.String(), title: binding.Hotkey.Title})
+		}
+		slices.SortFunc(group, func(a, b entry) int {
+			return strings.Compare(a.key, b.key)
+		})
+		entries = append(entries, group...)
+	}
+
+	for i, e := range entries {
+		keyCell := tview.NewTableCell(e.key)
 		keyCell.SetStyle(DefaultStyle.Foreground(DefaultTheme.KeyColor))
 		keyCell.SetExpansion(1)
 
-		titleCell := tview.NewTableCell(hk.Title)
+		titleCell := tview.NewTableCell(e.title)
 		titleCell.SetStyle(DefaultStyle.Foreground(DefaultTheme.SecondaryColor))
 		titleCell.SetExpansion(5)
 
-		info.SetCell(row, 0, keyCell)
-		info.SetCell(row, 1, titleCell)
-		row++
+		row, column := i%hotkeyInfoRows, 2*(i/hotkeyInfoRows)
+		info.SetCell(row, column, keyCell)
+		info.SetCell(row, column+1, titleCell)
 	}
 }
 
@@ -53,6 +78,8 @@ func eventKeyToString(key tcell.EventKey) string {
 	var keyNameParts []string
 	if key.Key() == tcell.KeyRune {
 		keyNameParts = []string{string(key.Rune())}
+	} else if key.Key() >= tcell.KeyCtrlA && key.Key() <= tcell.KeyCtrlZ {
+		keyNameParts = []string{string(rune('a' + key.Key() - tcell.KeyCtrlA))}
 	} else {
 		keyNameParts = []string{strings.ToLower(key.Name())}
 	}
@@ -0,0 +1,465 @@
+package terminal
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/gdamore/tcell/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// ActiveKeymap binds the keys of all pages. It is replaced by the keymap of
+// the user at startup, before the first page is opened.
+var ActiveKeymap = DefaultKeymap()
+
+// Keymap binds actions to key sequences. Actions are named after the titles
+// of their hotkeys in kebab case, like "delete-object" for "Delete Object".
+// Actions that the keymap does not bind keep the keys of their pages.
+type Keymap struct {
+	bindings map[string][]KeySequence
+}
+
+// KeySequence is a series of keys pressed one after another, like "g g".
+type KeySequence []tcell.EventKey
+
+// Binding is a key sequence that triggers the hotkey of an action.
+type Binding struct {
+	Action string
+	Keys   KeySequence
+	Hotkey Hotkey
+}
+
+// keymapFile is the YAML keymap of the user.
+type keymapFile struct {
+	// Preset is the keymap that the bindings are applied on, "default" or
+	// "vim".
+	Preset   string                 `yaml:"preset"`
+	Bindings map[string]keyBindings `yaml:"bindings"`
+}
+
+// keyBindings are the key sequences of an action, written as a single
+// sequence or a list of them. An empty list unbinds the action.
+type keyBindings []string
+
+func (b *keyBindings) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*b = keyBindings{node.Value}
+		return nil
+	}
+	var list []string
+	if err := node.Decode(&list); err != nil {
+		return err
+	}
+	*b = list
+	return nil
+}
+
+// keymapPresets are the keymaps that a keymap file can start from.
+var keymapPresets = map[string]map[string][]string{
+	"default": {},
+	"vim": {
+		"down":          {"j"},
+		"up":            {"k"},
+		"top":           {"g g"},
+		"bottom":        {"G"},
+		"page-down":     {"ctrl+d"},
+		"page-up":       {"ctrl+u"},
+		"delete-object": {"d d"},
+		"delete-bucket": {"d d"},
+		"mark-objects":  {"y y"},
+	},
+}
+
+// navigationKeys are the keys that the navigation actions pass on to the
+// focused list. They are not bound by default, since the lists handle these
+// keys themselves.
+var navigationKeys = map[string]tcell.EventKey{
+	"up":               EventKey(tcell.KeyUp, 0, tcell.ModNone),
+	"down":             EventKey(tcell.KeyDown, 0, tcell.ModNone),
+	"top":              EventKey(tcell.KeyHome, 0, tcell.ModNone),
+	"bottom":           EventKey(tcell.KeyEnd, 0, tcell.ModNone),
+	"page-up":          EventKey(tcell.KeyPgUp, 0, tcell.ModNone),
+	"page-down":        EventKey(tcell.KeyPgDn, 0, tcell.ModNone),
+	"open":             EventKey(tcell.KeyEnter, 0, tcell.ModNone),
+	"toggle-selection": EventKey(tcell.KeyRune, ' ', tcell.ModNone),
+}
+
+// keyNames are the names of the special keys in keymaps, like "enter".
+var keyNames = func() map[string]tcell.Key {
+	names := map[string]tcell.Key{"escape": tcell.KeyEscape}
+	for key, name := range tcell.KeyNames {
+		if strings.HasPrefix(name, "Ctrl-") {
+			continue
+		}
+		names[strings.ToLower(name)] = key
+	}
+	return names
+}()
+
+func DefaultKeymap() *Keymap {
+	return &Keymap{bindings: map[string][]KeySequence{}}
+}
+
+// LoadKeymap reads the keymap file at path. A missing file results in the
+// default keymap.
+func LoadKeymap(path string) (*Keymap, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return DefaultKeymap(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var file keymapFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("reading keymap %s: %w", path, err)
+	}
+	bindings := make(map[string][]string, len(file.Bindings))
+	for action, keys := range file.Bindings {
+		bindings[action] = keys
+	}
+	keymap, err := NewKeymap(file.Preset, bindings)
+	if err != nil {
+		return nil, fmt.Errorf("keymap %s: %w", path, err)
+	}
+	return keymap, nil
+}
+
+// NewKeymap applies bindings on the preset, an empty preset is the default
+// one. Unknown actions and keys bound to more than one action of a page are
+// reported as errors.
+func NewKeymap(preset string, bindings map[string][]string) (*Keymap, error) {
+	if preset == "" {
+		preset = "default"
+	}
+	presetBindings, ok := keymapPresets[preset]
+	if !ok {
+		return nil, fmt.Errorf("unknown preset %q", preset)
+	}
+
+	keymap := DefaultKeymap()
+	known := knownActions()
+	var errs []error
+	for _, source := range []map[string][]string{presetBindings, bindings} {
+		for action, keys := range source {
+			if !known[action] {
+				errs = append(errs, fmt.Errorf("unknown action %q", action))
+				continue
+			}
+			sequences := []KeySequence{}
+			for _, keys := range keys {
+				sequence, err := ParseKeySequence(keys)
+				if err != nil {
+					errs = append(errs, fmt.Errorf("%s: %w", action, err))
+					continue
+				}
+				sequences = append(sequences, sequence)
+			}
+			keymap.bindings[action] = sequences
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	if err := keymap.Check(); err != nil {
+		return nil, err
+	}
+	return keymap, nil
+}
+
+// ParseKeySequence parses keys separated by spaces, like "g g" or "ctrl+t".
+// Keys are single characters, names like "enter", "esc" or "space" and can
+// have the modifiers "ctrl+", "alt+" and "shift+".
+func ParseKeySequence(s string) (KeySequence, error) {
+	var sequence KeySequence
+	for _, field := range strings.Fields(s) {
+		key, err := parseKey(field)
+		if err != nil {
+			return nil, err
+		}
+		sequence = append(sequence, key)
+	}
+	if len(sequence) == 0 {
+		return nil, errors.New("empty key sequence")
+	}
+	return sequence, nil
+}
+
+func parseKey(s string) (tcell.EventKey, error) {
+	mod := tcell.ModNone
+	name := s
+	for {
+		// a trailing "+" is the plus key itself, like in "ctrl++"
+		modifier, rest, ok := strings.Cut(name, "+")
+		if !ok || rest == "" {
+			break
+		}
+		switch strings.ToLower(modifier) {
+		case "ctrl":
+			mod |= tcell.ModCtrl
+		case "alt":
+			mod |= tcell.ModAlt
+		case "shift":
+			mod |= tcell.ModShift
+		default:
+			return tcell.EventKey{}, fmt.Errorf("unknown modifier %q in %q", modifier, s)
+		}
+		name = rest
+	}
+
+	if runes := []rune(name); len(runes) == 1 {
+		return *tcell.NewEventKey(tcell.KeyRune, runes[0], mod), nil
+	}
+	if strings.EqualFold(name, "space") {
+		return *tcell.NewEventKey(tcell.KeyRune, ' ', mod), nil
+	}
+	if key, ok := keyNames[strings.ToLower(name)]; ok {
+		return *tcell.NewEventKey(key, 0, mod), nil
+	}
+	return tcell.EventKey{}, fmt.Errorf("unknown key %q", s)
+}
+
+// ActionName returns the name of the action of a hotkey title, like
+// "delete-object" for "Delete Object".
+func ActionName(title string) string {
+	var name strings.Builder
+	for _, word := range strings.Fields(title) {
+		word = strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				return unicode.ToLower(r)
+			}
+			return -1
+		}, word)
+		if word == "" {
+			continue
+		}
+		if name.Len() > 0 {
+			name.WriteByte('-')
+		}
+		name.WriteString(word)
+	}
+	return name.String()
+}
+
+// Bindings returns the bindings of hotkeys, which are keyed by their default
+// keys, ordered by action.
+func (k *Keymap) Bindings(hotkeys map[tcell.EventKey]Hotkey) []Binding {
+	var bindings []Binding
+	for key, hotkey := range hotkeys {
+		action := ActionName(hotkey.Title)
+		sequences, ok := k.bindings[action]
+		if !ok {
+			sequences = []KeySequence{{key}}
+		}
+		for _, sequence := range sequences {
+			bindings = append(bindings, Binding{Action: action, Keys: sequence, Hotkey: hotkey})
+		}
+	}
+	sortBindings(bindings)
+	return bindings
+}
+
+// navigationBindings returns the bindings of the navigation actions, which
+// pass on the keys that the lists handle.
+func (k *Keymap) navigationBindings() []Binding {
+	var bindings []Binding
+	for action, key := range navigationKeys {
+		for _, sequence := range k.bindings[action] {
+			bindings = append(bindings, Binding{
+				Action: action,
+				Keys:   sequence,
+				Hotkey: Hotkey{
+					Title: action,
+					Handler: func(event *tcell.EventKey) *tcell.EventKey {
+						return tcell.NewEventKey(key.Key(), key.Rune(), key.Modifiers())
+					},
+				},
+			})
+		}
+	}
+	sortBindings(bindings)
+	return bindings
+}
+
+// pageBindings returns the bindings of page, which shows content with
+// hotkeys.
+func (k *Keymap) pageBindings(page *Page, hotkeys map[tcell.EventKey]Hotkey) []Binding {
+	return slices.Concat(k.Bindings(page.hotkeys()), k.Bindings(hotkeys), k.navigationBindings())
+}
+
+func sortBindings(bindings []Binding) {
+	slices.SortFunc(bindings, func(a, b Binding) int {
+		return cmp.Or(strings.Compare(a.Action, b.Action), strings.Compare(a.Keys.String(), b.Keys.String()))
+	})
+}
+
+// keymapScopes returns the hotkeys of every kind of page, which are checked
+// for conflicts together with the global hotkeys.
+func keymapScopes() map[string]map[tcell.EventKey]Hotkey {
+	// the file manager has the hotkeys of both panes unless it binds their
+	// keys itself, like DualPanePage.Hotkeys
+	fileManager := dualPaneHotkeyKeys.hotkeys()
+	for _, keys := range []hotkeyKeys{localHotkeyKeys, objectsHotkeyKeys} {
+		for key, hotkey := range keys.hotkeys() {
+			if _, exists := findHotkey(fileManager, &key); !exists {
+				fileManager[key] = hotkey
+			}
+		}
+	}
+
+	return map[string]map[tcell.EventKey]Hotkey{
+		"profiles":     {},
+		"buckets":      bucketsHotkeyKeys.hotkeys(),
+		"bucket":       bucketHotkeyKeys.hotkeys(),
+		"objects":      objectsHotkeyKeys.hotkeys(),
+		"archive":      archiveHotkeyKeys.hotkeys(),
+		"object":       objectHotkeyKeys.hotkeys(),
+		"transfers":    transfersHotkeyKeys.hotkeys(),
+		"local":        localHotkeyKeys.hotkeys(),
+		"file manager": fileManager,
+	}
+}
+
+// globalHotkeys are the hotkeys that App registers on every page.
+func globalHotkeys() map[tcell.EventKey]Hotkey {
+	return map[tcell.EventKey]Hotkey{
+		transfersKey: {Title: "Transfers"},
+	}
+}
+
+func knownActions() map[string]bool {
+	known := map[string]bool{}
+	for action := range navigationKeys {
+		known[action] = true
+	}
+	scopes := keymapScopes()
+	scopes["page"] = pageHotkeyKeys.hotkeys()
+	scopes["global"] = globalHotkeys()
+	for _, hotkeys := range scopes {
+		for _, hotkey := range hotkeys {
+			known[ActionName(hotkey.Title)] = true
+		}
+	}
+	return known
+}
+
+// Check reports keys that are bound to more than one action of a page, and
+// keys that are the start of a sequence bound to another action, which could
+// never be completed.
+func (k *Keymap) Check() error {
+	conflicts := map[string]bool{}
+	global := k.Bindings(globalHotkeys())
+	for scope, hotkeys := range keymapScopes() {
+		bindings := append(k.pageBindings(&Page{}, hotkeys), global...)
+		for i := range bindings {
+			for j := i + 1; j < len(bindings); j++ {
+				a, b := bindings[i], bindings[j]
+				if a.Action == b.Action {
+					continue
+				}
+				if len(b.Keys) < len(a.Keys) {
+					a, b = b, a
+				}
+				if !b.Keys.hasPrefix(a.Keys) {
+					continue
+				}
+				if len(a.Keys) == len(b.Keys) {
+					conflicts[fmt.Sprintf("%s: %s is bound to %s and %s", scope, a.Keys, a.Action, b.Action)] = true
+				} else {
+					conflicts[fmt.Sprintf("%s: %s of %s is the start of %s of %s", scope, a.Keys, a.Action, b.Keys, b.Action)] = true
+				}
+			}
+		}
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	messages := make([]string, 0, len(conflicts))
+	for message := range conflicts {
+		messages = append(messages, message)
+	}
+	slices.Sort(messages)
+	return errors.New("conflicting keys:\n  " + strings.Join(messages, "\n  "))
+}
+
+func (s KeySequence) String() string {
+	var text strings.Builder
+	for _, key := range s {
+		text.WriteString(eventKeyToString(key))
+	}
+	return text.String()
+}
+
+// hasPrefix reports whether the sequence starts with prefix.
+func (s KeySequence) hasPrefix(prefix KeySequence) bool {
+	if len(prefix) > len(s) {
+		return false
+	}
+	for i := range prefix {
+		if !keysEqual(s[i], prefix[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// keysEqual compares keys like findHotkey, but ignores the characters that
+// terminals send with special keys.
+func keysEqual(a, b tcell.EventKey) bool {
+	if a.Key() != b.Key() || a.Modifiers() != b.Modifiers() {
+		return false
+	}
+	return a.Key() != tcell.KeyRune || a.Rune() == b.Rune()
+}
+
+// keyDispatcher matches key events against bindings and collects the keys
+// of sequences until they are complete.
+type keyDispatcher struct {
+	bindings []Binding
+	pending  KeySequence
+}
+
+// dispatch returns the hotkey that event completes. waiting reports whether
+// event is part of an incomplete sequence and must not be handled otherwise.
+func (d *keyDispatcher) dispatch(event *tcell.EventKey) (hotkey Hotkey, ok, waiting bool) {
+	sequence := append(slices.Clone(d.pending), *event)
+	for {
+		var match *Binding
+		started := false
+		for i := range d.bindings {
+			binding := &d.bindings[i]
+			if !binding.Keys.hasPrefix(sequence) {
+				continue
+			}
+			if len(binding.Keys) == len(sequence) {
+				match = binding
+			} else {
+				started = true
+			}
+		}
+
+		switch {
+		case started:
+			d.pending = sequence
+			return Hotkey{}, false, true
+		case match != nil:
+			d.pending = nil
+			return match.Hotkey, true, false
+		case len(sequence) > 1:
+			// the incomplete sequence is dropped, event may start another one
+			sequence = KeySequence{*event}
+		default:
+			d.pending = nil
+			return Hotkey{}, false, false
+		}
+	}
+}
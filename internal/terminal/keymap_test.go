@@ -0,0 +1,231 @@
+package terminal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/schidstorm/s3tool/internal/s3lib"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseKeySequence(t *testing.T) {
+	tests := []struct {
+		keys     string
+		expected KeySequence
+	}{
+		{"g g", KeySequence{EventKey(tcell.KeyRune, 'g', 0), EventKey(tcell.KeyRune, 'g', 0)}},
+		{"G", KeySequence{EventKey(tcell.KeyRune, 'G', 0)}},
+		{"ctrl+t", KeySequence{transfersKey}},
+		{"enter", KeySequence{EventKey(tcell.KeyEnter, 0, 0)}},
+		{"Esc", KeySequence{EventKey(tcell.KeyEscape, 0, 0)}},
+		{"space", KeySequence{EventKey(tcell.KeyRune, ' ', 0)}},
+		{"alt++", KeySequence{EventKey(tcell.KeyRune, '+', tcell.ModAlt)}},
+	}
+	for _, test := range tests {
+		sequence, err := ParseKeySequence(test.keys)
+		assert.NoError(t, err, test.keys)
+		assert.Equal(t, test.expected.String(), sequence.String(), test.keys)
+		assert.True(t, sequence.hasPrefix(test.expected), test.keys)
+	}
+
+	for _, keys := range []string{"", "super+a", "nokey"} {
+		_, err := ParseKeySequence(keys)
+		assert.Error(t, err, keys)
+	}
+}
+
+func TestActionName(t *testing.T) {
+	assert.Equal(t, "delete-object", ActionName("Delete Object"))
+	assert.Equal(t, "copy-to-other-pane", ActionName("Copy to Other Pane"))
+	assert.Equal(t, "presign-url", ActionName("Presign URL"))
+}
+
+func TestNewKeymapPresets(t *testing.T) {
+	for preset := range keymapPresets {
+		_, err := NewKeymap(preset, nil)
+		assert.NoError(t, err, preset)
+	}
+
+	_, err := NewKeymap("emacs", nil)
+	assert.ErrorContains(t, err, `unknown preset "emacs"`)
+}
+
+func TestNewKeymapErrors(t *testing.T) {
+	_, err := NewKeymap("", map[string][]string{"launch-rocket": {"r"}})
+	assert.ErrorContains(t, err, `unknown action "launch-rocket"`)
+
+	_, err = NewKeymap("", map[string][]string{"new-object": {"v"}})
+	assert.ErrorContains(t, err, "objects: <v> is bound to new-object and view-object")
+
+	_, err = NewKeymap("vim", map[string][]string{"new-object": {"d"}})
+	assert.ErrorContains(t, err, "objects: <d> of new-object is the start of <d><d> of delete-object")
+
+	_, err = NewKeymap("", map[string][]string{"search": {"ctrl+t"}})
+	assert.ErrorContains(t, err, "<ctrl+t> is bound to search and transfers")
+}
+
+func TestLoadKeymap(t *testing.T) {
+	dir := t.TempDir()
+
+	keymap, err := LoadKeymap(filepath.Join(dir, "missing.yaml"))
+	assert.NoError(t, err)
+	assert.Empty(t, keymap.bindings)
+
+	path := filepath.Join(dir, "keymap.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(`preset: vim
+bindings:
+  new-object: N
+  view-object: [v, enter]
+  mark-objects: []
+`), 0o644))
+	keymap, err = LoadKeymap(path)
+	assert.NoError(t, err)
+
+	var keys []string
+	for _, binding := range keymap.Bindings(NewObjectsPage(NewContext()).Hotkeys()) {
+		switch binding.Action {
+		case "new-object", "view-object", "delete-object", "mark-objects":
+			keys = append(keys, binding.Action+" "+binding.Keys.String())
+		}
+	}
+	assert.Equal(t, []string{
+		"delete-object <d><d>",
+		"new-object <N>",
+		"view-object <enter>",
+		"view-object <v>",
+	}, keys)
+
+	assert.NoError(t, os.WriteFile(path, []byte("bindings:\n  new-object: hyper+n\n"), 0o644))
+	_, err = LoadKeymap(path)
+	assert.ErrorContains(t, err, `unknown modifier "hyper"`)
+}
+
+func TestPageKeySequences(t *testing.T) {
+	keymap, err := NewKeymap("vim", map[string][]string{"search": {"g /"}})
+	assert.NoError(t, err)
+	defer func(previous *Keymap) { ActiveKeymap = previous }(ActiveKeymap)
+	ActiveKeymap = keymap
+
+	content := newPageTestContent("Sample", NewContext())
+	deleted := 0
+	content.hotkeys[EventKey(tcell.KeyRune, 'd', 0)] = Hotkey{
+		Title: "Delete Object",
+		Handler: func(event *tcell.EventKey) *tcell.EventKey {
+			deleted++
+			return nil
+		},
+	}
+	page := NewPage(content)
+	press := page.searchFlex.GetItem(0).(*tview.Flex).GetInputCapture()
+	key := func(r rune) *tcell.EventKey { return tcell.NewEventKey(tcell.KeyRune, r, 0) }
+
+	assert.Nil(t, press(key('d')))
+	assert.Equal(t, 0, deleted)
+	assert.Nil(t, press(key('d')))
+	assert.Equal(t, 1, deleted)
+
+	// an incomplete sequence is dropped by a key that does not continue it
+	assert.Nil(t, press(key('d')))
+	assert.Nil(t, press(key('g')))
+	assert.Nil(t, press(key('/')))
+	assert.Equal(t, 1, deleted)
+	assert.True(t, page.isSearchActive)
+	page.deactivateSearch()
+
+	// navigation actions pass on the keys of the list
+	assert.Nil(t, press(key('g')))
+	assert.Equal(t, tcell.KeyHome, press(key('g')).Key())
+	assert.Equal(t, tcell.KeyDown, press(key('j')).Key())
+
+	// unbound keys are passed on as they are
+	assert.Equal(t, 'x', press(key('x')).Rune())
+}
+
+func TestHotkeyInfoBoxShowsBoundKeys(t *testing.T) {
+	keymap, err := NewKeymap("vim", map[string][]string{"new-object": {"N", "ctrl+n"}})
+	assert.NoError(t, err)
+	defer func(previous *Keymap) { ActiveKeymap = previous }(ActiveKeymap)
+	ActiveKeymap = keymap
+
+	info := NewHotkeyInfoBox()
+	info.Update(NewObjectsPage(NewContext()), nil)
+
+	shown := map[string]string{}
+	rows, columns := info.GetRowCount(), info.GetColumnCount()
+	for row := range rows {
+		for column := 0; column+1 < columns; column += 2 {
+			if cell := info.GetCell(row, column+1); cell.Text != "" {
+				shown[cell.Text] = info.GetCell(row, column).Text
+			}
+		}
+	}
+	assert.Equal(t, "<N> <ctrl+n>", shown["New Object"])
+	assert.Equal(t, "<d><d>", shown["Delete Object"])
+	assert.Equal(t, "<v>", shown["View Object"])
+}
+
+func TestHotkeyKeysMatchPages(t *testing.T) {
+	c := NewContext()
+	dir := t.TempDir()
+	archivePage := NewObjectsPage(c)
+	archivePage.archive = &s3lib.Archive{}
+
+	pages := map[string]struct {
+		hotkeys map[tcell.EventKey]Hotkey
+		keys    hotkeyKeys
+	}{
+		"page":      {(&Page{}).hotkeys(), pageHotkeyKeys},
+		"buckets":   {NewBucketsPage(c).Hotkeys(), bucketsHotkeyKeys},
+		"bucket":    {NewBucketPage(c).Hotkeys(), bucketHotkeyKeys},
+		"objects":   {NewObjectsPage(c).Hotkeys(), objectsHotkeyKeys},
+		"archive":   {archivePage.Hotkeys(), archiveHotkeyKeys},
+		"object":    {NewObjectPage(c).Hotkeys(), objectHotkeyKeys},
+		"transfers": {NewTransfersPage(c).Hotkeys(), transfersHotkeyKeys},
+		"local":     {NewLocalPage(c, dir).Hotkeys(), localHotkeyKeys},
+	}
+	for name, page := range pages {
+		assert.Len(t, page.hotkeys, len(page.keys), name)
+		for title, key := range page.keys {
+			hotkey, ok := page.hotkeys[key]
+			if assert.True(t, ok, "%s: %s", name, title) {
+				assert.Equal(t, title, hotkey.Title, name)
+				assert.NotNil(t, hotkey.Handler, "%s: %s", name, title)
+			}
+		}
+	}
+
+	fileManager := NewDualPanePage(c, dir).Hotkeys()
+	scope := keymapScopes()["file manager"]
+	assert.Len(t, scope, len(fileManager))
+	for key, hotkey := range fileManager {
+		assert.Equal(t, hotkey.Title, scope[key].Title)
+		assert.NotNil(t, hotkey.Handler, hotkey.Title)
+	}
+}
+
+func TestObjectPageReboundEditKey(t *testing.T) {
+	keymap, err := NewKeymap("", map[string][]string{"edit-object": {"E"}})
+	assert.NoError(t, err)
+	defer func(previous *Keymap) { ActiveKeymap = previous }(ActiveKeymap)
+	ActiveKeymap = keymap
+
+	useEditor(t, `sh -c 'echo mine >> "$1"' sh`)
+	client := editConflictClient()
+	content := NewObjectPage(testContextWithClient(client).WithErrorFunc(func(err error) { t.Error(err) }))
+	page := NewPage(content)
+	press := func(r rune) {
+		event := page.searchFlex.GetItem(0).(*tview.Flex).GetInputCapture()(tcell.NewEventKey(tcell.KeyRune, r, 0))
+		if event != nil {
+			content.InputHandler()(event, func(tview.Primitive) {})
+		}
+	}
+
+	press('e')
+	assert.Equal(t, "payload\n", readTestObject(t, client, "object.txt"))
+
+	press('E')
+	assert.Equal(t, "payload\nmine\n", readTestObject(t, client, "object.txt"))
+}
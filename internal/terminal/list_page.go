@@ -1,10 +1,12 @@
 package terminal
 
 import (
+	"context"
 	"strings"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
+	"github.com/schidstorm/s3tool/internal/s3lib"
 )
 
 var listTableRowStyle = DefaultStyle.Foreground(DefaultTheme.PrimaryColor)
@@ -15,12 +17,26 @@ type Row struct {
 	Columns []string
 }
 
+// loadMoreThreshold is the number of rows left below the selection at which
+// the next page of a paginated list is fetched.
+const loadMoreThreshold = 100
+
 type ListPage[TItem any] struct {
 	*tview.Flex
 
 	tviewTable  *tview.Table
+	status      *tview.TextView
 	table       *Table[TItem]
 	multiSelect bool
+
+	// pages fetches the rows after the loaded ones, nil once all are loaded
+	pages       s3lib.Paginator[TItem]
+	pagesCtx    context.Context
+	cancelPages context.CancelFunc
+	loadContext Context
+	loadingMore bool
+
+	selectionChanged func()
 }
 
 func NewListPage[TItem any]() *ListPage[TItem] {
@@ -31,14 +47,25 @@ func NewListPage[TItem any]() *ListPage[TItem] {
 	flex.SetDirection(tview.FlexRow)
 	flex.AddItem(table, 0, 1, true)
 
+	// the status line is only shown while more rows are loaded
+	status := tview.NewTextView().SetTextColor(DefaultTheme.SecondaryColor)
+	flex.AddItem(status, 0, 0, false)
+
 	listPage := &ListPage[TItem]{
 		tviewTable:  table,
+		status:      status,
 		Flex:        flex,
 		table:       NewTable[TItem](),
 		multiSelect: true,
 	}
 
 	table.SetInputCapture(listPage.inputCapture)
+	table.SetSelectionChangedFunc(func(row, column int) {
+		listPage.loadMoreIfNeeded()
+		if listPage.selectionChanged != nil {
+			listPage.selectionChanged()
+		}
+	})
 
 	listPage.update()
 	return listPage
@@ -94,16 +121,121 @@ func (b *ListPage[TItem]) SetSelectedFunc(f func(item TItem)) {
 	})
 }
 
+// SetSelectionChangedFunc sets a function that is called whenever another
+// row is selected.
+func (b *ListPage[TItem]) SetSelectionChangedFunc(f func()) {
+	b.selectionChanged = f
+}
+
 func (b *ListPage[TItem]) SetSearch(search string) {
 	b.table.SetFilter(search)
 	b.update()
+	b.loadMoreIfNeeded()
+}
+
+// LoadPages replaces the rows by the first page of paginator. The following
+// pages are fetched when the selection comes close to the last row, in the
+// background if c can queue updates of the UI. Errors of these fetches are
+// reported to c.
+func (b *ListPage[TItem]) LoadPages(c Context, paginator s3lib.Paginator[TItem]) error {
+	b.Close()
+	b.ClearRows()
+
+	b.pagesCtx, b.cancelPages = context.WithCancel(context.Background())
+	b.loadContext = c
+
+	if paginator.HasMorePages() {
+		items, err := paginator.NextPage(b.pagesCtx)
+		if err != nil {
+			return err
+		}
+		b.AddAll(items)
+	}
+	if paginator.HasMorePages() {
+		b.pages = paginator
+		b.loadMoreIfNeeded()
+	}
+	return nil
+}
+
+// Close cancels fetching further pages.
+func (b *ListPage[TItem]) Close() {
+	if b.cancelPages != nil {
+		b.cancelPages()
+	}
+	b.pages, b.cancelPages, b.loadingMore = nil, nil, false
+	b.setStatus("")
+}
+
+// HasMoreRows reports whether there are pages left to fetch.
+func (b *ListPage[TItem]) HasMoreRows() bool {
+	return b.pages != nil
+}
+
+func (b *ListPage[TItem]) loadMoreIfNeeded() {
+	row, _ := b.tviewTable.GetSelection()
+	if b.pages == nil || b.loadingMore || len(b.table.filteredRows)-row >= loadMoreThreshold {
+		return
+	}
+
+	queueUpdate := b.loadContext.QueueUpdateFunc()
+	if queueUpdate == nil {
+		items, err := b.pages.NextPage(b.pagesCtx)
+		b.addPage(items, err)
+		return
+	}
+
+	b.loadingMore = true
+	b.setStatus("Loading more…")
+	pages, ctx := b.pages, b.pagesCtx
+	go func() {
+		items, err := pages.NextPage(ctx)
+		queueUpdate(func() {
+			if ctx.Err() != nil {
+				// closed or reloaded in the meantime
+				return
+			}
+			b.loadingMore = false
+			b.setStatus("")
+			b.addPage(items, err)
+		})
+	}()
+}
+
+func (b *ListPage[TItem]) addPage(items []TItem, err error) {
+	if err != nil {
+		// fetching stops, reloading the page starts over
+		b.pages = nil
+		b.loadContext.SetError(err)
+		return
+	}
+
+	b.appendAll(items)
+	if !b.pages.HasMorePages() {
+		b.pages = nil
+		return
+	}
+	b.loadMoreIfNeeded()
+}
+
+func (b *ListPage[TItem]) setStatus(text string) {
+	height := 0
+	if text != "" {
+		height = 1
+	}
+	b.status.SetText(text)
+	b.ResizeItem(b.status, height, 0)
 }
 
 func (b *ListPage[TItem]) update() {
 	b.tviewTable.Clear()
 
+	sortColumn, descending := b.table.Sort()
 	columns := b.table.Columns()
 	for colIndex, col := range columns {
+		if col == sortColumn {
+			col += sortIndicator(descending)
+		}
 		cell := tview.NewTableCell(col)
 		cell.SetAlign(tview.AlignLeft)
 		cell.SetExpansion(1)
@@ -113,20 +245,24 @@ func (b *ListPage[TItem]) update() {
 	}
 
 	for rowIndex, row := range b.table.Rows() {
-		highlighted := b.table.IsHighlighted(rowIndex)
-		for columnIndex, item := range row {
-			cell := tview.NewTableCell(item)
-			cell.SetAlign(tview.AlignLeft)
-			cell.SetExpansion(1)
-			if highlighted {
-				cell.SetStyle(listTableMultiHighlightedRowStyle)
-			} else {
-				cell.SetStyle(listTableRowStyle)
-			}
-			cell.SetSelectable(true)
-			cell.SelectedStyle = DefaultTheme.HighlightStyle
-			b.tviewTable.SetCell(rowIndex+1, columnIndex, cell)
+		b.setRow(rowIndex, row)
+	}
+}
+
+func (b *ListPage[TItem]) setRow(rowIndex int, row []string) {
+	highlighted := b.table.IsHighlighted(rowIndex)
+	for columnIndex, item := range row {
+		cell := tview.NewTableCell(item)
+		cell.SetAlign(tview.AlignLeft)
+		cell.SetExpansion(1)
+		if highlighted {
+			cell.SetStyle(listTableMultiHighlightedRowStyle)
+		} else {
+			cell.SetStyle(listTableRowStyle)
 		}
+		cell.SetSelectable(true)
+		cell.SelectedStyle = DefaultTheme.HighlightStyle
+		b.tviewTable.SetCell(rowIndex+1, columnIndex, cell)
 	}
 }
 
@@ -161,6 +297,24 @@ func (b *ListPage[TItem]) AddAll(rows []TItem) {
 	b.update()
 }
 
+// appendAll adds rows without rebuilding the existing ones, which keeps
+// adding pages to long lists fast.
+func (b *ListPage[TItem]) appendAll(items []TItem) {
+	if name, _ := b.table.Sort(); name != "" {
+		// sorted rows are inserted in between the existing ones
+		b.AddAll(items)
+		return
+	}
+
+	first := len(b.table.filteredRows)
+	for _, item := range items {
+		b.table.Add(item)
+	}
+	for rowIndex := first; rowIndex < len(b.table.filteredRows); rowIndex++ {
+		b.setRow(rowIndex, b.table.allRows[b.table.filteredRows[rowIndex]])
+	}
+}
+
 func (b *ListPage[TItem]) ClearRows() {
 	b.table.Clear()
 	b.update()
@@ -176,6 +330,50 @@ func (b *ListPage[TItem]) AddColumn(name string, filler func(item TItem) string)
 	b.update()
 }
 
+// AddSortableColumn adds a column that can be sorted by compare, see
+// SortHotkey.
+func (b *ListPage[TItem]) AddSortableColumn(name string, filler func(item TItem) string, compare func(a, b TItem) int) {
+	b.table.AddSortableColumn(name, filler, compare)
+	b.update()
+}
+
+// SetPinnedFunc keeps the items for which pinned returns true on top when
+// sorting.
+func (b *ListPage[TItem]) SetPinnedFunc(pinned func(item TItem) bool) {
+	b.table.SetPinnedFunc(pinned)
+	b.update()
+}
+
+// CycleSort sorts the rows by the next sortable column or direction.
+func (b *ListPage[TItem]) CycleSort() {
+	b.table.CycleSort()
+	b.update()
+}
+
+// SortHotkey returns the hotkey that cycles through the sort orders of the
+// sortable columns.
+func (b *ListPage[TItem]) SortHotkey() Hotkey {
+	return Hotkey{
+		Title: "Sort",
+		Handler: func(event *tcell.EventKey) *tcell.EventKey {
+			b.CycleSort()
+			return nil
+		},
+	}
+}
+
+func sortIndicator(descending bool) string {
+	if descending {
+		return " ▼"
+	}
+	return " ▲"
+}
+
+func (b *ListPage[TItem]) ClearColumns() {
+	b.table.ClearColumns()
+	b.update()
+}
+
 func (b *ListPage[TItem]) SetMultiSelect(enabled bool) {
 	b.multiSelect = enabled
 	if !enabled {
@@ -1,9 +1,13 @@
 package terminal
 
 import (
+	"cmp"
+	"context"
+	"errors"
 	"fmt"
 	"testing"
 
+	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -46,3 +50,121 @@ func TestListPage(t *testing.T) {
 		{"Number", "Square"},
 	}, rows)
 }
+
+func TestListPageSort(t *testing.T) {
+	page := NewListPage[int]()
+	page.AddSortableColumn("Number", func(item int) string { return fmt.Sprintf("%d", item) }, cmp.Compare[int])
+	page.AddColumn("Square", func(item int) string { return fmt.Sprintf("%d", item*item) })
+	page.AddAll([]int{2, 10, 1})
+
+	hotkey := page.SortHotkey()
+	hotkey.Handler(nil)
+	assert.EqualValues(t, [][]string{
+		{"Number ▲", "Square"},
+		{"1", "1"},
+		{"2", "4"},
+		{"10", "100"},
+	}, getTableRows(page.tviewTable))
+
+	hotkey.Handler(nil)
+	assert.Equal(t, []string{"Number ▼", "Square"}, getTableRows(page.tviewTable)[0])
+
+	// reloading and searching keep the order
+	page.ClearRows()
+	page.AddAll([]int{3, 1, 2})
+	page.Add(4)
+	page.SetSearch("1")
+	assert.EqualValues(t, [][]string{
+		{"Number ▼", "Square"},
+		{"4", "16"},
+		{"1", "1"},
+	}, getTableRows(page.tviewTable))
+}
+
+// testPaginator returns pages of size numbers until count numbers have been
+// returned, or fails with err once the numbers are exhausted.
+type testPaginator struct {
+	size, count, next int
+	err               error
+	ctx               context.Context
+}
+
+func (p *testPaginator) NextPage(ctx context.Context, optFns ...func(*s3.Options)) ([]int, error) {
+	p.ctx = ctx
+	if p.next >= p.count && p.err != nil {
+		return nil, p.err
+	}
+	var items []int
+	for ; p.next < p.count && len(items) < p.size; p.next++ {
+		items = append(items, p.next)
+	}
+	return items, nil
+}
+
+func (p *testPaginator) HasMorePages() bool {
+	return p.next < p.count || p.err != nil
+}
+
+func newNumberListPage() *ListPage[int] {
+	page := NewListPage[int]()
+	page.AddColumn("Number", func(item int) string { return fmt.Sprintf("%d", item) })
+	return page
+}
+
+func TestListPageLoadPagesOnDemand(t *testing.T) {
+	page := newNumberListPage()
+	paginator := &testPaginator{size: 150, count: 1000}
+
+	assert.NoError(t, page.LoadPages(NewContext(), paginator))
+	assert.Len(t, getTableRows(page.tviewTable), 151)
+	assert.True(t, page.HasMoreRows())
+
+	page.tviewTable.Select(100, 0)
+	rows := getTableRows(page.tviewTable)
+	assert.Len(t, rows, 301)
+	assert.Equal(t, []string{"299"}, rows[300])
+
+	page.Close()
+	assert.False(t, page.HasMoreRows())
+	assert.Error(t, paginator.ctx.Err())
+
+	page.tviewTable.Select(300, 0)
+	assert.Len(t, getTableRows(page.tviewTable), 301)
+}
+
+func TestListPageLoadPagesInBackground(t *testing.T) {
+	queued := make(chan func(), 1)
+	c := NewContext().
+		WithErrorFunc(func(err error) { t.Error(err) }).
+		WithQueueUpdateFunc(func(f func()) { queued <- f })
+
+	page := newNumberListPage()
+	assert.NoError(t, page.LoadPages(c, &testPaginator{size: 10, count: 35}))
+	assert.Len(t, getTableRows(page.tviewTable), 11)
+	assert.Equal(t, "Loading more…", page.status.GetText(true))
+
+	for page.HasMoreRows() {
+		(<-queued)()
+	}
+	assert.Len(t, getTableRows(page.tviewTable), 36)
+	assert.Empty(t, page.status.GetText(true))
+
+	// results of fetches that finish after a reload are dropped
+	assert.NoError(t, page.LoadPages(c, &testPaginator{size: 10, count: 35}))
+	assert.NoError(t, page.LoadPages(c, &testPaginator{size: 5, count: 5}))
+	(<-queued)()
+	assert.Len(t, getTableRows(page.tviewTable), 6)
+}
+
+func TestListPageLoadPagesError(t *testing.T) {
+	var errs []error
+	c := NewContext().WithErrorFunc(func(err error) { errs = append(errs, err) })
+
+	page := newNumberListPage()
+	assert.NoError(t, page.LoadPages(c, &testPaginator{size: 10, count: 10, err: errors.New("denied")}))
+	assert.Len(t, getTableRows(page.tviewTable), 11)
+	assert.False(t, page.HasMoreRows())
+	assert.Len(t, errs, 1)
+
+	assert.Error(t, page.LoadPages(c, &testPaginator{err: errors.New("denied")}))
+}
@@ -0,0 +1,130 @@
+package terminal
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/schidstorm/s3tool/internal/s3lib"
+)
+
+// localFile is a local file and the key it is uploaded to.
+type localFile struct {
+	path string
+	key  string
+	size int64
+}
+
+// expandLocalEntries returns the files among entries together with all files
+// below the directories among entries. The keys are the paths relative to
+// the directory of the entries, below dstPrefix. The walk stops once ctx is
+// done.
+func expandLocalEntries(ctx context.Context, entries []LocalEntry, dstPrefix string) ([]localFile, error) {
+	var files []localFile
+	for _, entry := range entries {
+		if !entry.Dir {
+			files = append(files, localFile{path: entry.Path, key: dstPrefix + entry.Name, size: entry.Size})
+			continue
+		}
+
+		base := filepath.Dir(entry.Path)
+		err := filepath.WalkDir(entry.Path, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if !d.Type().IsRegular() {
+				// directories are created implicitly, special files are skipped
+				return nil
+			}
+
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			relativePath, err := filepath.Rel(base, path)
+			if err != nil {
+				return err
+			}
+			files = append(files, localFile{path: path, key: dstPrefix + filepath.ToSlash(relativePath), size: info.Size()})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// localFilesSummary describes the number and total size of files.
+func localFilesSummary(files []localFile) string {
+	var size int64
+	for _, file := range files {
+		size += file.size
+	}
+	noun := "files"
+	if len(files) == 1 {
+		noun = "file"
+	}
+	return fmt.Sprintf("%d %s (%s)", len(files), noun, humanizeSize(&size))
+}
+
+// uploadFiles uploads files into the bucket of c in the background. label
+// names the uploaded files in the transfers page.
+func uploadFiles(c Context, label string, files []localFile, done func(err error)) {
+	client, bucket := c.S3Client(), c.Bucket()
+
+	var total int64
+	for _, file := range files {
+		total += file.size
+	}
+
+	c.Transfers().Submit(NewTransfer(TransferUpload, bucket, label,
+		func(ctx context.Context, progress func(transferred, total int64)) error {
+			var finished int64
+			for _, file := range files {
+				err := client.UploadFile(ctx, bucket, file.key, file.path, partOptions(s3lib.WithProgress(func(transferred, _ int64) {
+					progress(finished+transferred, total)
+				}))...)
+				if err != nil {
+					return err
+				}
+
+				finished += file.size
+				progress(finished, total)
+			}
+			return nil
+		},
+		transferDone(c, done),
+	))
+}
+
+// removeLocalEntries removes entries and everything below them.
+func removeLocalEntries(entries []LocalEntry) error {
+	for _, entry := range entries {
+		if err := os.RemoveAll(entry.Path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runSync performs plan in the background. The transfers page shows the
+// synced prefix.
+func runSync(c Context, plan s3lib.SyncPlan, done func(err error)) {
+	remote := plan.Destination
+	if !remote.IsRemote() {
+		remote = plan.Source
+	}
+
+	c.Transfers().Submit(NewTransfer(TransferSync, remote.Bucket, remote.Prefix,
+		func(ctx context.Context, progress func(transferred, total int64)) error {
+			return plan.Run(ctx, progress, partOptions()...)
+		},
+		transferDone(c, done),
+	))
+}
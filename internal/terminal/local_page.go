@@ -0,0 +1,174 @@
+package terminal
+
+import (
+	"cmp"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// LocalEntry is a file or directory in the local filesystem.
+type LocalEntry struct {
+	Name    string
+	Path    string
+	Dir     bool
+	Size    int64
+	ModTime time.Time
+}
+
+// IsParent reports whether e is the ".." entry leading to the parent
+// directory.
+func (e LocalEntry) IsParent() bool {
+	return e.Name == ".."
+}
+
+// LocalPage lists a directory of the local filesystem. Selecting a directory
+// shows it in place.
+type LocalPage struct {
+	*ListPage[LocalEntry]
+
+	context Context
+	dir     string
+}
+
+func NewLocalPage(context Context, dir string) *LocalPage {
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+
+	page := &LocalPage{
+		ListPage: NewListPage[LocalEntry](),
+		context:  context,
+		dir:      dir,
+	}
+
+	page.AddSortableColumn("Name", func(item LocalEntry) string {
+		if item.Dir && !item.IsParent() {
+			return item.Name + "/"
+		}
+		return item.Name
+	}, func(a, b LocalEntry) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	page.AddSortableColumn("Size", func(item LocalEntry) string {
+		if item.Dir {
+			return ""
+		}
+		return humanizeSize(&item.Size)
+	}, func(a, b LocalEntry) int {
+		return cmp.Compare(a.Size, b.Size)
+	})
+	page.AddSortableColumn("Last Modified", func(item LocalEntry) string {
+		if item.IsParent() {
+			return ""
+		}
+		return humanizeTime(&item.ModTime)
+	}, func(a, b LocalEntry) int {
+		return a.ModTime.Compare(b.ModTime)
+	})
+	page.SetPinnedFunc(LocalEntry.IsParent)
+
+	page.SetSelectedFunc(func(selected LocalEntry) {
+		if selected.Dir {
+			page.navigate(selected.Path)
+		}
+	})
+
+	return page
+}
+
+// Dir returns the directory shown.
+func (p *LocalPage) Dir() string {
+	return p.dir
+}
+
+func (p *LocalPage) Context() Context {
+	return p.context
+}
+
+func (p *LocalPage) Title() string {
+	return "Local - " + p.dir
+}
+
+// localHotkeyKeys are the keys of the hotkeys of LocalPage.
+var localHotkeyKeys = hotkeyKeys{
+	"Parent Directory": EventKey(tcell.KeyBackspace, 0, 0),
+	"Sort":             EventKey(tcell.KeyRune, 'o', 0),
+}
+
+func (p *LocalPage) Hotkeys() map[tcell.EventKey]Hotkey {
+	return localHotkeyKeys.bind(map[string]func(*tcell.EventKey) *tcell.EventKey{
+		"Parent Directory": func(event *tcell.EventKey) *tcell.EventKey {
+			p.navigate(filepath.Dir(p.dir))
+			return nil
+		},
+		"Sort": p.SortHotkey().Handler,
+	})
+}
+
+// selectedEntries returns the highlighted entries, or the selected row if
+// none are highlighted. The parent directory entry is never part of it.
+func (p *LocalPage) selectedEntries() []LocalEntry {
+	items := p.table.GetHighlightedItems()
+	if len(items) == 0 {
+		if entry, ok := p.GetSelectedRow(); ok {
+			items = []LocalEntry{entry}
+		}
+	}
+
+	var entries []LocalEntry
+	for _, item := range items {
+		if !item.IsParent() {
+			entries = append(entries, item)
+		}
+	}
+	return entries
+}
+
+// navigate shows dir in place of the current directory.
+func (p *LocalPage) navigate(dir string) {
+	p.dir = dir
+	p.reload()
+	p.tviewTable.ScrollToBeginning().Select(1, 0)
+}
+
+func (p *LocalPage) reload() {
+	err := p.Load()
+	if err != nil {
+		p.context.SetError(err)
+	}
+}
+
+func (p *LocalPage) Load() error {
+	p.ClearRows()
+
+	dirEntries, err := os.ReadDir(p.dir)
+	if err != nil {
+		return err
+	}
+
+	var entries []LocalEntry
+	if parent := filepath.Dir(p.dir); parent != p.dir {
+		entries = append(entries, LocalEntry{Name: "..", Path: parent, Dir: true})
+	}
+	for _, dirEntry := range dirEntries {
+		info, err := dirEntry.Info()
+		if err != nil {
+			// the entry was removed after reading the directory
+			continue
+		}
+		entries = append(entries, LocalEntry{
+			Name:    dirEntry.Name(),
+			Path:    filepath.Join(p.dir, dirEntry.Name()),
+			Dir:     info.IsDir(),
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		})
+	}
+
+	p.AddAll(entries)
+	return nil
+}
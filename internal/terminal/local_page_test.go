@@ -0,0 +1,74 @@
+package terminal
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeLocalFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+}
+
+func TestLocalPage(t *testing.T) {
+	dir := t.TempDir()
+	writeLocalFiles(t, dir, map[string]string{
+		"a.txt":       "a",
+		"sub/b.txt":   "bb",
+		"sub/c/d.txt": "ddd",
+	})
+
+	page := NewLocalPage(NewContext().WithErrorFunc(func(err error) { t.Error(err) }), dir)
+	assert.NoError(t, page.Load())
+
+	rows := getTableRows(page.tviewTable)
+	assert.Equal(t, []string{"Name", "Size", "Last Modified"}, rows[0])
+	assert.Equal(t, []string{"..", "", ""}, rows[1])
+	assert.Equal(t, []string{"a.txt", "1 B"}, rows[2][:2])
+	assert.Equal(t, []string{"sub/", ""}, rows[3][:2])
+
+	page.navigate(filepath.Join(dir, "sub"))
+	assert.Equal(t, filepath.Join(dir, "sub"), page.Dir())
+	assert.Len(t, getTableRows(page.tviewTable), 4)
+
+	hotkey, ok := findHotkey(page.Hotkeys(), tcell.NewEventKey(tcell.KeyBackspace2, 0, 0))
+	assert.True(t, ok)
+	hotkey.Handler(nil)
+	assert.Equal(t, dir, page.Dir())
+}
+
+func TestExpandLocalEntries(t *testing.T) {
+	dir := t.TempDir()
+	writeLocalFiles(t, dir, map[string]string{
+		"a.txt":       "a",
+		"sub/b.txt":   "bb",
+		"sub/c/d.txt": "ddd",
+	})
+
+	files, err := expandLocalEntries(context.Background(), []LocalEntry{
+		{Name: "a.txt", Path: filepath.Join(dir, "a.txt"), Size: 1},
+		{Name: "sub", Path: filepath.Join(dir, "sub"), Dir: true},
+	}, "upload/")
+	assert.NoError(t, err)
+
+	var keys []string
+	for _, file := range files {
+		keys = append(keys, file.key)
+	}
+	assert.Equal(t, []string{"upload/a.txt", "upload/sub/b.txt", "upload/sub/c/d.txt"}, keys)
+	assert.Equal(t, "3 files (6 B)", localFilesSummary(files))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = expandLocalEntries(ctx, []LocalEntry{{Name: "sub", Path: filepath.Join(dir, "sub"), Dir: true}}, "")
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -5,75 +5,228 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"errors"
+	"fmt"
 	"io"
 	"os"
-	"path/filepath"
+
+	"github.com/schidstorm/s3tool/internal/s3lib"
 )
 
-func editObject(c Context) error {
-	tmpFilePath, err := downloadFileToTmp(c)
-	if err != nil {
-		return err
-	}
-	defer func() {
-		_ = os.RemoveAll(filepath.Dir(tmpFilePath))
-	}()
+// editObject opens the object of c in the editor and uploads it once it was
+// changed. Compressed objects are edited decompressed and compressed again
+// with the same codec before they are uploaded; if that is not possible they
+// can only be viewed. The upload only replaces the object if it was not
+// changed since the download, see saveEditedObject.
+func editObject(c Context) {
+	var etag string
+	downloadObject(c, "", func(tmpDir, tmpFilePath string) error {
+		filePath, codec, err := decompressFile(tmpDir, c.ObjectKey(), tmpFilePath)
+		if err != nil {
+			return err
+		}
+		if codec != nil {
+			if err := codec.checkCompress(); err != nil {
+				viewInsteadModal(c, err, tmpDir, filePath)
+				return nil
+			}
+		}
 
-	oldHash, err := fileHash(tmpFilePath)
-	if err != nil {
-		return err
-	}
+		oldHash, err := fileHash(filePath)
+		if err != nil {
+			return err
+		}
 
-	err = EditFile(c, tmpFilePath)
-	if err != nil {
-		return err
-	}
+		err = EditFile(c, filePath)
+		if err != nil {
+			return err
+		}
 
-	if _, err := os.Stat(tmpFilePath); os.IsNotExist(err) {
-		return errors.New("file does not exist after editing")
-	}
+		if _, err := os.Stat(filePath); os.IsNotExist(err) {
+			return errors.New("file does not exist after editing")
+		}
 
-	newHash, err := fileHash(tmpFilePath)
-	if err != nil {
-		return err
-	}
+		newHash, err := fileHash(filePath)
+		if err != nil {
+			return err
+		}
 
-	if oldHash != newHash {
-		err = c.S3Client().UploadFile(context.Background(), c.Bucket(), c.ObjectKey(), tmpFilePath)
+		if oldHash == newHash {
+			return os.RemoveAll(tmpDir)
+		}
+
+		if codec != nil {
+			err = convertFile(tmpFilePath, filePath, codec.compress)
+			if err != nil {
+				// the edited file stays for the user to save it otherwise
+				c.SetError(fmt.Errorf("%w, the edited file is kept at %s", err, filePath))
+				return nil
+			}
+		}
+
+		saveEditedObject(c, etag, tmpDir, tmpFilePath, filePath)
+		return nil
+	}, s3lib.WithDownloadedETag(&etag))
+}
+
+// viewInsteadModal offers to show the downloaded object at filePath because
+// it cannot be edited for reason. It takes over tmpDir.
+func viewInsteadModal(c Context, reason error, tmpDir, filePath string) {
+	message := fmt.Sprintf("%s cannot be edited: %v.\nView it instead?", c.ObjectKey(), reason)
+	choiceModal(c, message, []string{"View", "Cancel"}, func(buttonLabel string) {
+		defer func() {
+			_ = os.RemoveAll(tmpDir)
+		}()
+		if buttonLabel != "View" {
+			return
+		}
+		if err := ShowFile(c, filePath); err != nil {
+			c.SetError(err)
+		}
+	})
+}
+
+// viewObject shows the object of c, or the version selected by versionID if
+// it is not empty. Compressed objects are shown decompressed.
+func viewObject(c Context, versionID string) {
+	downloadObject(c, versionID, func(tmpDir, tmpFilePath string) error {
+		defer func() {
+			_ = os.RemoveAll(tmpDir)
+		}()
+
+		filePath, _, err := decompressFile(tmpDir, c.ObjectKey(), tmpFilePath)
 		if err != nil {
 			return err
 		}
-	}
+		return ShowFile(c, filePath)
+	})
+}
+
+// downloadObject downloads the object of c into a new temporary directory in
+// the background and calls open with the downloaded file once it is done.
+// open takes over the temporary directory and is responsible for removing it.
+// An empty versionID downloads the current version.
+func downloadObject(c Context, versionID string, open func(tmpDir, tmpFilePath string) error, optFns ...func(*s3lib.TransferOptions)) {
+	client, bucket, key := c.S3Client(), c.Bucket(), c.ObjectKey()
 
-	return nil
+	var tmpDir, tmpFilePath string
+	transfer := NewTransfer(TransferDownload, bucket, key,
+		func(ctx context.Context, progress func(transferred, total int64)) error {
+			var err error
+			tmpDir, tmpFilePath, err = downloadFileToTmp(ctx, client, bucket, key, append(optFns, s3lib.WithProgress(progress), s3lib.WithVersion(versionID))...)
+			return err
+		},
+		func(err error) {
+			if err == nil {
+				err = open(tmpDir, tmpFilePath)
+				if err != nil {
+					_ = os.RemoveAll(tmpDir)
+				}
+			}
+			if err != nil && !errors.Is(err, context.Canceled) {
+				c.SetError(err)
+			}
+		},
+	)
+	transfer.VersionID = versionID
+	c.Transfers().Submit(transfer)
 }
 
-func viewObject(c Context) error {
-	tmpFilePath, err := downloadFileToTmp(c)
-	if err != nil {
-		return err
-	}
-	defer func() {
-		_ = os.RemoveAll(filepath.Dir(tmpFilePath))
-	}()
+// saveObject downloads the object of c, or the version selected by versionID
+// if it is not empty, to filePath in the background.
+func saveObject(c Context, versionID, filePath string, done func(err error)) {
+	client, bucket, key := c.S3Client(), c.Bucket(), c.ObjectKey()
+
+	transfer := NewTransfer(TransferDownload, bucket, key,
+		func(ctx context.Context, progress func(transferred, total int64)) error {
+			return client.DownloadFile(ctx, bucket, key, filePath, partOptions(s3lib.WithProgress(progress), s3lib.WithVersion(versionID))...)
+		},
+		transferDone(c, done),
+	)
+	transfer.VersionID = versionID
+	c.Transfers().Submit(transfer)
+}
+
+// uploadObject uploads filePath to key in the bucket of c in the background.
+func uploadObject(c Context, key, filePath string, done func(err error), optFns ...func(*s3lib.TransferOptions)) {
+	client, bucket := c.S3Client(), c.Bucket()
 
-	return ShowFile(c, tmpFilePath)
+	c.Transfers().Submit(NewTransfer(TransferUpload, bucket, key,
+		func(ctx context.Context, progress func(transferred, total int64)) error {
+			return client.UploadFile(ctx, bucket, key, filePath, partOptions(append(optFns, s3lib.WithProgress(progress))...)...)
+		},
+		transferDone(c, done),
+	))
 }
 
-func downloadFileToTmp(c Context) (string, error) {
+// deleteObject deletes key from the bucket of c in the background.
+func deleteObject(c Context, key string, done func(err error)) {
+	client, bucket := c.S3Client(), c.Bucket()
+
+	c.Transfers().Submit(NewTransfer(TransferDelete, bucket, key,
+		func(ctx context.Context, progress func(transferred, total int64)) error {
+			return client.DeleteObject(ctx, bucket, key)
+		},
+		transferDone(c, done),
+	))
+}
+
+// deleteObjectVersion permanently deletes a version of key from the bucket of
+// c in the background.
+func deleteObjectVersion(c Context, key, versionID string, done func(err error)) {
+	client, bucket := c.S3Client(), c.Bucket()
+
+	transfer := NewTransfer(TransferDelete, bucket, key,
+		func(ctx context.Context, progress func(transferred, total int64)) error {
+			return client.DeleteObjectVersion(ctx, bucket, key, versionID)
+		},
+		transferDone(c, done),
+	)
+	transfer.VersionID = versionID
+	c.Transfers().Submit(transfer)
+}
+
+// restoreObjectVersion makes a copy of a version of key the current version
+// in the background.
+func restoreObjectVersion(c Context, key, versionID string, done func(err error)) {
+	client, bucket := c.S3Client(), c.Bucket()
+
+	transfer := NewTransfer(TransferRestore, bucket, key,
+		func(ctx context.Context, progress func(transferred, total int64)) error {
+			return client.RestoreObjectVersion(ctx, bucket, key, versionID)
+		},
+		transferDone(c, done),
+	)
+	transfer.VersionID = versionID
+	c.Transfers().Submit(transfer)
+}
+
+// transferDone returns a transfer callback reporting errors of the transfer
+// before calling done, which may be nil.
+func transferDone(c Context, done func(err error)) func(err error) {
+	return func(err error) {
+		if err != nil && !errors.Is(err, context.Canceled) {
+			c.SetError(err)
+		}
+		if done != nil {
+			done(err)
+		}
+	}
+}
+
+func downloadFileToTmp(ctx context.Context, client s3lib.Client, bucket, key string, optFns ...func(*s3lib.TransferOptions)) (string, string, error) {
 	tmpDir, err := os.MkdirTemp("", "s3tool")
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
-	tmpFilePath := tmpDir + "/" + c.ObjectKey()
+	tmpFilePath := tmpDir + "/" + key
 
-	err = c.S3Client().DownloadFile(context.Background(), c.Bucket(), c.ObjectKey(), tmpFilePath)
+	err = client.DownloadFile(ctx, bucket, key, tmpFilePath, partOptions(optFns...)...)
 	if err != nil {
-		return "", err
+		_ = os.RemoveAll(tmpDir)
+		return "", "", err
 	}
 
-	return tmpFilePath, nil
-
+	return tmpDir, tmpFilePath, nil
 }
 
 func fileHash(filePath string) (string, error) {
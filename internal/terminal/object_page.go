@@ -8,6 +8,7 @@ import (
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
+	"github.com/schidstorm/s3tool/internal/s3lib"
 )
 
 type ObjectPage struct {
@@ -15,6 +16,8 @@ type ObjectPage struct {
 
 	context    Context
 	searchTerm string
+	// object is the metadata shown, as of the last load.
+	object s3lib.ObjectMetadata
 }
 
 func NewObjectPage(context Context) *ObjectPage {
@@ -25,27 +28,6 @@ func NewObjectPage(context Context) *ObjectPage {
 		context: context,
 	}
 
-	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		switch event.Key() {
-		case tcell.KeyRune:
-			if event.Rune() == 'v' {
-				err := viewObject(context)
-				if err != nil {
-					context.SetError(err)
-				}
-				return nil
-			}
-			if event.Rune() == 'e' {
-				err := editObject(context)
-				if err != nil {
-					context.SetError(err)
-				}
-				return nil
-			}
-		}
-		return event
-	})
-
 	return page
 }
 
@@ -70,28 +52,44 @@ func (b *ObjectPage) Title() string {
 	return title
 }
 
+// objectHotkeyKeys are the keys of the hotkeys of ObjectPage.
+var objectHotkeyKeys = hotkeyKeys{
+	"View Object":   EventKey(tcell.KeyRune, 'v', 0),
+	"Edit Object":   EventKey(tcell.KeyRune, 'e', 0),
+	"Presign URL":   EventKey(tcell.KeyRune, 'u', 0),
+	"Edit Tags":     EventKey(tcell.KeyRune, 't', 0),
+	"Edit Metadata": EventKey(tcell.KeyRune, 'm', 0),
+}
+
 func (b *ObjectPage) Hotkeys() map[tcell.EventKey]Hotkey {
-	return map[tcell.EventKey]Hotkey{
-		EventKey(tcell.KeyRune, 'v', 0): {
-			Title: "View Object",
-			Handler: func(event *tcell.EventKey) *tcell.EventKey {
-				err := viewObject(b.context)
-				if err != nil {
-					b.context.SetError(err)
-				}
-				return nil
-			},
+	return objectHotkeyKeys.bind(map[string]func(*tcell.EventKey) *tcell.EventKey{
+		"View Object": func(event *tcell.EventKey) *tcell.EventKey {
+			viewObject(b.context, "")
+			return nil
+		},
+		"Edit Object": func(event *tcell.EventKey) *tcell.EventKey {
+			editObject(b.context)
+			return nil
 		},
-		EventKey(tcell.KeyRune, 'e', 0): {
-			Title: "Edit Object",
-			Handler: func(event *tcell.EventKey) *tcell.EventKey {
-				err := editObject(b.context)
-				if err != nil {
-					b.context.SetError(err)
-				}
-				return nil
-			},
+		"Presign URL": func(event *tcell.EventKey) *tcell.EventKey {
+			presignObjectForm(b.context, "")
+			return nil
 		},
+		"Edit Tags": func(event *tcell.EventKey) *tcell.EventKey {
+			editTagsForm(b.context, b.object.Tags, b.reloadAfter)
+			return nil
+		},
+		"Edit Metadata": func(event *tcell.EventKey) *tcell.EventKey {
+			editPropertiesForm(b.context, b.object, b.reloadAfter)
+			return nil
+		},
+	})
+}
+
+// reloadAfter reloads the page once a change of the object is done.
+func (b *ObjectPage) reloadAfter(err error) {
+	if err := b.Load(); err != nil {
+		b.context.SetError(err)
 	}
 }
 
@@ -106,6 +104,7 @@ func (b *ObjectPage) Load() error {
 	if err != nil {
 		return err
 	}
+	b.object = obj
 
 	var items []item
 	addItem := func(title string, value *string) {
@@ -119,6 +118,9 @@ func (b *ObjectPage) Load() error {
 	addItem("Region", &obj.Region)
 	addItem("Owner", obj.Owner)
 	addItem("Type", obj.Type)
+	addItem("CacheControl", obj.CacheControl)
+	addItem("ContentDisposition", obj.ContentDisposition)
+	addItem("ContentEncoding", obj.ContentEncoding)
 	size := humanizeSize(obj.Size)
 	addItem("Size", &size)
 	addItem("ETag", obj.ETag)
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/schidstorm/s3tool/internal/s3lib"
@@ -30,11 +31,19 @@ func (c *objectTestClient) ListObjects(ctx context.Context, bucket string, prefi
 	return nil
 }
 
+func (c *objectTestClient) ListObjectVersions(ctx context.Context, bucket string, prefix string) s3lib.Paginator[s3lib.Object] {
+	return nil
+}
+
+func (c *objectTestClient) ListObjectsRecursive(ctx context.Context, bucket string, prefix string) s3lib.Paginator[s3lib.Object] {
+	return nil
+}
+
 func (c *objectTestClient) CreateBucket(ctx context.Context, bucket, region string) error {
 	return nil
 }
 
-func (c *objectTestClient) UploadFile(ctx context.Context, bucket, key, filePath string) error {
+func (c *objectTestClient) UploadFile(ctx context.Context, bucket, key, filePath string, optFns ...func(*s3lib.TransferOptions)) error {
 	if c.uploadErr != nil {
 		return c.uploadErr
 	}
@@ -42,17 +51,49 @@ func (c *objectTestClient) UploadFile(ctx context.Context, bucket, key, filePath
 	return nil
 }
 
-func (c *objectTestClient) DownloadFile(ctx context.Context, bucket, key, filePath string) error {
+func (c *objectTestClient) DownloadFile(ctx context.Context, bucket, key, filePath string, optFns ...func(*s3lib.TransferOptions)) error {
 	if c.downloadErr != nil {
 		return c.downloadErr
 	}
 	return os.WriteFile(filePath, c.downloadData, 0o600)
 }
 
+func (c *objectTestClient) ReadObjectRange(ctx context.Context, bucket, key, versionID string, offset, length int64) ([]byte, int64, error) {
+	return nil, 0, nil
+}
+
+func (c *objectTestClient) CopyObject(ctx context.Context, srcBucket, srcKey, bucket, key string, optFns ...func(*s3lib.TransferOptions)) error {
+	return nil
+}
+
 func (c *objectTestClient) GetObject(ctx context.Context, bucket, key string) (s3lib.ObjectMetadata, error) {
 	return s3lib.ObjectMetadata{}, nil
 }
 
+func (c *objectTestClient) GetObjectTags(ctx context.Context, bucket, key string) (map[string]string, error) {
+	return nil, nil
+}
+
+func (c *objectTestClient) PutObjectTags(ctx context.Context, bucket, key string, tags map[string]string) error {
+	return nil
+}
+
+func (c *objectTestClient) SetObjectProperties(ctx context.Context, bucket, key string, props s3lib.ObjectProperties) error {
+	return nil
+}
+
+func (c *objectTestClient) GetBucketConfiguration(ctx context.Context, bucket string) ([]s3lib.BucketSetting, error) {
+	return nil, nil
+}
+
+func (c *objectTestClient) GetBucketDocument(ctx context.Context, bucket string, doc s3lib.BucketDocument) (string, error) {
+	return "", nil
+}
+
+func (c *objectTestClient) PutBucketDocument(ctx context.Context, bucket string, doc s3lib.BucketDocument, document string) error {
+	return nil
+}
+
 func (c *objectTestClient) DeleteBucket(ctx context.Context, bucket string) error {
 	return nil
 }
@@ -61,6 +102,22 @@ func (c *objectTestClient) DeleteObject(ctx context.Context, bucket, key string)
 	return nil
 }
 
+func (c *objectTestClient) DeleteObjects(ctx context.Context, bucket string, keys []string) error {
+	return nil
+}
+
+func (c *objectTestClient) DeleteObjectVersion(ctx context.Context, bucket, key, versionID string) error {
+	return nil
+}
+
+func (c *objectTestClient) RestoreObjectVersion(ctx context.Context, bucket, key, versionID string) error {
+	return nil
+}
+
+func (c *objectTestClient) PresignObject(ctx context.Context, bucket, key, versionID string, method s3lib.PresignMethod, expires time.Duration) (string, error) {
+	return "", nil
+}
+
 func testContextWithClient(client s3lib.Client) Context {
 	return NewContext().
 		WithClient(client).
@@ -111,9 +168,8 @@ func TestFileHashSameAndDifferent(t *testing.T) {
 
 func TestDownloadFileToTmp(t *testing.T) {
 	client := &objectTestClient{downloadData: []byte("payload")}
-	ctx := testContextWithClient(client)
 
-	path, err := downloadFileToTmp(ctx)
+	_, path, err := downloadFileToTmp(context.Background(), client, "bucket", "object.txt")
 	if err != nil {
 		t.Fatalf("downloadFileToTmp failed: %v", err)
 	}
@@ -128,69 +184,61 @@ func TestDownloadFileToTmp(t *testing.T) {
 }
 
 func TestEditObjectNoChangeNoUpload(t *testing.T) {
-	prevEdit := editCommand
-	editCommand = []string{"sh", "-c", "true", "sh"}
-	t.Cleanup(func() { editCommand = prevEdit })
+	useEditor(t, "sh -c true sh")
 
 	client := &objectTestClient{downloadData: []byte("payload")}
 	ctx := testContextWithClient(client)
 
-	if err := editObject(ctx); err != nil {
+	editObject(ctx.WithErrorFunc(func(err error) {
 		t.Fatalf("editObject failed: %v", err)
-	}
+	}))
 	if client.uploadCount != 0 {
 		t.Fatalf("expected no upload on unchanged file, got %d", client.uploadCount)
 	}
 }
 
 func TestEditObjectChangedUploads(t *testing.T) {
-	prevEdit := editCommand
-	editCommand = []string{"sh", "-c", "echo changed >> \"$1\"", "sh"}
-	t.Cleanup(func() { editCommand = prevEdit })
+	useEditor(t, `sh -c 'echo changed >> "$1"' sh`)
 
 	client := &objectTestClient{downloadData: []byte("payload")}
 	ctx := testContextWithClient(client)
 
-	if err := editObject(ctx); err != nil {
+	editObject(ctx.WithErrorFunc(func(err error) {
 		t.Fatalf("editObject failed: %v", err)
-	}
+	}))
 	if client.uploadCount != 1 {
 		t.Fatalf("expected one upload on changed file, got %d", client.uploadCount)
 	}
 }
 
 func TestEditObjectMissingFileAfterEdit(t *testing.T) {
-	prevEdit := editCommand
-	editCommand = []string{"sh", "-c", "rm -f \"$1\"", "sh"}
-	t.Cleanup(func() { editCommand = prevEdit })
+	useEditor(t, `sh -c 'rm -f "$1"' sh`)
 
 	client := &objectTestClient{downloadData: []byte("payload")}
 	ctx := testContextWithClient(client)
 
-	err := editObject(ctx)
+	var err error
+	editObject(ctx.WithErrorFunc(func(e error) { err = e }))
 	if err == nil {
 		t.Fatal("expected error when file is deleted during edit")
 	}
 }
 
 func TestViewObject(t *testing.T) {
-	prevView := viewCommand
-	viewCommand = []string{"sh", "-c", "true", "sh"}
-	t.Cleanup(func() { viewCommand = prevView })
+	useViewer(t, "sh -c true sh")
 
 	client := &objectTestClient{downloadData: []byte("payload")}
 	ctx := testContextWithClient(client)
 
-	if err := viewObject(ctx); err != nil {
+	viewObject(ctx.WithErrorFunc(func(err error) {
 		t.Fatalf("viewObject failed: %v", err)
-	}
+	}), "")
 }
 
 func TestDownloadFileToTmpDownloadError(t *testing.T) {
 	client := &objectTestClient{downloadErr: errors.New("download failed")}
-	ctx := testContextWithClient(client)
 
-	if _, err := downloadFileToTmp(ctx); err == nil {
+	if _, _, err := downloadFileToTmp(context.Background(), client, "bucket", "object.txt"); err == nil {
 		t.Fatal("expected error from downloadFileToTmp")
 	}
 }
@@ -1,12 +1,14 @@
 package terminal
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
 
 	"os"
 	"path"
+	"slices"
 	"strings"
 	"time"
 
@@ -18,44 +20,126 @@ import (
 )
 
 type ObjectsPage struct {
+	*tview.Flex
 	*ListPage[s3lib.Object]
 
-	context Context
+	context      Context
+	showVersions bool
+	preview      *PreviewPane
+	showPreview  bool
+	// archive is set if the page lists the members of an archive
+	archive *s3lib.Archive
+	// cancelBackground stops what runBackground runs
+	cancelBackground context.CancelFunc
 }
 
 func NewObjectsPage(context Context) *ObjectsPage {
-	listPage := NewListPage[s3lib.Object]()
-	listPage.AddColumn("Name", func(item s3lib.Object) string {
-		return strings.TrimPrefix(aws.ToString(item.Object.Key), context.ObjectKey())
+	page := &ObjectsPage{
+		Flex:     tview.NewFlex().SetDirection(tview.FlexColumn),
+		ListPage: NewListPage[s3lib.Object](),
+		context:  context,
+		preview:  NewPreviewPane(),
+	}
+	page.setColumns()
+	page.SetShowPreview(false)
+	page.SetSelectionChangedFunc(page.updatePreview)
+
+	page.SetSelectedFunc(func(selected s3lib.Object) {
+		key := aws.ToString(selected.Object.Key)
+		switch {
+		case selected.IsDirectory():
+			objectsPage := NewObjectsPage(page.context.WithObjectKey(key))
+			objectsPage.SetShowVersions(page.showVersions)
+			objectsPage.SetShowPreview(page.showPreview)
+			objectsPage.archive = page.archive
+			page.context.OpenPage(objectsPage)
+		case selected.IsFile() && page.archive != nil:
+			viewArchiveMember(page.context, page.archive, key)
+		case selected.IsFile():
+			page.context.OpenPage(NewObjectPage(page.context.WithObjectKey(key)))
+		}
+	})
+
+	return page
+}
+
+// SetShowVersions switches between listing the current objects and listing
+// all versions and delete markers of the objects.
+func (b *ObjectsPage) SetShowVersions(showVersions bool) {
+	b.showVersions = showVersions
+	b.setColumns()
+}
+
+// SetShowPreview shows or hides the preview of the selected object next to
+// the list.
+func (b *ObjectsPage) SetShowPreview(showPreview bool) {
+	b.showPreview = showPreview
+	b.Flex.Clear()
+	b.Flex.AddItem(b.ListPage, 0, 1, true)
+	if showPreview {
+		b.Flex.AddItem(b.preview, 0, 1, false)
+		b.updatePreview()
+	} else {
+		b.preview.Clear()
+	}
+}
+
+func (b *ObjectsPage) updatePreview() {
+	if !b.showPreview {
+		return
+	}
+	obj, ok := b.GetSelectedRow()
+	switch {
+	case ok && b.archive != nil:
+		b.preview.ShowMember(b.context, b.archive, obj)
+	case ok:
+		b.preview.Show(b.context, obj)
+	default:
+		b.preview.Clear()
+	}
+}
+
+// Close stops loading rows, the preview and what runs in the background.
+func (b *ObjectsPage) Close() {
+	b.ListPage.Close()
+	b.preview.Close()
+	b.cancelBackgroundWork()
+}
+
+func (b *ObjectsPage) setColumns() {
+	b.ClearColumns()
+	b.AddSortableColumn("Name", func(item s3lib.Object) string {
+		return strings.TrimPrefix(aws.ToString(item.Object.Key), b.context.ObjectKey())
+	}, func(a, b s3lib.Object) int {
+		return strings.Compare(aws.ToString(a.Object.Key), aws.ToString(b.Object.Key))
 	})
-	listPage.AddColumn("Size", func(item s3lib.Object) string {
+	if b.showVersions {
+		b.AddColumn("Version", func(item s3lib.Object) string {
+			if item.IsLatest {
+				return item.VersionID + " (latest)"
+			}
+			return item.VersionID
+		})
+	}
+	b.AddSortableColumn("Size", func(item s3lib.Object) string {
+		if item.IsDeleteMarker() {
+			return "delete marker"
+		}
 		if item.IsDirectory() {
 			return ""
 		}
 		return humanizeSize(item.Object.Size)
+	}, func(a, b s3lib.Object) int {
+		return cmp.Compare(aws.ToInt64(a.Object.Size), aws.ToInt64(b.Object.Size))
 	})
-	listPage.AddColumn("Last Modified", func(item s3lib.Object) string {
+	b.AddSortableColumn("Last Modified", func(item s3lib.Object) string {
 		if item.IsDirectory() {
 			return ""
 		}
 		return humanizeTime(item.Object.LastModified)
+	}, func(a, b s3lib.Object) int {
+		return aws.ToTime(a.Object.LastModified).Compare(aws.ToTime(b.Object.LastModified))
 	})
-
-	page := &ObjectsPage{
-		ListPage: listPage,
-		context:  context,
-	}
-
-	listPage.SetSelectedFunc(func(selected s3lib.Object) {
-		p := strings.TrimPrefix(aws.ToString(selected.Object.Key), context.ObjectKey())
-		if strings.HasSuffix(p, "/") {
-			context.OpenPage(NewObjectsPage(context.WithObjectKey(aws.ToString(selected.Object.Key))))
-		} else {
-			context.OpenPage(NewObjectPage(context.WithObjectKey(aws.ToString(selected.Object.Key))))
-		}
-	})
-
-	return page
 }
 
 func (b *ObjectsPage) Context() Context {
@@ -71,108 +155,569 @@ func (b *ObjectsPage) Title() string {
 	return title
 }
 
+// objectsHotkeyKeys are the keys of the hotkeys of ObjectsPage.
+var objectsHotkeyKeys = hotkeyKeys{
+	"New Object":      EventKey(tcell.KeyRune, 'n', 0),
+	"View Object":     EventKey(tcell.KeyRune, 'v', 0),
+	"Edit Object":     EventKey(tcell.KeyRune, 'e', 0),
+	"Save Object":     EventKey(tcell.KeyRune, 's', 0),
+	"Delete Object":   EventKey(tcell.KeyRune, 'd', 0),
+	"Copy Objects":    EventKey(tcell.KeyRune, 'c', 0),
+	"Move Objects":    EventKey(tcell.KeyRune, 'm', 0),
+	"Rename Object":   EventKey(tcell.KeyRune, 'R', 0),
+	"Presign URL":     EventKey(tcell.KeyRune, 'u', 0),
+	"Tag Objects":     EventKey(tcell.KeyRune, 't', 0),
+	"Mark Objects":    EventKey(tcell.KeyRune, 'y', 0),
+	"Paste Objects":   EventKey(tcell.KeyRune, 'p', 0),
+	"Open Archive":    EventKey(tcell.KeyRune, 'x', 0),
+	"File Manager":    EventKey(tcell.KeyRune, 'f', 0),
+	"Sort":            EventKey(tcell.KeyRune, 'o', 0),
+	"Toggle Preview":  EventKey(tcell.KeyRune, 'P', 0),
+	"Toggle Versions": EventKey(tcell.KeyRune, 'V', 0),
+	"Restore Version": EventKey(tcell.KeyRune, 'r', 0),
+}
+
 func (b *ObjectsPage) Hotkeys() map[tcell.EventKey]Hotkey {
-	return map[tcell.EventKey]Hotkey{
-		EventKey(tcell.KeyRune, 'n', 0): {
-			Title:   "New Object",
-			Handler: func(event *tcell.EventKey) *tcell.EventKey { b.newObjectForm(); return nil },
+	if b.archive != nil {
+		return b.archiveHotkeys()
+	}
+
+	return objectsHotkeyKeys.bind(map[string]func(*tcell.EventKey) *tcell.EventKey{
+		"New Object": func(event *tcell.EventKey) *tcell.EventKey { b.newObjectForm(); return nil },
+		"View Object": func(event *tcell.EventKey) *tcell.EventKey {
+			if obj, ok := b.GetSelectedRow(); ok && !obj.IsDeleteMarker() {
+				viewObject(b.context.WithObjectKey(aws.ToString(obj.Object.Key)), obj.VersionID)
+			}
+			return nil
 		},
-		EventKey(tcell.KeyRune, 'v', 0): {
-			Title: "View Object",
-			Handler: func(event *tcell.EventKey) *tcell.EventKey {
-				if obj, ok := b.GetSelectedRow(); ok {
-					err := viewObject(b.context.WithObjectKey(aws.ToString(obj.Object.Key)))
-					if err != nil {
-						b.context.SetError(err)
-					}
-				}
+		"Edit Object": func(event *tcell.EventKey) *tcell.EventKey {
+			if obj, ok := b.GetSelectedRow(); ok {
+				b.editSelectedObject(obj)
+			}
+			return nil
+		},
+		"Save Object": func(event *tcell.EventKey) *tcell.EventKey {
+			items := b.selectedItems()
+			switch {
+			case len(items) == 1 && items[0].IsFile():
+				b.saveObjectForm(items[0])
+			case len(items) > 0:
+				b.saveObjectsForm(items)
+			}
+			return nil
+		},
+		"Delete Object": func(event *tcell.EventKey) *tcell.EventKey {
+			items := b.selectedItems()
+			if len(items) == 0 {
 				return nil
-			},
+			}
+
+			if b.showVersions {
+				b.deleteVersionsForm(items)
+			} else {
+				b.deleteObjectsForm(items)
+			}
+			return nil
+		},
+		"Copy Objects": func(event *tcell.EventKey) *tcell.EventKey {
+			if items := b.selectedItems(); len(items) > 0 {
+				b.copyObjectsForm(items, false)
+			}
+			return nil
+		},
+		"Move Objects": func(event *tcell.EventKey) *tcell.EventKey {
+			if items := b.selectedItems(); len(items) > 0 {
+				b.copyObjectsForm(items, true)
+			}
+			return nil
+		},
+		"Rename Object": func(event *tcell.EventKey) *tcell.EventKey {
+			if obj, ok := b.GetSelectedRow(); ok {
+				b.renameObjectForm(obj)
+			}
+			return nil
+		},
+		"Presign URL": func(event *tcell.EventKey) *tcell.EventKey {
+			if obj, ok := b.GetSelectedRow(); ok && obj.IsFile() {
+				presignObjectForm(b.context.WithObjectKey(aws.ToString(obj.Object.Key)), obj.VersionID)
+			}
+			return nil
+		},
+		"Tag Objects": func(event *tcell.EventKey) *tcell.EventKey {
+			if items := b.selectedItems(); len(items) > 0 {
+				b.tagObjectsForm(items)
+			}
+			return nil
+		},
+		"Mark Objects": func(event *tcell.EventKey) *tcell.EventKey {
+			if items := b.selectedItems(); len(items) > 0 {
+				b.markObjects(items)
+			}
+			return nil
+		},
+		"Paste Objects": func(event *tcell.EventKey) *tcell.EventKey {
+			b.pasteObjects()
+			return nil
+		},
+		"Open Archive": func(event *tcell.EventKey) *tcell.EventKey {
+			if obj, ok := b.GetSelectedRow(); ok && obj.IsFile() {
+				b.openArchive(obj)
+			}
+			return nil
+		},
+		"File Manager": func(event *tcell.EventKey) *tcell.EventKey {
+			dir, err := os.Getwd()
+			if err != nil {
+				dir = "."
+			}
+			b.context.OpenPage(NewDualPanePage(b.context, dir))
+			return nil
 		},
-		EventKey(tcell.KeyRune, 'e', 0): {
-			Title: "Edit Object",
-			Handler: func(event *tcell.EventKey) *tcell.EventKey {
-				if obj, ok := b.GetSelectedRow(); ok {
-					err := editObject(b.context.WithObjectKey(aws.ToString(obj.Object.Key)))
+		"Sort": b.SortHotkey().Handler,
+		"Toggle Preview": func(event *tcell.EventKey) *tcell.EventKey {
+			b.SetShowPreview(!b.showPreview)
+			return nil
+		},
+		"Toggle Versions": func(event *tcell.EventKey) *tcell.EventKey {
+			b.SetShowVersions(!b.showVersions)
+			b.reload()
+			return nil
+		},
+		"Restore Version": func(event *tcell.EventKey) *tcell.EventKey {
+			if obj, ok := b.GetSelectedRow(); ok {
+				b.restoreVersionForm(obj)
+			}
+			return nil
+		},
+	})
+}
+
+// archiveHotkeyKeys are the keys of the hotkeys of ObjectsPage listing an
+// archive.
+var archiveHotkeyKeys = hotkeyKeys{
+	"View Member":    EventKey(tcell.KeyRune, 'v', 0),
+	"Save Member":    EventKey(tcell.KeyRune, 's', 0),
+	"Sort":           EventKey(tcell.KeyRune, 'o', 0),
+	"Toggle Preview": EventKey(tcell.KeyRune, 'P', 0),
+}
+
+// archiveHotkeys are the hotkeys of pages listing the members of an archive,
+// which can only be read.
+func (b *ObjectsPage) archiveHotkeys() map[tcell.EventKey]Hotkey {
+	return archiveHotkeyKeys.bind(map[string]func(*tcell.EventKey) *tcell.EventKey{
+		"View Member": func(event *tcell.EventKey) *tcell.EventKey {
+			if obj, ok := b.GetSelectedRow(); ok && obj.IsFile() {
+				viewArchiveMember(b.context, b.archive, aws.ToString(obj.Object.Key))
+			}
+			return nil
+		},
+		"Save Member": func(event *tcell.EventKey) *tcell.EventKey {
+			if obj, ok := b.GetSelectedRow(); ok && obj.IsFile() {
+				key := aws.ToString(obj.Object.Key)
+				b.pathForm("Save Member", "Path", path.Base(key), func(filePath string) {
+					saveArchiveMember(b.context, b.archive, key, filePath, nil)
+				})
+			}
+			return nil
+		},
+		"Sort": b.SortHotkey().Handler,
+		"Toggle Preview": func(event *tcell.EventKey) *tcell.EventKey {
+			b.SetShowPreview(!b.showPreview)
+			return nil
+		},
+	})
+}
+
+// openArchive lists the members of the zip or tar archive obj in a new page.
+func (b *ObjectsPage) openArchive(obj s3lib.Object) {
+	key := aws.ToString(obj.Object.Key)
+	if !s3lib.IsArchiveKey(key) {
+		b.context.SetError(fmt.Errorf("%s is not a zip or tar archive", key))
+		return
+	}
+
+	client, bucket := b.context.S3Client(), b.context.Bucket()
+	var archive *s3lib.Archive
+	b.runBackground("Opening archive…", func(ctx context.Context) error {
+		var err error
+		archive, err = s3lib.OpenArchive(ctx, client, bucket, key, obj.VersionID)
+		return err
+	}, func(err error) {
+		if err != nil {
+			b.context.SetError(err)
+			return
+		}
+
+		page := NewObjectsPage(b.context.WithObjectKey(key + "/"))
+		page.archive = archive
+		page.SetShowPreview(b.showPreview)
+		b.context.OpenPage(page)
+	})
+}
+
+// selectedItems returns the highlighted items, or the selected row if none
+// are highlighted.
+func (b *ObjectsPage) selectedItems() []s3lib.Object {
+	items := b.table.GetHighlightedItems()
+	if len(items) == 0 {
+		if obj, ok := b.GetSelectedRow(); ok {
+			items = []s3lib.Object{obj}
+		}
+	}
+	return items
+}
+
+// expandSelection resolves the directories among items for an operation on
+// all objects below them and calls then with the objects. Directories are
+// listed in the background, see runBackground. It reports errors itself.
+func (b *ObjectsPage) expandSelection(items []s3lib.Object, then func(objects []s3lib.Object)) {
+	if b.showVersions {
+		b.context.SetError(errors.New("press V to hide the versions for operations on directories and multiple objects"))
+		return
+	}
+
+	expanded := func(objects []s3lib.Object, err error) {
+		if err != nil {
+			b.context.SetError(err)
+			return
+		}
+		if len(objects) == 0 {
+			b.context.SetError(errors.New("no objects selected"))
+			return
+		}
+		then(objects)
+	}
+	if !slices.ContainsFunc(items, s3lib.Object.IsDirectory) {
+		expanded(expandObjects(context.Background(), b.context, items))
+		return
+	}
+
+	var objects []s3lib.Object
+	b.runBackground("Listing objects…", func(ctx context.Context) error {
+		var err error
+		objects, err = expandObjects(ctx, b.context, items)
+		return err
+	}, func(err error) {
+		expanded(objects, err)
+	})
+}
+
+// runBackground runs work in the background if c can queue updates of the UI
+// and shows status meanwhile, then calls done with its error on the goroutine
+// of the UI. Closing the page or running other work cancels work and done is
+// not called. Without UI work runs right away.
+func (b *ObjectsPage) runBackground(status string, work func(ctx context.Context) error, done func(err error)) {
+	queueUpdate := b.context.QueueUpdateFunc()
+	if queueUpdate == nil {
+		done(work(context.Background()))
+		return
+	}
+
+	b.cancelBackgroundWork()
+	ctx, cancel := context.WithCancel(context.Background())
+	b.cancelBackground = cancel
+	b.setStatus(status)
+	go func() {
+		err := work(ctx)
+		queueUpdate(func() {
+			if ctx.Err() != nil {
+				// closed or running other work in the meantime
+				return
+			}
+			b.cancelBackground = nil
+			b.setStatus("")
+			done(err)
+		})
+	}()
+}
+
+// cancelBackgroundWork stops what runBackground runs.
+func (b *ObjectsPage) cancelBackgroundWork() {
+	if b.cancelBackground != nil {
+		b.cancelBackground()
+		b.cancelBackground = nil
+	}
+}
+
+// selectionLabel names items in the transfers page.
+func selectionLabel(items []s3lib.Object) string {
+	if len(items) == 1 {
+		return aws.ToString(items[0].Object.Key)
+	}
+	return fmt.Sprintf("%d items", len(items))
+}
+
+func (b *ObjectsPage) deleteObjectsForm(items []s3lib.Object) {
+	b.expandSelection(items, func(objects []s3lib.Object) {
+		modalMessage := fmt.Sprintf(
+			"Are you sure you want to delete %s? \n%s",
+			objectsSummary(objects),
+			limitedItemsAsString(objectKeys(objects), func(key string) string { return key }),
+		)
+
+		b.context.Modal(ConfirmModal(modalMessage, func() {
+			deleteObjects(b.context, selectionLabel(items), objects, func(err error) {
+				b.reload()
+			})
+		}))
+	})
+}
+
+func (b *ObjectsPage) saveObjectsForm(items []s3lib.Object) {
+	b.pathForm("Save Objects", "Directory", ".", func(dir string) {
+		b.expandSelection(items, func(objects []s3lib.Object) {
+			modalMessage := fmt.Sprintf("Download %s to %s?", objectsSummary(objects), dir)
+			b.context.Modal(ConfirmModal(modalMessage, func() {
+				saveObjects(b.context, b.context.ObjectKey(), objects, dir, nil)
+			}))
+		})
+	})
+}
+
+// copyObjectsForm asks for the destination of items and copies or moves
+// them there. A single object is copied to the destination key, everything
+// else below the destination prefix.
+func (b *ObjectsPage) copyObjectsForm(items []s3lib.Object, move bool) {
+	title, verb := "Copy Objects", "Copy"
+	if move {
+		title, verb = "Move Objects", "Move"
+	}
+
+	b.pathForm(title, "Destination", b.context.ObjectKey(), func(dstPrefix string) {
+		prefix := b.context.ObjectKey()
+		switch {
+		case strings.HasSuffix(dstPrefix, "/"):
+		case len(items) == 1 && items[0].IsFile():
+			prefix = aws.ToString(items[0].Object.Key)
+		default:
+			dstPrefix += "/"
+		}
+
+		b.copyObjects(items, prefix, dstPrefix, verb, move)
+	})
+}
+
+// renameObjectForm asks for a new name of object in the current directory
+// and moves it there. Directories are renamed with all objects below them.
+func (b *ObjectsPage) renameObjectForm(object s3lib.Object) {
+	key := aws.ToString(object.Object.Key)
+	name := strings.TrimPrefix(key, b.context.ObjectKey())
+
+	b.pathForm("Rename Object", "Name", strings.TrimSuffix(name, "/"), func(newName string) {
+		dstKey := b.context.ObjectKey() + newName
+		if object.IsDirectory() && !strings.HasSuffix(dstKey, "/") {
+			dstKey += "/"
+		}
+
+		b.copyObjects([]s3lib.Object{object}, key, dstKey, "Rename", true)
+	})
+}
+
+// copyObjects confirms and starts copying or moving items, replacing prefix
+// in their keys by dstPrefix.
+func (b *ObjectsPage) copyObjects(items []s3lib.Object, prefix, dstPrefix, verb string, move bool) {
+	if dstPrefix == prefix {
+		b.context.SetError(errors.New("destination must differ from the source"))
+		return
+	}
+	b.expandSelection(items, func(objects []s3lib.Object) {
+		modalMessage := fmt.Sprintf("%s %s to %s?", verb, objectsSummary(objects), dstPrefix)
+		b.context.Modal(ConfirmModal(modalMessage, func() {
+			copyObjects(b.context, prefix, objects, dstPrefix, move, func(err error) {
+				b.reload()
+			})
+		}))
+	})
+}
+
+// tagObjectsForm asks for tags to add to and tag keys to remove from items,
+// and changes the tags of all objects below them.
+func (b *ObjectsPage) tagObjectsForm(items []s3lib.Object) {
+	b.expandSelection(items, func(objects []s3lib.Object) {
+		b.context.Modal(func(close func()) tview.Primitive {
+			return NewModal().
+				SetTitle("Tag Objects").
+				SetText("Change the tags of " + objectsSummary(objects)).
+				AddInput().SetLabel("Add").
+				AddInput().SetLabel("Remove").
+				AddButtons([]string{"OK", "Cancel"}).
+				SetDoneFunc(func(buttonLabel string, values map[string]string) {
+					close()
+					if buttonLabel != "OK" {
+						return
+					}
+
+					add, err := parseKeyValues(values["Add"])
 					if err != nil {
 						b.context.SetError(err)
+						return
 					}
-				}
-				return nil
-			},
-		},
-		EventKey(tcell.KeyRune, 'd', 0): {
-			Title: "Delete Object",
-			Handler: func(event *tcell.EventKey) *tcell.EventKey {
-				items := b.table.GetHighlightedItems()
-				if len(items) == 0 {
-					if obj, ok := b.GetSelectedRow(); ok {
-						items = []s3lib.Object{obj}
+					remove := parseKeys(values["Remove"])
+					if len(add) == 0 && len(remove) == 0 {
+						return
 					}
-				}
+					tagObjects(b.context, selectionLabel(items), objects, add, remove, nil)
+				})
+		})
+	})
+}
 
-				if len(items) == 0 {
-					return nil
+// markObjects marks items, so they can be pasted into another directory,
+// bucket or profile.
+func (b *ObjectsPage) markObjects(items []s3lib.Object) {
+	if b.context.Clipboard() == nil {
+		return
+	}
+	b.expandSelection(items, func(objects []s3lib.Object) {
+		b.context.Clipboard().Mark(MarkedObjects{
+			Client:  b.context.S3Client(),
+			Bucket:  b.context.Bucket(),
+			Prefix:  b.context.ObjectKey(),
+			Objects: objects,
+		})
+	})
+}
+
+// pasteObjects confirms and starts copying the marked objects into the
+// current directory.
+func (b *ObjectsPage) pasteObjects() {
+	marked, ok := b.context.Clipboard().Marked()
+	if !ok {
+		b.context.SetError(errors.New("no objects marked, mark objects with y first"))
+		return
+	}
+
+	dstPrefix := b.context.ObjectKey()
+	modalMessage := fmt.Sprintf("Paste %s from %s to %s/%s?", objectsSummary(marked.Objects), markedLabel(marked), b.context.Bucket(), dstPrefix)
+	b.context.Modal(ConfirmModal(modalMessage, func() {
+		pasteObjects(b.context, marked, dstPrefix, func(err error) {
+			b.reload()
+		})
+	}))
+}
+
+// pathForm asks for a single path and calls submit with it unless the form
+// is cancelled.
+func (b *ObjectsPage) pathForm(title, label, value string, submit func(value string)) {
+	b.context.Modal(func(close func()) tview.Primitive {
+		return NewModal().
+			SetTitle(title).
+			AddInput().SetLabel(label).SetText(value).
+			AddButtons([]string{"OK", "Cancel"}).
+			SetDoneFunc(func(buttonLabel string, values map[string]string) {
+				close()
+				if buttonLabel != "OK" {
+					return
 				}
 
-				objectKey := "object"
-				if len(items) != 1 {
-					objectKey = "objects\n"
+				value := strings.TrimSpace(values[label])
+				if value == "" {
+					b.context.SetError(fmt.Errorf("%s cannot be empty", strings.ToLower(label)))
+					return
 				}
-				modalMessage := fmt.Sprintf(
-					"Are you sure you want to delete %d %s? \n%s",
-					len(items),
-					objectKey,
-					limitedItemsAsString(
-						items,
-						func(item s3lib.Object) string {
-							return aws.ToString(item.Object.Key)
-						},
-					),
-				)
-
-				b.context.Modal(ConfirmModal(modalMessage, func() {
-					for _, item := range items {
-						b.deleteObject(item)
-					}
-				}))
+				submit(value)
+			})
+	})
+}
 
-				return nil
-			},
-		},
+func (b *ObjectsPage) deleteVersionsForm(items []s3lib.Object) {
+	var versions []s3lib.Object
+	for _, item := range items {
+		if !item.IsDirectory() {
+			versions = append(versions, item)
+		}
+	}
+	if len(versions) == 0 {
+		return
 	}
-}
 
-func (b *ObjectsPage) deleteObject(object s3lib.Object) {
-	err := b.context.S3Client().DeleteObject(
-		context.Background(),
-		b.context.Bucket(),
-		aws.ToString(object.Object.Key),
+	modalMessage := fmt.Sprintf(
+		"Are you sure you want to permanently delete %d version(s)? \n%s",
+		len(versions),
+		limitedItemsAsString(versions, objectVersionString),
 	)
-	if err != nil {
-		b.context.SetError(err)
+
+	b.context.Modal(ConfirmModal(modalMessage, func() {
+		for _, version := range versions {
+			deleteObjectVersion(b.context, aws.ToString(version.Object.Key), version.VersionID, func(err error) {
+				b.reload()
+			})
+		}
+	}))
+}
+
+// editSelectedObject edits object, which replaces the current version of its
+// key when saved. Other versions, delete markers and directories cannot be
+// edited.
+func (b *ObjectsPage) editSelectedObject(object s3lib.Object) {
+	switch {
+	case !object.IsFile():
+		b.context.SetError(errors.New("only objects can be edited"))
+	case b.showVersions && !object.IsLatest:
+		b.context.SetError(errors.New("only the latest version of an object can be edited"))
+	default:
+		editObject(b.context.WithObjectKey(aws.ToString(object.Object.Key)))
 	}
-	err = b.Load()
+}
+
+func (b *ObjectsPage) restoreVersionForm(object s3lib.Object) {
+	if !b.showVersions {
+		b.context.SetError(errors.New("press V to list the versions to restore from"))
+		return
+	}
+	if !object.IsFile() {
+		b.context.SetError(errors.New("only object versions can be restored"))
+		return
+	}
+
+	modalMessage := fmt.Sprintf(
+		"Are you sure you want to restore %s as the current version?",
+		objectVersionString(object),
+	)
+
+	b.context.Modal(ConfirmModal(modalMessage, func() {
+		restoreObjectVersion(b.context, aws.ToString(object.Object.Key), object.VersionID, func(err error) {
+			b.reload()
+		})
+	}))
+}
+
+func (b *ObjectsPage) saveObjectForm(object s3lib.Object) {
+	key := aws.ToString(object.Object.Key)
+
+	b.pathForm("Save Object", "Path", path.Base(key), func(filePath string) {
+		saveObject(b.context.WithObjectKey(key), object.VersionID, filePath, nil)
+	})
+}
+
+func objectVersionString(object s3lib.Object) string {
+	return aws.ToString(object.Object.Key) + " (" + object.VersionID + ")"
+}
+
+// navigate lists the objects of c in place of the current ones.
+func (b *ObjectsPage) navigate(c Context) {
+	b.context = c
+	b.reload()
+	b.tviewTable.ScrollToBeginning().Select(1, 0)
+}
+
+func (b *ObjectsPage) reload() {
+	err := b.Load()
 	if err != nil {
 		b.context.SetError(err)
 	}
 }
 
 func (b *ObjectsPage) Load() error {
-	b.ClearRows()
-	paginator := b.context.S3Client().ListObjects(context.Background(), b.context.Bucket(), b.context.ObjectKey())
-	var objects []s3lib.Object
-	for paginator.HasMorePages() {
-		page, err := paginator.NextPage(context.Background())
-		if err != nil {
-			return err
-		}
-
-		objects = append(objects, page...)
+	var paginator s3lib.Paginator[s3lib.Object]
+	if b.archive != nil {
+		paginator = b.archive.ListObjects(b.archive.MemberName(b.context.ObjectKey()))
+	} else if b.showVersions {
+		paginator = b.context.S3Client().ListObjectVersions(context.Background(), b.context.Bucket(), b.context.ObjectKey())
+	} else {
+		paginator = b.context.S3Client().ListObjects(context.Background(), b.context.Bucket(), b.context.ObjectKey())
 	}
-
-	b.AddAll(objects)
-	return nil
+	return b.LoadPages(b.context, paginator)
 }
 
 func humanizeTime(t *time.Time) string {
@@ -274,18 +819,15 @@ func (b *ObjectsPage) createObject(values map[string]string) {
 
 	if _, err := os.Stat(tmpFilePath); os.IsNotExist(err) {
 		// File was deleted, do nothing
+		_ = os.RemoveAll(tmpDir)
 		return
 	}
 
-	err = b.context.S3Client().UploadFile(context.Background(), b.context.Bucket(), name, tmpFilePath)
-	if err != nil {
-		b.context.SetError(err)
-		return
-	}
-
-	err = b.Load()
-	if err != nil {
-		b.context.SetError(err)
-		return
-	}
+	uploadObject(b.context, name, tmpFilePath, func(err error) {
+		// a failed upload keeps its file so that it can be retried
+		if err == nil {
+			_ = os.RemoveAll(tmpDir)
+		}
+		b.reload()
+	})
 }
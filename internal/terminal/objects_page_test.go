@@ -1,9 +1,13 @@
 package terminal
 
 import (
+	"archive/zip"
+	"bytes"
+	"context"
 	"testing"
 	"time"
 
+	"github.com/gdamore/tcell/v2"
 	"github.com/schidstorm/s3tool/internal/s3lib"
 	"github.com/stretchr/testify/assert"
 )
@@ -35,6 +39,76 @@ func TestObjectsPage(t *testing.T) {
 
 }
 
+func TestObjectsPageVersions(t *testing.T) {
+	now := time.Now()
+	client := s3lib.NewMemoryClientFactory().
+		WithBucket("test-bucket", "eu-central-1", now).
+		WithVersioning("test-bucket").
+		WithObject("test-bucket", "file.txt", 2, now.Add(-2*time.Hour), "etag1", "STANDARD", []byte("v1")).
+		WithObject("test-bucket", "file.txt", 2, now.Add(-time.Hour), "etag2", "STANDARD", []byte("v2")).
+		Build()
+	page := NewObjectsPage(NewContext().WithErrorFunc(func(err error) {
+		t.Error(err)
+	}).WithClient(client).WithBucket("test-bucket"))
+
+	page.SetShowVersions(true)
+	assert.NoError(t, page.Load())
+	rows := getTableRows(page.tviewTable)
+	assert.Equal(t, []string{"Name", "Version", "Size", "Last Modified"}, rows[0])
+	assert.Equal(t, []string{"file.txt", "v2 (latest)", "2 B"}, rows[1][:3])
+	assert.Equal(t, []string{"file.txt", "v1", "2 B"}, rows[2][:3])
+
+	restoreObjectVersion(page.context, "file.txt", "v1", func(err error) {
+		assert.NoError(t, err)
+		page.reload()
+	})
+	rows = getTableRows(page.tviewTable)
+	assert.Len(t, rows, 4)
+	assert.Equal(t, []string{"file.txt", "v3 (latest)", "2 B"}, rows[1][:3])
+
+	deleteObject(page.context, "file.txt", func(err error) {
+		assert.NoError(t, err)
+		page.reload()
+	})
+	rows = getTableRows(page.tviewTable)
+	assert.Equal(t, "delete marker", rows[1][2])
+
+	deleteObjectVersion(page.context, "file.txt", "v1", func(err error) {
+		assert.NoError(t, err)
+		page.reload()
+	})
+	assert.Len(t, getTableRows(page.tviewTable), 4)
+
+	page.SetShowVersions(false)
+	assert.NoError(t, page.Load())
+	assert.Equal(t, [][]string{{"Name", "Size", "Last Modified"}}, getTableRows(page.tviewTable))
+}
+
+func TestObjectsPageSortBySize(t *testing.T) {
+	now := time.Now()
+	client := s3lib.NewMemoryClientFactory().
+		WithBucket("test-bucket", "eu-central-1", now).
+		WithObject("test-bucket", "big.txt", 2048, now, "etag1", "STANDARD", make([]byte, 2048)).
+		WithObject("test-bucket", "small.txt", 1, now, "etag2", "STANDARD", []byte("a")).
+		WithObject("test-bucket", "huge.txt", 1<<20, now, "etag3", "STANDARD", make([]byte, 1<<20)).
+		Build()
+	page := NewObjectsPage(NewContext().WithErrorFunc(func(err error) {
+		t.Error(err)
+	}).WithClient(client).WithBucket("test-bucket"))
+	assert.NoError(t, page.Load())
+
+	page.table.SetSort("Size", true)
+	assert.NoError(t, page.Load())
+	rows := getTableRows(page.tviewTable)
+	assert.Equal(t, "Size ▼", rows[0][1])
+	assert.Equal(t, []string{"huge.txt", "big.txt", "small.txt"}, []string{rows[1][0], rows[2][0], rows[3][0]})
+
+	// the sort column is kept when the version column is added
+	page.SetShowVersions(true)
+	assert.NoError(t, page.Load())
+	assert.Equal(t, "Size ▼", getTableRows(page.tviewTable)[0][2])
+}
+
 func TestHumanizeSize(t *testing.T) {
 	tests := []struct {
 		size     *int64
@@ -58,3 +132,110 @@ func TestHumanizeSize(t *testing.T) {
 func int64Ptr(i int64) *int64 {
 	return &i
 }
+
+func TestObjectsPageArchive(t *testing.T) {
+	var buffer bytes.Buffer
+	w := zip.NewWriter(&buffer)
+	for _, name := range []string{"docs/a.txt", "b.txt"} {
+		f, err := w.Create(name)
+		assert.NoError(t, err)
+		_, err = f.Write([]byte("content of " + name))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, w.Close())
+
+	client := s3lib.NewMemoryClientFactory().
+		WithBucket("bucket", "eu-central-1", time.Now()).
+		WithObject("bucket", "bundle.zip", int64(buffer.Len()), time.Now(), "etag", "STANDARD", buffer.Bytes()).
+		Build()
+
+	var opened PageContent
+	c := NewContext().WithErrorFunc(func(err error) {
+		t.Error(err)
+	}).WithClient(client).WithBucket("bucket").WithOpenPageFunc(func(page PageContent) {
+		opened = page
+	})
+	page := NewObjectsPage(c)
+	assert.NoError(t, page.Load())
+	page.tviewTable.Select(1, 0)
+	pressHotkey(t, page, tcell.NewEventKey(tcell.KeyRune, 'x', 0))
+
+	archivePage, ok := opened.(*ObjectsPage)
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.Equal(t, "Objects - bundle.zip/", archivePage.Title())
+	assert.NoError(t, archivePage.Load())
+	rows := getTableRows(archivePage.tviewTable)
+	assert.Equal(t, []string{"docs/", "b.txt"}, []string{rows[1][0], rows[2][0]})
+	_, editable := findHotkey(archivePage.Hotkeys(), tcell.NewEventKey(tcell.KeyRune, 'e', 0))
+	assert.False(t, editable)
+
+	archivePage.tviewTable.Select(2, 0)
+	pressHotkey(t, archivePage, tcell.NewEventKey(tcell.KeyRune, 'P', 0))
+	assert.Equal(t, "content of b.txt", archivePage.preview.GetText(true))
+
+	archivePage.tviewTable.Select(1, 0)
+	archivePage.tviewTable.InputHandler()(tcell.NewEventKey(tcell.KeyEnter, 0, 0), nil)
+	docsPage, ok := opened.(*ObjectsPage)
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.NoError(t, docsPage.Load())
+	assert.Equal(t, "a.txt", getTableRows(docsPage.tviewTable)[1][0])
+
+	// with a UI the archive is opened in the background
+	opened = nil
+	queued := make(chan func(), 1)
+	page = NewObjectsPage(c.WithQueueUpdateFunc(func(f func()) { queued <- f }))
+	assert.NoError(t, page.Load())
+	page.tviewTable.Select(1, 0)
+	pressHotkey(t, page, tcell.NewEventKey(tcell.KeyRune, 'x', 0))
+	assert.Equal(t, "Opening archive…", page.status.GetText(true))
+	assert.Nil(t, opened)
+	(<-queued)()
+	assert.IsType(t, &ObjectsPage{}, opened)
+	assert.Empty(t, page.status.GetText(true))
+}
+
+func TestObjectsPageEditOnlyLatestVersion(t *testing.T) {
+	useEditor(t, `sh -c 'echo mine >> "$1"' sh`)
+	now := time.Now()
+	client := s3lib.NewMemoryClientFactory().
+		WithBucket("test-bucket", "eu-central-1", now).
+		WithVersioning("test-bucket").
+		WithObject("test-bucket", "dir/file.txt", 2, now.Add(-2*time.Hour), "etag1", "STANDARD", []byte("v1")).
+		WithObject("test-bucket", "dir/file.txt", 2, now.Add(-time.Hour), "etag2", "STANDARD", []byte("v2")).
+		Build()
+	var errs []string
+	c := NewContext().
+		WithErrorFunc(func(err error) { errs = append(errs, err.Error()) }).
+		WithSuspendAppFunc(func(f func()) bool { f(); return true }).
+		WithClient(client).
+		WithBucket("test-bucket")
+	read := func() string {
+		data, _, err := client.ReadObjectRange(context.Background(), "test-bucket", "dir/file.txt", "", 0, 1024)
+		assert.NoError(t, err)
+		return string(data)
+	}
+
+	page := NewObjectsPage(c)
+	assert.NoError(t, page.Load())
+	// the only row is the directory
+	page.tviewTable.Select(1, 0)
+	pressHotkey(t, page, tcell.NewEventKey(tcell.KeyRune, 'e', 0))
+	assert.Equal(t, []string{"only objects can be edited"}, errs)
+
+	page = NewObjectsPage(c.WithObjectKey("dir/"))
+	page.SetShowVersions(true)
+	assert.NoError(t, page.Load())
+	page.tviewTable.Select(2, 0)
+	pressHotkey(t, page, tcell.NewEventKey(tcell.KeyRune, 'e', 0))
+	assert.Equal(t, "only the latest version of an object can be edited", errs[len(errs)-1])
+	assert.Equal(t, "v2", read())
+
+	page.tviewTable.Select(1, 0)
+	pressHotkey(t, page, tcell.NewEventKey(tcell.KeyRune, 'e', 0))
+	assert.Len(t, errs, 2)
+	assert.Equal(t, "v2mine\n", read())
+}
@@ -15,6 +15,15 @@ func EventKey(k tcell.Key, ch rune, mod tcell.ModMask) tcell.EventKey {
 	return *ek
 }
 
+func findHotkey(hotkeys map[tcell.EventKey]Hotkey, event *tcell.EventKey) (Hotkey, bool) {
+	for hkKey, hk := range hotkeys {
+		if event.Key() == hkKey.Key() && event.Rune() == hkKey.Rune() && event.Modifiers() == hkKey.Modifiers() {
+			return hk, true
+		}
+	}
+	return Hotkey{}, false
+}
+
 type PageContent interface {
 	tview.Primitive
 
@@ -25,6 +34,12 @@ type PageContent interface {
 	Load() error
 }
 
+// closer is implemented by page contents that stop background work, like
+// fetching more rows, once their page is closed.
+type closer interface {
+	Close()
+}
+
 type Page struct {
 	*tview.Flex
 
@@ -33,6 +48,7 @@ type Page struct {
 	isSearchActive bool
 	searchTerm     string
 	closeHandler   func()
+	keys           keyDispatcher
 }
 
 func NewPage(content PageContent) *Page {
@@ -50,25 +66,15 @@ func NewPage(content PageContent) *Page {
 		searchFlex: searchFlex,
 	}
 
-	hotkeys := content.Hotkeys()
+	p.keys.bindings = ActiveKeymap.pageBindings(p, content.Hotkeys())
 	contentFlex.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
-		switch event.Key() {
-		case tcell.KeyEscape:
-			p.handleClose()
-			return nil
-		case tcell.KeyRune:
-			if event.Rune() == '/' {
-				p.activateSearch()
-				return nil
-			}
+		hotkey, ok, waiting := p.keys.dispatch(event)
+		if ok {
+			return hotkey.Handler(event)
 		}
-
-		for hkKey, hk := range hotkeys {
-			if event.Key() == hkKey.Key() && event.Rune() == hkKey.Rune() && event.Modifiers() == hkKey.Modifiers() {
-				return hk.Handler(event)
-			}
+		if waiting {
+			return nil
 		}
-
 		return event
 	})
 
@@ -99,6 +105,46 @@ func NewPage(content PageContent) *Page {
 	return p
 }
 
+// hotkeyKeys are the default keys of the hotkeys of a kind of page by their
+// titles. They are declared statically, so the keymap knows the hotkeys of
+// every page without creating one.
+type hotkeyKeys map[string]tcell.EventKey
+
+// bind returns the hotkeys of keys with the handlers of their titles.
+func (keys hotkeyKeys) bind(handlers map[string]func(*tcell.EventKey) *tcell.EventKey) map[tcell.EventKey]Hotkey {
+	hotkeys := map[tcell.EventKey]Hotkey{}
+	for title, key := range keys {
+		hotkeys[key] = Hotkey{Title: title, Handler: handlers[title]}
+	}
+	return hotkeys
+}
+
+// hotkeys returns the hotkeys of keys without handlers.
+func (keys hotkeyKeys) hotkeys() map[tcell.EventKey]Hotkey {
+	return keys.bind(nil)
+}
+
+// pageHotkeyKeys are the keys of the hotkeys of Page.
+var pageHotkeyKeys = hotkeyKeys{
+	"Back":   EventKey(tcell.KeyEscape, 0, tcell.ModNone),
+	"Search": EventKey(tcell.KeyRune, '/', tcell.ModNone),
+}
+
+// hotkeys returns the hotkeys that every page has besides the ones of its
+// content.
+func (p *Page) hotkeys() map[tcell.EventKey]Hotkey {
+	return pageHotkeyKeys.bind(map[string]func(*tcell.EventKey) *tcell.EventKey{
+		"Back": func(event *tcell.EventKey) *tcell.EventKey {
+			p.handleClose()
+			return nil
+		},
+		"Search": func(event *tcell.EventKey) *tcell.EventKey {
+			p.activateSearch()
+			return nil
+		},
+	})
+}
+
 // Modal flex -> Search Flex -> Content
 
 // modes: modal mode, search mode, search term not empty mode
@@ -148,6 +194,13 @@ func (p *Page) SetCloseHandler(handler func()) {
 	p.closeHandler = handler
 }
 
+// closeContent stops the background work of the content.
+func (p *Page) closeContent() {
+	if c, ok := p.content.(closer); ok {
+		c.Close()
+	}
+}
+
 func (p *Page) handleClose() {
 	if p.closeHandler != nil {
 		p.closeHandler()
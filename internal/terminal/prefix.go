@@ -0,0 +1,148 @@
+package terminal
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/schidstorm/s3tool/internal/s3lib"
+)
+
+// expandObjects returns the files among items together with all objects below
+// the directories among items.
+func expandObjects(ctx context.Context, c Context, items []s3lib.Object) ([]s3lib.Object, error) {
+	var objects []s3lib.Object
+	for _, item := range items {
+		if !item.IsDirectory() {
+			objects = append(objects, item)
+			continue
+		}
+
+		paginator := c.S3Client().ListObjectsRecursive(ctx, c.Bucket(), aws.ToString(item.Object.Key))
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, err
+			}
+			objects = append(objects, page...)
+		}
+	}
+	return objects, nil
+}
+
+// objectsSize returns the total size of objects.
+func objectsSize(objects []s3lib.Object) int64 {
+	var size int64
+	for _, object := range objects {
+		size += aws.ToInt64(object.Object.Size)
+	}
+	return size
+}
+
+// objectsSummary describes the number and total size of objects.
+func objectsSummary(objects []s3lib.Object) string {
+	size := objectsSize(objects)
+	noun := "objects"
+	if len(objects) == 1 {
+		noun = "object"
+	}
+	return fmt.Sprintf("%d %s (%s)", len(objects), noun, humanizeSize(&size))
+}
+
+func objectKeys(objects []s3lib.Object) []string {
+	keys := make([]string, 0, len(objects))
+	for _, object := range objects {
+		keys = append(keys, aws.ToString(object.Object.Key))
+	}
+	return keys
+}
+
+// deleteObjects deletes objects from the bucket of c in the background. label
+// names the deleted objects in the transfers page.
+func deleteObjects(c Context, label string, objects []s3lib.Object, done func(err error)) {
+	client, bucket, keys := c.S3Client(), c.Bucket(), objectKeys(objects)
+
+	c.Transfers().Submit(NewTransfer(TransferDelete, bucket, label,
+		func(ctx context.Context, progress func(transferred, total int64)) error {
+			return client.DeleteObjects(ctx, bucket, keys)
+		},
+		transferDone(c, done),
+	))
+}
+
+// saveObjects downloads objects into dir in the background. The local paths
+// are the keys relative to prefix, so that the directory structure below
+// prefix is kept.
+func saveObjects(c Context, prefix string, objects []s3lib.Object, dir string, done func(err error)) {
+	client, bucket, total := c.S3Client(), c.Bucket(), objectsSize(objects)
+
+	c.Transfers().Submit(NewTransfer(TransferDownload, bucket, prefix,
+		func(ctx context.Context, progress func(transferred, total int64)) error {
+			return forEachObject(objects, total, progress, func(key string, objectProgress func(int64)) error {
+				relativePath := filepath.FromSlash(strings.TrimPrefix(key, prefix))
+				if !filepath.IsLocal(relativePath) {
+					return fmt.Errorf("cannot save %s outside of %s", key, dir)
+				}
+
+				filePath := filepath.Join(dir, relativePath)
+				if strings.HasSuffix(key, "/") {
+					// directory placeholder objects become empty directories
+					return os.MkdirAll(filePath, 0755)
+				}
+				return client.DownloadFile(ctx, bucket, key, filePath, partOptions(s3lib.WithProgress(func(transferred, _ int64) {
+					objectProgress(transferred)
+				}))...)
+			})
+		},
+		transferDone(c, done),
+	))
+}
+
+// copyObjects copies objects to dstPrefix on the server side in the
+// background, replacing prefix in their keys by dstPrefix. If move is set the
+// objects are deleted once all of them have been copied.
+func copyObjects(c Context, prefix string, objects []s3lib.Object, dstPrefix string, move bool, done func(err error)) {
+	client, bucket, total := c.S3Client(), c.Bucket(), objectsSize(objects)
+
+	kind := TransferCopy
+	if move {
+		kind = TransferMove
+	}
+
+	c.Transfers().Submit(NewTransfer(kind, bucket, prefix,
+		func(ctx context.Context, progress func(transferred, total int64)) error {
+			err := forEachObject(objects, total, progress, func(key string, objectProgress func(int64)) error {
+				dstKey := dstPrefix + strings.TrimPrefix(key, prefix)
+				return client.CopyObject(ctx, bucket, key, bucket, dstKey, partOptions(s3lib.WithProgress(func(transferred, _ int64) {
+					objectProgress(transferred)
+				}))...)
+			})
+			if err != nil || !move {
+				return err
+			}
+			return client.DeleteObjects(ctx, bucket, objectKeys(objects))
+		},
+		transferDone(c, done),
+	))
+}
+
+// forEachObject calls f for every object and reports the progress of all
+// objects together. f reports the bytes of its object transferred so far.
+func forEachObject(objects []s3lib.Object, total int64, progress func(transferred, total int64), f func(key string, objectProgress func(int64)) error) error {
+	var finished int64
+	for _, object := range objects {
+		err := f(aws.ToString(object.Object.Key), func(transferred int64) {
+			progress(finished+transferred, total)
+		})
+		if err != nil {
+			return err
+		}
+
+		finished += aws.ToInt64(object.Object.Size)
+		progress(finished, total)
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package terminal
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/schidstorm/s3tool/internal/s3lib"
+	"github.com/stretchr/testify/assert"
+)
+
+func prefixTestContext(t *testing.T) Context {
+	now := time.Now()
+	client := s3lib.NewMemoryClientFactory().
+		WithBucket("bucket", "eu-central-1", now).
+		WithObject("bucket", "photos/2024/a.jpg", 1, now, "e1", "STANDARD", []byte("a")).
+		WithObject("bucket", "photos/2024/b.jpg", 2, now, "e2", "STANDARD", []byte("bb")).
+		WithObject("bucket", "photos/root.txt", 4, now, "e3", "STANDARD", []byte("root")).
+		WithObject("bucket", "other.txt", 5, now, "e4", "STANDARD", []byte("other")).
+		Build()
+
+	return NewContext().
+		WithClient(client).
+		WithBucket("bucket").
+		WithErrorFunc(func(err error) { t.Error(err) })
+}
+
+func listKeys(t *testing.T, c Context, prefix string) []string {
+	t.Helper()
+	objects, err := c.S3Client().ListObjectsRecursive(context.Background(), c.Bucket(), prefix).NextPage(context.Background())
+	assert.NoError(t, err)
+	return objectKeys(objects)
+}
+
+func TestExpandObjects(t *testing.T) {
+	c := prefixTestContext(t)
+
+	objects, err := expandObjects(context.Background(), c, []s3lib.Object{
+		s3lib.NewObjectDirectory("photos/"),
+		s3lib.NewObjectFile(types.Object{Key: aws.String("other.txt")}),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"photos/2024/a.jpg", "photos/2024/b.jpg", "photos/root.txt", "other.txt"}, objectKeys(objects))
+	assert.Equal(t, "3 objects (7 B)", objectsSummary(objects[:3]))
+}
+
+func TestDeleteObjectsRecursive(t *testing.T) {
+	c := prefixTestContext(t)
+
+	objects, err := expandObjects(context.Background(), c, []s3lib.Object{s3lib.NewObjectDirectory("photos/")})
+	assert.NoError(t, err)
+
+	called := false
+	deleteObjects(c, "photos/", objects, func(err error) {
+		called = true
+		assert.NoError(t, err)
+	})
+	assert.True(t, called)
+	assert.Equal(t, []string{"other.txt"}, listKeys(t, c, ""))
+}
+
+func TestSaveObjectsKeepsDirectoryStructure(t *testing.T) {
+	c := prefixTestContext(t)
+	dir := t.TempDir()
+
+	objects, err := expandObjects(context.Background(), c, []s3lib.Object{s3lib.NewObjectDirectory("photos/2024/")})
+	assert.NoError(t, err)
+
+	saveObjects(c, "photos/", objects, dir, func(err error) {
+		assert.NoError(t, err)
+	})
+
+	got, err := os.ReadFile(filepath.Join(dir, "2024", "b.jpg"))
+	assert.NoError(t, err)
+	assert.Equal(t, "bb", string(got))
+}
+
+func TestSaveObjectsRejectsKeysOutsideDirectory(t *testing.T) {
+	c := prefixTestContext(t)
+	var saveErr error
+	c = c.WithErrorFunc(func(err error) { saveErr = err })
+
+	object := s3lib.NewObjectFile(types.Object{Key: aws.String("photos/../../escape.txt")})
+	saveObjects(c, "photos/", []s3lib.Object{object}, t.TempDir(), nil)
+
+	assert.ErrorContains(t, saveErr, "outside of")
+}
+
+func TestCopyObjectsRecursive(t *testing.T) {
+	c := prefixTestContext(t)
+
+	objects, err := expandObjects(context.Background(), c, []s3lib.Object{s3lib.NewObjectDirectory("photos/")})
+	assert.NoError(t, err)
+
+	transfers := NewTransferManager(1)
+	done := make(chan error, 1)
+	copyObjects(c.WithTransfers(transfers), "", objects, "backup/", false, func(err error) { done <- err })
+	assert.NoError(t, <-done)
+
+	transfer := transfers.Transfers()[0]
+	snapshot := transfer.Snapshot()
+	assert.Equal(t, TransferCopy, transfer.Kind)
+	assert.Equal(t, int64(7), snapshot.Transferred)
+
+	assert.Equal(t, []string{
+		"backup/photos/2024/a.jpg",
+		"backup/photos/2024/b.jpg",
+		"backup/photos/root.txt",
+	}, listKeys(t, c, "backup/"))
+	assert.Len(t, listKeys(t, c, "photos/"), 3)
+}
+
+func TestMoveObjectsRenamesPrefix(t *testing.T) {
+	c := prefixTestContext(t)
+
+	objects, err := expandObjects(context.Background(), c, []s3lib.Object{s3lib.NewObjectDirectory("photos/2024/")})
+	assert.NoError(t, err)
+
+	transfers := NewTransferManager(1)
+	done := make(chan error, 1)
+	copyObjects(c.WithTransfers(transfers), "photos/2024/", objects, "photos/2025/", true, func(err error) { done <- err })
+	assert.NoError(t, <-done)
+
+	assert.Equal(t, TransferMove, transfers.Transfers()[0].Kind)
+	assert.Equal(t, []string{
+		"photos/2025/a.jpg",
+		"photos/2025/b.jpg",
+		"photos/root.txt",
+	}, listKeys(t, c, "photos/"))
+
+	metadata, err := c.S3Client().GetObject(context.Background(), "bucket", "photos/2025/b.jpg")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"Environment": "Test"}, metadata.Tags)
+}
+
+func TestExpandSelectionInBackground(t *testing.T) {
+	queued := make(chan func(), 1)
+	c := prefixTestContext(t).WithQueueUpdateFunc(func(f func()) { queued <- f })
+	page := NewObjectsPage(c)
+
+	var expanded []string
+	page.expandSelection([]s3lib.Object{s3lib.NewObjectDirectory("photos/")}, func(objects []s3lib.Object) {
+		expanded = objectKeys(objects)
+	})
+	assert.Equal(t, "Listing objects…", page.status.GetText(true))
+	assert.Nil(t, expanded)
+
+	(<-queued)()
+	assert.Equal(t, []string{"photos/2024/a.jpg", "photos/2024/b.jpg", "photos/root.txt"}, expanded)
+	assert.Empty(t, page.status.GetText(true))
+
+	// closing the page drops the objects of a running expansion
+	expanded = nil
+	page.expandSelection([]s3lib.Object{s3lib.NewObjectDirectory("photos/")}, func(objects []s3lib.Object) {
+		expanded = objectKeys(objects)
+	})
+	page.Close()
+	(<-queued)()
+	assert.Nil(t, expanded)
+}
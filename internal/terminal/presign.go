@@ -0,0 +1,75 @@
+package terminal
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/rivo/tview"
+	"github.com/schidstorm/s3tool/internal/s3lib"
+)
+
+// presignObjectForm asks for the validity of a presigned URL of the object of
+// c, then shows the URL and sends it to the clipboard of the terminal. A
+// versionID presigns downloads of that version.
+func presignObjectForm(c Context, versionID string) {
+	text := c.Bucket() + "/" + c.ObjectKey()
+	buttons := []string{"Download URL", "Upload URL", "Cancel"}
+	if versionID != "" {
+		text += " (version " + versionID + ")"
+		buttons = []string{"Download URL", "Cancel"}
+	}
+
+	c.Modal(func(close func()) tview.Primitive {
+		return NewModal().
+			SetTitle("Presign URL").
+			SetText(text).
+			AddInput().SetLabel("Expires").SetText("1h").
+			AddButtons(buttons).
+			SetDoneFunc(func(buttonLabel string, values map[string]string) {
+				close()
+
+				method := s3lib.PresignGet
+				switch buttonLabel {
+				case "Download URL":
+				case "Upload URL":
+					method = s3lib.PresignPut
+				default:
+					return
+				}
+
+				expires, err := time.ParseDuration(strings.TrimSpace(values["Expires"]))
+				if err != nil {
+					c.SetError(err)
+					return
+				}
+				presignObject(c, versionID, method, expires)
+			})
+	})
+}
+
+func presignObject(c Context, versionID string, method s3lib.PresignMethod, expires time.Duration) {
+	url, err := c.S3Client().PresignObject(context.Background(), c.Bucket(), c.ObjectKey(), versionID, method, expires)
+	if err != nil {
+		c.SetError(err)
+		return
+	}
+
+	// the URL is shown in a field to select it from, as the terminal may
+	// ignore the clipboard
+	text := "Valid until " + time.Now().Add(expires).Format(time.DateTime)
+	if c.SetSystemClipboard(url) {
+		text += ", sent to the terminal clipboard"
+	}
+	text += "."
+	c.Modal(func(close func()) tview.Primitive {
+		return NewModal().
+			SetTitle("Presigned URL").
+			SetText(text).
+			AddInput().SetLabel("URL").SetText(url).
+			AddButtons([]string{"OK"}).
+			SetDoneFunc(func(buttonLabel string, values map[string]string) {
+				close()
+			})
+	})
+}
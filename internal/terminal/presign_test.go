@@ -0,0 +1,90 @@
+package terminal
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rivo/tview"
+	"github.com/schidstorm/s3tool/internal/s3lib"
+	"github.com/stretchr/testify/assert"
+)
+
+func presignTestContext(t *testing.T, button, expires string) (c Context, copied *string, shown *string, errs *[]error) {
+	t.Helper()
+
+	client := s3lib.NewMemoryClientFactory().
+		WithBucket("bucket", "eu-central-1", time.Now()).
+		WithVersioning("bucket").
+		WithObject("bucket", "a.txt", 1, time.Now(), "etag", "STANDARD", []byte("a")).
+		WithObject("bucket", "a.txt", 1, time.Now(), "etag2", "STANDARD", []byte("b")).
+		Build()
+
+	copied, shown, errs = new(string), new(string), new([]error)
+	c = NewContext().
+		WithClient(client).
+		WithBucket("bucket").
+		WithObjectKey("a.txt").
+		WithErrorFunc(func(err error) { *errs = append(*errs, err) }).
+		WithSystemClipboardFunc(func(text string) bool {
+			*copied = text
+			return true
+		}).
+		WithModalFunc(func(build ModalBuilder) {
+			modal := build(func() {}).(*Modal)
+			if modal.title == "Presign URL" {
+				var buttons []string
+				for i := range modal.form.GetButtonCount() {
+					buttons = append(buttons, modal.form.GetButton(i).GetLabel())
+				}
+				*shown = strings.Join(buttons, ",")
+				modal.done(button, map[string]string{"Expires": expires})
+			} else {
+				url := modal.form.GetFormItemByLabel("URL").(*tview.InputField).GetText()
+				*shown = url + "\n" + modal.text
+			}
+		})
+	return c, copied, shown, errs
+}
+
+func TestPresignObjectForm(t *testing.T) {
+	c, copied, shown, errs := presignTestContext(t, "Upload URL", "30m")
+	presignObjectForm(c, "")
+	assert.Empty(t, *errs)
+	assert.Equal(t, "memory://bucket/a.txt?Method=PUT&X-Amz-Expires=1800", *copied)
+	assert.Contains(t, *shown, *copied)
+	assert.Contains(t, *shown, "sent to the terminal clipboard")
+
+	c, copied, shown, errs = presignTestContext(t, "Download URL", "1h")
+	presignObjectForm(c, "v1")
+	assert.Empty(t, *errs)
+	assert.Equal(t, "memory://bucket/a.txt?Method=GET&X-Amz-Expires=3600&versionId=v1", *copied)
+
+	c, copied, shown, _ = presignTestContext(t, "Cancel", "1h")
+	presignObjectForm(c, "v1")
+	assert.Equal(t, "Download URL,Cancel", *shown)
+	assert.Empty(t, *copied)
+
+	// without a terminal the URL is only shown
+	c, _, shown, errs = presignTestContext(t, "Download URL", "1h")
+	presignObjectForm(c.WithSystemClipboardFunc(func(text string) bool { return false }), "")
+	assert.Empty(t, *errs)
+	assert.Contains(t, *shown, "memory://bucket/a.txt?Method=GET")
+	assert.NotContains(t, *shown, "clipboard")
+
+	c, copied, _, errs = presignTestContext(t, "Download URL", "soon")
+	presignObjectForm(c, "")
+	assert.Len(t, *errs, 1)
+	assert.Empty(t, *copied)
+
+	c, copied, _, errs = presignTestContext(t, "Download URL", "200h")
+	presignObjectForm(c, "")
+	assert.Len(t, *errs, 1)
+	assert.Empty(t, *copied)
+
+	c, copied, shown, errs = presignTestContext(t, "Cancel", "1h")
+	presignObjectForm(c, "")
+	assert.Empty(t, *errs)
+	assert.Empty(t, *copied)
+	assert.Equal(t, "Download URL,Upload URL,Cancel", *shown)
+}
@@ -0,0 +1,137 @@
+package terminal
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/rivo/tview"
+	"github.com/schidstorm/s3tool/internal/s3lib"
+)
+
+const (
+	// previewBytes is the size of the start of an object that is previewed.
+	previewBytes = 64 << 10
+	// imagePreviewBytes is larger, since images can only be shown whole.
+	imagePreviewBytes = 4 << 20
+)
+
+// PreviewPane shows the start of an object rendered by its type. Objects are
+// read in the background if the context can queue updates of the UI.
+type PreviewPane struct {
+	*tview.TextView
+
+	cancel context.CancelFunc
+	// shown identifies the object version that is shown
+	shown string
+}
+
+func NewPreviewPane() *PreviewPane {
+	textView := tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(false)
+	textView.SetBorder(true).
+		SetBorderColor(DefaultTheme.BorderColor).
+		SetTitleAlign(tview.AlignLeft).
+		SetTitle(" Preview ")
+
+	return &PreviewPane{
+		TextView: textView,
+	}
+}
+
+// Show previews obj of the bucket of c. Directories and delete markers
+// clear the pane.
+func (p *PreviewPane) Show(c Context, obj s3lib.Object) {
+	key := aws.ToString(obj.Object.Key)
+	client, bucket, versionID := c.S3Client(), c.Bucket(), obj.VersionID
+	p.show(c, obj, bucket+"/"+key+"@"+versionID+aws.ToString(obj.Object.ETag), func(ctx context.Context, limit int64) ([]byte, int64, error) {
+		return client.ReadObjectRange(ctx, bucket, key, versionID, 0, limit)
+	})
+}
+
+// ShowMember previews the member obj of archive.
+func (p *PreviewPane) ShowMember(c Context, archive *s3lib.Archive, obj s3lib.Object) {
+	name := archive.MemberName(aws.ToString(obj.Object.Key))
+	p.show(c, obj, c.Bucket()+"/"+aws.ToString(obj.Object.Key), func(ctx context.Context, limit int64) ([]byte, int64, error) {
+		return archive.ReadMember(ctx, name, limit)
+	})
+}
+
+// show previews obj with the data returned by read, unless the object
+// identified by shown is already shown.
+func (p *PreviewPane) show(c Context, obj s3lib.Object, shown string, read func(ctx context.Context, limit int64) ([]byte, int64, error)) {
+	key := aws.ToString(obj.Object.Key)
+	if shown == p.shown {
+		return
+	}
+	p.Close()
+	p.shown = shown
+
+	p.SetTitle(" Preview - " + path.Base(key) + " ")
+	p.ScrollToBeginning()
+	if !obj.IsFile() || obj.IsDeleteMarker() {
+		p.SetText("")
+		return
+	}
+
+	limit := int64(previewBytes)
+	if isImageKey(key) {
+		limit = imagePreviewBytes
+	}
+	_, _, width, height := p.GetInnerRect()
+	if width <= 0 || height <= 0 {
+		width, height = 40, 20
+	}
+
+	render := func(ctx context.Context) string {
+		data, size, err := read(ctx, limit)
+		if err != nil {
+			return colored(DefaultTheme.ErrorColor, err.Error())
+		}
+
+		truncated := int64(len(data)) < size
+		text := renderPreview(key, data, truncated, width, height)
+		if truncated {
+			text += "\n" + colored(DefaultTheme.SecondaryColor, fmt.Sprintf("… first %s of %s", humanizeSize(aws.Int64(int64(len(data)))), humanizeSize(&size)))
+		}
+		return text
+	}
+
+	queueUpdate := c.QueueUpdateFunc()
+	if queueUpdate == nil {
+		p.SetText(render(context.Background()))
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	p.SetText(colored(DefaultTheme.SecondaryColor, "Loading…"))
+	go func() {
+		text := render(ctx)
+		queueUpdate(func() {
+			if ctx.Err() != nil {
+				// another object was selected in the meantime
+				return
+			}
+			p.SetText(text)
+		})
+	}()
+}
+
+// Clear empties the pane.
+func (p *PreviewPane) Clear() {
+	p.Close()
+	p.shown = ""
+	p.SetTitle(" Preview ")
+	p.SetText("")
+}
+
+// Close stops reading the object that is previewed.
+func (p *PreviewPane) Close() {
+	if p.cancel != nil {
+		p.cancel()
+		p.cancel = nil
+	}
+}
@@ -0,0 +1,428 @@
+package terminal
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// hexdumpBytes limits the bytes shown by hexdumps.
+	hexdumpBytes = 4 << 10
+	// csvPreviewRows and csvColumnWidth limit the CSV tables.
+	csvPreviewRows = 200
+	csvColumnWidth = 30
+)
+
+var imageExtensions = []string{".png", ".jpg", ".jpeg", ".gif"}
+
+// isImageKey reports whether key is an image that can be shown as a
+// thumbnail.
+func isImageKey(key string) bool {
+	ext := strings.ToLower(path.Ext(key))
+	for _, imageExt := range imageExtensions {
+		if ext == imageExt {
+			return true
+		}
+	}
+	return false
+}
+
+// renderPreview renders the first bytes of the object key as text with
+// color tags. truncated tells whether data is only the start of the object.
+// Images are scaled to fit into width x height cells.
+func renderPreview(key string, data []byte, truncated bool, width, height int) string {
+	ext := strings.ToLower(path.Ext(key))
+
+	if isImageKey(key) || strings.HasPrefix(http.DetectContentType(data), "image/") {
+		if DefaultTheme.NoColor {
+			return "images are not previewed without colors"
+		}
+		img, _, err := image.Decode(bytes.NewReader(data))
+		if err == nil {
+			return renderImage(img, width, height)
+		}
+		if truncated {
+			return colored(DefaultTheme.SecondaryColor, "image is too large to preview")
+		}
+	}
+
+	if isBinary(data, truncated) {
+		return renderHexdump(data)
+	}
+
+	switch ext {
+	case ".json":
+		if text, ok := renderJSON(data); ok {
+			return text
+		}
+	case ".yaml", ".yml":
+		if text, ok := renderYAML(data, truncated); ok {
+			return text
+		}
+	case ".csv":
+		return renderCSV(data, ',', truncated)
+	case ".tsv":
+		return renderCSV(data, '\t', truncated)
+	case "":
+		// JSON without extension, like API responses
+		if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+			if text, ok := renderJSON(data); ok {
+				return text
+			}
+		}
+	}
+
+	return highlight(string(data), languageOf(ext))
+}
+
+// isBinary reports whether data is not UTF-8 text. A rune cut off at the end
+// of truncated data does not count.
+func isBinary(data []byte, truncated bool) bool {
+	if bytes.IndexByte(data, 0) >= 0 {
+		return true
+	}
+	if truncated {
+		for i := 0; i < utf8.UTFMax && len(data) > 0 && !utf8.Valid(data); i++ {
+			data = data[:len(data)-1]
+		}
+	}
+	return !utf8.Valid(data)
+}
+
+func renderHexdump(data []byte) string {
+	_, data = limitSlice(data, hexdumpBytes)
+	return tview.Escape(hex.Dump(data))
+}
+
+func renderJSON(data []byte) (string, bool) {
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, bytes.TrimSpace(data), "", "  "); err != nil {
+		return "", false
+	}
+	return highlight(indented.String(), languages["json"]), true
+}
+
+// renderYAML normalizes the indentation of a YAML document. Truncated
+// documents are shown as they are, since their last value may be cut off.
+func renderYAML(data []byte, truncated bool) (string, bool) {
+	if truncated {
+		return highlight(string(data), languages["yaml"]), true
+	}
+
+	var out bytes.Buffer
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	for {
+		var node yaml.Node
+		err := decoder.Decode(&node)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", false
+		}
+		if err := encoder.Encode(&node); err != nil {
+			return "", false
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		return "", false
+	}
+	return highlight(out.String(), languages["yaml"]), true
+}
+
+// renderCSV shows comma or tab separated values as a table with aligned
+// columns and the first row as header.
+func renderCSV(data []byte, separator rune, truncated bool) string {
+	if truncated {
+		// the last line is most likely cut off
+		if i := bytes.LastIndexByte(data, '\n'); i >= 0 {
+			data = data[:i+1]
+		}
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = separator
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	var records [][]string
+	for len(records) < csvPreviewRows {
+		record, err := reader.Read()
+		if err != nil {
+			break
+		}
+		records = append(records, record)
+	}
+
+	var widths []int
+	for _, record := range records {
+		for i, field := range record {
+			if i == len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], min(utf8.RuneCountInString(field), csvColumnWidth))
+		}
+	}
+
+	var out strings.Builder
+	for rowIndex, record := range records {
+		var line strings.Builder
+		for i, field := range record {
+			field = truncateRunes(field, csvColumnWidth)
+			line.WriteString(field)
+			if i < len(record)-1 {
+				line.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(field)+2))
+			}
+		}
+		if rowIndex == 0 {
+			out.WriteString(colored(DefaultTheme.LabelColor, line.String()))
+		} else {
+			out.WriteString(tview.Escape(line.String()))
+		}
+		out.WriteString("\n")
+	}
+	return out.String()
+}
+
+func truncateRunes(s string, limit int) string {
+	if utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+	return string([]rune(s)[:limit-1]) + "…"
+}
+
+// renderImage draws img with half-block characters, two pixels per cell,
+// scaled down to fit into width x height cells.
+func renderImage(img image.Image, width, height int) string {
+	bounds := img.Bounds()
+	if bounds.Empty() || width <= 0 || height <= 0 {
+		return ""
+	}
+
+	scale := min(float64(width)/float64(bounds.Dx()), float64(2*height)/float64(bounds.Dy()), 1)
+	columns := max(int(float64(bounds.Dx())*scale), 1)
+	rows := max(int(float64(bounds.Dy())*scale), 1)
+
+	pixel := func(x, y int) string {
+		sx := bounds.Min.X + x*bounds.Dx()/columns
+		sy := bounds.Min.Y + y*bounds.Dy()/rows
+		// transparent pixels are shown on a black background
+		r, g, b, _ := img.At(sx, sy).RGBA()
+		return fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
+	}
+
+	var out strings.Builder
+	for y := 0; y < rows; y += 2 {
+		for x := range columns {
+			bottom := "#000000"
+			if y+1 < rows {
+				bottom = pixel(x, y+1)
+			}
+			fmt.Fprintf(&out, "[%s:%s]▀", pixel(x, y), bottom)
+		}
+		out.WriteString("[-:-]\n")
+	}
+	return out.String()
+}
+
+// language describes how the text of a file type is highlighted.
+type language struct {
+	keywords     []string
+	lineComments []string
+}
+
+var languages = map[string]language{
+	"go": {
+		keywords: []string{"break", "case", "chan", "const", "continue", "default", "defer", "else", "fallthrough",
+			"for", "func", "go", "goto", "if", "import", "interface", "map", "package", "range", "return", "select",
+			"struct", "switch", "type", "var", "nil", "true", "false"},
+		lineComments: []string{"//"},
+	},
+	"python": {
+		keywords: []string{"and", "as", "assert", "async", "await", "break", "class", "continue", "def", "del", "elif",
+			"else", "except", "finally", "for", "from", "global", "if", "import", "in", "is", "lambda", "None",
+			"nonlocal", "not", "or", "pass", "raise", "return", "True", "False", "try", "while", "with", "yield"},
+		lineComments: []string{"#"},
+	},
+	"javascript": {
+		keywords: []string{"async", "await", "break", "case", "catch", "class", "const", "continue", "default",
+			"delete", "do", "else", "export", "extends", "false", "finally", "for", "function", "if", "import", "in",
+			"instanceof", "interface", "let", "new", "null", "return", "switch", "this", "throw", "true", "try",
+			"type", "typeof", "undefined", "var", "while", "yield"},
+		lineComments: []string{"//"},
+	},
+	"shell": {
+		keywords: []string{"case", "do", "done", "elif", "else", "esac", "export", "fi", "for", "function", "if",
+			"in", "local", "return", "then", "until", "while"},
+		lineComments: []string{"#"},
+	},
+	"sql": {
+		keywords: []string{"SELECT", "FROM", "WHERE", "AND", "OR", "NOT", "INSERT", "INTO", "VALUES", "UPDATE", "SET",
+			"DELETE", "CREATE", "TABLE", "DROP", "ALTER", "JOIN", "LEFT", "RIGHT", "INNER", "OUTER", "ON", "GROUP",
+			"BY", "ORDER", "HAVING", "LIMIT", "AS", "NULL", "IS", "IN", "DISTINCT", "UNION"},
+		lineComments: []string{"--"},
+	},
+	"json": {
+		keywords: []string{"true", "false", "null"},
+	},
+	"yaml": {
+		keywords:     []string{"true", "false", "null", "yes", "no", "on", "off"},
+		lineComments: []string{"#"},
+	},
+	"config": {
+		keywords:     []string{"true", "false"},
+		lineComments: []string{"#", ";"},
+	},
+}
+
+var languageExtensions = map[string]string{
+	".go":   "go",
+	".py":   "python",
+	".js":   "javascript",
+	".mjs":  "javascript",
+	".ts":   "javascript",
+	".tsx":  "javascript",
+	".jsx":  "javascript",
+	".sh":   "shell",
+	".bash": "shell",
+	".zsh":  "shell",
+	".sql":  "sql",
+	".json": "json",
+	".yaml": "yaml",
+	".yml":  "yaml",
+	".toml": "config",
+	".ini":  "config",
+	".conf": "config",
+	".cfg":  "config",
+	".env":  "config",
+}
+
+// languageOf returns the language of a file extension. Unknown extensions
+// are only highlighted for strings and numbers.
+func languageOf(ext string) language {
+	return languages[languageExtensions[ext]]
+}
+
+// highlight colors the keywords, strings, numbers and comments of text.
+func highlight(text string, lang language) string {
+	var out strings.Builder
+	for line := range strings.Lines(text) {
+		highlightLine(&out, line, lang)
+	}
+	return out.String()
+}
+
+func highlightLine(out *strings.Builder, line string, lang language) {
+	// plain text is escaped as a whole, so brackets of different tokens
+	// cannot form a tag
+	var plain strings.Builder
+	token := func(c tcell.Color, text string) {
+		out.WriteString(tview.Escape(plain.String()))
+		plain.Reset()
+		out.WriteString(colored(c, text))
+	}
+
+	runes := []rune(line)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		rest := string(runes[i:])
+
+		if lineComment(rest, lang, i == 0 || unicode.IsSpace(runes[i-1])) {
+			comment := strings.TrimRight(rest, "\n")
+			token(DefaultTheme.SecondaryColor, comment)
+			plain.WriteString(rest[len(comment):])
+			break
+		}
+
+		switch {
+		case r == '"' || r == '\'' || r == '`':
+			end := i + 1
+			for end < len(runes) && runes[end] != r && runes[end] != '\n' {
+				if runes[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			end = min(end+1, len(runes))
+			token(DefaultTheme.InfoColor, string(runes[i:end]))
+			i = end
+		case unicode.IsDigit(r) && (i == 0 || !isWordRune(runes[i-1])):
+			end := i
+			for end < len(runes) && (isWordRune(runes[end]) || runes[end] == '.') {
+				end++
+			}
+			token(DefaultTheme.LabelColor, string(runes[i:end]))
+			i = end
+		case isWordRune(r):
+			end := i
+			for end < len(runes) && isWordRune(runes[end]) {
+				end++
+			}
+			word := string(runes[i:end])
+			if isKeyword(word, lang) {
+				token(DefaultTheme.KeyColor, word)
+			} else {
+				plain.WriteString(word)
+			}
+			i = end
+		default:
+			plain.WriteRune(r)
+			i++
+		}
+	}
+	out.WriteString(tview.Escape(plain.String()))
+}
+
+// lineComment reports whether rest starts a comment. Comments that start
+// with # need to follow a space, so anchors in URLs are not comments.
+func lineComment(rest string, lang language, afterSpace bool) bool {
+	for _, prefix := range lang.lineComments {
+		if strings.HasPrefix(rest, prefix) && (prefix != "#" || afterSpace) {
+			return true
+		}
+	}
+	return false
+}
+
+func isKeyword(word string, lang language) bool {
+	for _, keyword := range lang.keywords {
+		// SQL keywords are written in any case
+		if word == keyword || (strings.ToUpper(keyword) == keyword && strings.EqualFold(word, keyword)) {
+			return true
+		}
+	}
+	return false
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// colored wraps text in tags of color. Colors are given by their hex value,
+// since the names of colors with aliases are not stable.
+func colored(c tcell.Color, text string) string {
+	tag := "-"
+	if hex := c.Hex(); hex >= 0 {
+		tag = fmt.Sprintf("#%06x", hex)
+	}
+	return "[" + tag + "]" + tview.Escape(text) + "[-]"
+}
@@ -0,0 +1,69 @@
+package terminal
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/schidstorm/s3tool/internal/s3lib"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderPreview(t *testing.T) {
+	text := renderPreview("a.json", []byte(`{"a":[1,true]}`), false, 40, 20)
+	assert.Contains(t, text, `"a"`)
+	assert.Contains(t, text, "\n    [#ffa500]1[-],")
+
+	text = renderPreview("a.csv", []byte("name,size\nlong-name,1\n"), false, 40, 20)
+	assert.Equal(t, "[#ffa500]name       size[-]\nlong-name  1\n", text)
+
+	text = renderPreview("a.bin", []byte{0, 1, 2, 'a'}, false, 40, 20)
+	assert.True(t, strings.HasPrefix(text, "00000000  00 01 02 61"), text)
+
+	text = renderPreview("a.yaml", []byte("a:\n      b: 1\n"), false, 40, 20)
+	assert.Equal(t, "a:\n  b: [#ffa500]1[-]\n", text)
+
+	// brackets of the content are never taken as tags
+	text = renderPreview("a.go", []byte(`s := "[red]" + x[i] // [b]`), false, 40, 20)
+	assert.Equal(t, `s := [#008000]"[red[]"[-] + x[i[] [#808080]// [b[][-]`, text)
+}
+
+func TestRenderImage(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	img.Set(0, 0, color.RGBA{R: 255, A: 255})
+	img.Set(0, 1, color.RGBA{B: 255, A: 255})
+	var data bytes.Buffer
+	assert.NoError(t, png.Encode(&data, img))
+
+	text := renderPreview("a.png", data.Bytes(), false, 40, 20)
+	assert.Equal(t, "[#ff0000:#0000ff]▀[#000000:#000000]▀[-:-]\n", text)
+
+	assert.Contains(t, renderPreview("a.png", data.Bytes()[:20], true, 40, 20), "too large")
+}
+
+func TestObjectsPagePreview(t *testing.T) {
+	client := s3lib.NewMemoryClientFactory().
+		WithBucket("bucket", "eu-central-1", time.Now()).
+		WithObject("bucket", "a.json", 7, time.Now(), "etag", "STANDARD", []byte(`{"a":1}`)).
+		WithObject("bucket", "b.txt", previewBytes+1, time.Now(), "etag", "STANDARD", bytes.Repeat([]byte("b"), previewBytes+1)).
+		Build()
+	page := NewObjectsPage(NewContext().WithErrorFunc(func(err error) {
+		t.Error(err)
+	}).WithClient(client).WithBucket("bucket"))
+	assert.NoError(t, page.Load())
+	page.tviewTable.Select(1, 0)
+
+	pressHotkey(t, page, tcell.NewEventKey(tcell.KeyRune, 'P', 0))
+	assert.Equal(t, "{\n  \"a\": 1\n}", page.preview.GetText(true))
+
+	page.tviewTable.Select(2, 0)
+	assert.Contains(t, page.preview.GetText(true), "… first 64 KiB of 64 KiB")
+
+	pressHotkey(t, page, tcell.NewEventKey(tcell.KeyRune, 'P', 0))
+	assert.Empty(t, page.preview.GetText(true))
+}
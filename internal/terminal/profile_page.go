@@ -2,8 +2,7 @@ package terminal
 
 import (
 	"context"
-	"slices"
-	"strings"
+	"errors"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/schidstorm/s3tool/internal/s3lib"
@@ -12,8 +11,9 @@ import (
 type ProfilePage struct {
 	*ListPage[s3lib.Connector]
 
-	loaders []s3lib.ConnectorLoader
-	context Context
+	loaders    []s3lib.ConnectorLoader
+	context    Context
+	connecting bool
 }
 
 func NewProfilePage(c Context, loaders []s3lib.ConnectorLoader) *ProfilePage {
@@ -26,39 +26,53 @@ func NewProfilePage(c Context, loaders []s3lib.ConnectorLoader) *ProfilePage {
 	page.SetMultiSelect(false)
 	page.AddColumn("Type", func(item s3lib.Connector) string { return item.Type() })
 	page.AddColumn("Name", func(item s3lib.Connector) string { return item.Name() })
-
-	page.SetSelectedFunc(func(connector s3lib.Connector) {
-		client, err := connector.CreateClient(context.Background())
-		if err != nil {
-			c.SetError(err)
-			return
+	page.AddColumn("Credentials", func(item s3lib.Connector) string {
+		if typer, ok := item.(s3lib.CredentialTyper); ok {
+			return typer.CredentialType()
 		}
-
-		c.OpenPage(NewBucketsPage(c.WithClient(client)))
+		return ""
 	})
 
+	page.SetSelectedFunc(page.connect)
+
 	return page
 }
 
-func loadConnectors(loaders []s3lib.ConnectorLoader) ([]s3lib.Connector, error) {
-	var profiles []s3lib.Connector
-
-	for _, loader := range loaders {
-		loadedProfiles, err := loader.Load()
+// connect creates the client of connector and opens its buckets. With a UI
+// the client is created in the background, as signing in may ask the user
+// for MFA codes or to confirm a device login.
+func (b *ProfilePage) connect(connector s3lib.Connector) {
+	c := b.context
+	ctx := s3lib.WithPrompter(context.Background(), modalPrompter{context: c})
+	open := func(client s3lib.Client, err error) {
 		if err != nil {
-			return nil, err
+			if !errors.Is(err, context.Canceled) {
+				c.SetError(err)
+			}
+			return
 		}
-		profiles = append(profiles, loadedProfiles...)
+		c.OpenPage(NewBucketsPage(c.WithClient(client)))
 	}
 
-	slices.SortFunc(profiles, func(a, b s3lib.Connector) int {
-		if byType := strings.Compare(a.Type(), b.Type()); byType != 0 {
-			return byType
-		}
-		return strings.Compare(a.Name(), b.Name())
-	})
+	queueUpdate := c.QueueUpdateFunc()
+	if queueUpdate == nil {
+		open(connector.CreateClient(ctx))
+		return
+	}
 
-	return profiles, nil
+	if b.connecting {
+		return
+	}
+	b.connecting = true
+	b.setStatus("Connecting to " + connector.Name() + "…")
+	go func() {
+		client, err := connector.CreateClient(ctx)
+		queueUpdate(func() {
+			b.connecting = false
+			b.setStatus("")
+			open(client, err)
+		})
+	}()
 }
 
 func (b *ProfilePage) Title() string {
@@ -76,7 +90,7 @@ func (b *ProfilePage) Hotkeys() map[tcell.EventKey]Hotkey {
 func (b *ProfilePage) Load() error {
 	b.ClearRows()
 
-	profiles, err := loadConnectors(b.loaders)
+	profiles, err := s3lib.LoadConnectors(b.loaders)
 	if err != nil {
 		return err
 	}
@@ -37,9 +37,9 @@ func TestLoadConnectorsAggregates(t *testing.T) {
 		profileTestLoader{connectors: []s3lib.Connector{profileTestConnector{name: "b", typeName: "s3tool"}}},
 	}
 
-	connectors, err := loadConnectors(loaders)
+	connectors, err := s3lib.LoadConnectors(loaders)
 	if err != nil {
-		t.Fatalf("loadConnectors failed: %v", err)
+		t.Fatalf("LoadConnectors failed: %v", err)
 	}
 	if len(connectors) != 2 {
 		t.Fatalf("expected 2 connectors, got %d", len(connectors))
@@ -58,9 +58,9 @@ func TestLoadConnectorsSortsByTypeThenName(t *testing.T) {
 		}},
 	}
 
-	connectors, err := loadConnectors(loaders)
+	connectors, err := s3lib.LoadConnectors(loaders)
 	if err != nil {
-		t.Fatalf("loadConnectors failed: %v", err)
+		t.Fatalf("LoadConnectors failed: %v", err)
 	}
 
 	got := make([]string, len(connectors))
@@ -83,7 +83,7 @@ func TestLoadConnectorsReturnsError(t *testing.T) {
 		profileTestLoader{err: expected},
 	}
 
-	_, err := loadConnectors(loaders)
+	_, err := s3lib.LoadConnectors(loaders)
 	if err == nil {
 		t.Fatal("expected loader error, got nil")
 	}
@@ -107,8 +107,8 @@ func TestNewProfilePageBasics(t *testing.T) {
 	}
 
 	columns := page.table.Columns()
-	if len(columns) != 2 || columns[0] != "Type" || columns[1] != "Name" {
-		t.Fatalf("expected columns [Type Name], got %#v", columns)
+	if len(columns) != 3 || columns[0] != "Type" || columns[1] != "Name" || columns[2] != "Credentials" {
+		t.Fatalf("expected columns [Type Name Credentials], got %#v", columns)
 	}
 }
 
@@ -140,3 +140,97 @@ func TestProfilePageLoadReplacesRows(t *testing.T) {
 		t.Fatalf("unexpected second row %#v", rows[1])
 	}
 }
+
+type credentialTestConnector struct {
+	profileTestConnector
+	credentialType string
+}
+
+func (c credentialTestConnector) CredentialType() string { return c.credentialType }
+
+func TestProfilePageShowsCredentialType(t *testing.T) {
+	page := NewProfilePage(NewContext(), nil)
+	page.Add(credentialTestConnector{profileTestConnector{name: "dev", typeName: "aws"}, s3lib.CredentialsSSO})
+	page.Add(profileTestConnector{name: "local", typeName: "memory"})
+
+	rows := page.table.Rows()
+	if rows[0][2] != "sso" || rows[1][2] != "" {
+		t.Fatalf("unexpected credential types %#v", rows)
+	}
+}
+
+func TestProfilePageConnectsInBackground(t *testing.T) {
+	updates := make(chan func(), 1)
+	var opened PageContent
+	ctx := NewContext().
+		WithQueueUpdateFunc(func(f func()) { updates <- f }).
+		WithOpenPageFunc(func(page PageContent) { opened = page }).
+		WithErrorFunc(func(err error) { t.Fatalf("connect failed: %v", err) })
+
+	page := NewProfilePage(ctx, nil)
+	page.connect(profileTestConnector{name: "dev", typeName: "aws"})
+	if !page.connecting || page.status.GetText(true) != "Connecting to dev…" {
+		t.Fatalf("expected connecting status, got %q", page.status.GetText(true))
+	}
+
+	(<-updates)()
+	if page.connecting || opened == nil {
+		t.Fatal("expected the buckets page to open once connected")
+	}
+}
+
+func TestModalPrompter(t *testing.T) {
+	var messages []string
+	answer := func(button string, values map[string]string) Context {
+		return NewContext().
+			WithQueueUpdateFunc(func(f func()) { f() }).
+			WithModalFunc(func(build ModalBuilder) {
+				modal := build(func() {}).(*Modal)
+				messages = append(messages, modal.text)
+				modal.done(button, values)
+			})
+	}
+
+	code, err := modalPrompter{answer("Continue", map[string]string{"MFA Code": " 123456 "})}.Prompt(context.Background(), "Enter the code", "MFA Code")
+	if err != nil || code != "123456" {
+		t.Fatalf("expected code 123456, got %q %v", code, err)
+	}
+
+	_, err = modalPrompter{answer("Cancel", nil)}.Prompt(context.Background(), "Confirm the login", "")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancelled prompt, got %v", err)
+	}
+
+	if len(messages) != 2 || messages[0] != "Enter the code" {
+		t.Fatalf("unexpected prompts %q", messages)
+	}
+}
+
+// promptingTestConnector asks its prompter for an MFA code while creating a
+// client.
+type promptingTestConnector struct {
+	profileTestConnector
+	prompter s3lib.Prompter
+}
+
+func (c promptingTestConnector) CreateClient(ctx context.Context) (s3lib.Client, error) {
+	if _, err := c.prompter.Prompt(ctx, "Enter the code", "MFA Code"); err != nil {
+		return nil, err
+	}
+	return s3lib.NewMemoryClient(), nil
+}
+
+func TestModalPrompterWithoutUI(t *testing.T) {
+	var errs []error
+	shown := false
+	ctx := NewContext().
+		WithModalFunc(func(build ModalBuilder) { shown = true }).
+		WithOpenPageFunc(func(page PageContent) { t.Fatal("expected no page to open") }).
+		WithErrorFunc(func(err error) { errs = append(errs, err) })
+
+	page := NewProfilePage(ctx, nil)
+	page.connect(promptingTestConnector{profileTestConnector{name: "dev", typeName: "s3tool"}, modalPrompter{context: ctx}})
+	if shown || len(errs) != 1 || errs[0].Error() != "cannot prompt without a UI" {
+		t.Fatalf("expected the prompt to fail without a modal, got %v %v", shown, errs)
+	}
+}
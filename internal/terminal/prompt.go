@@ -0,0 +1,59 @@
+package terminal
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// modalPrompter asks the user for what connectors need to sign in, like MFA
+// codes, with modals. Prompt blocks until the modal is answered, so it must
+// not be called on the goroutine of the UI. Without a UI it fails, as nothing
+// could answer the modal.
+type modalPrompter struct {
+	context Context
+}
+
+func (p modalPrompter) Prompt(ctx context.Context, message, label string) (string, error) {
+	type answer struct {
+		value string
+		err   error
+	}
+	answers := make(chan answer, 1)
+
+	show := func() {
+		p.context.Modal(func(close func()) tview.Primitive {
+			modal := NewModal().SetText(tview.Escape(message))
+			if label != "" {
+				modal = modal.AddInput().SetLabel(label).Modal
+			}
+			modal.
+				AddButtons([]string{"Continue", "Cancel"}).
+				SetDoneFunc(func(buttonLabel string, values map[string]string) {
+					close()
+					if buttonLabel != "Continue" {
+						answers <- answer{err: context.Canceled}
+						return
+					}
+					answers <- answer{value: strings.TrimSpace(values[label])}
+				})
+			modal.SetTitleAlign(tview.AlignLeft)
+			return modal
+		})
+	}
+
+	queueUpdate := p.context.QueueUpdateFunc()
+	if queueUpdate == nil {
+		return "", errors.New("cannot prompt without a UI")
+	}
+	queueUpdate(show)
+
+	select {
+	case a := <-answers:
+		return a.value, a.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
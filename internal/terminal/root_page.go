@@ -20,6 +20,8 @@ type RootPage struct {
 
 	pageStask      []*Page
 	openModalNames []string
+	globalHotkeys  map[tcell.EventKey]Hotkey
+	globalKeys     keyDispatcher
 }
 
 func NewRootPage() *RootPage {
@@ -30,8 +32,8 @@ func NewRootPage() *RootPage {
 	header.AddItem(profileInfo, 0, 1, false)
 
 	hotkeyInfo := NewHotkeyInfoBox()
-	hotkeyInfo.Update(nil)
-	header.AddItem(hotkeyInfo, 40, 0, false)
+	hotkeyInfo.Update(nil, nil)
+	header.AddItem(hotkeyInfo, 60, 0, false)
 
 	content := tview.NewPages()
 	content.SetBorder(true)
@@ -44,10 +46,11 @@ func NewRootPage() *RootPage {
 	flex.AddItem(content, 0, 1, true)
 
 	a := &RootPage{
-		profileInfo: profileInfo,
-		hotkeyInfo:  hotkeyInfo,
-		pages:       content,
-		Flex:        flex,
+		profileInfo:   profileInfo,
+		hotkeyInfo:    hotkeyInfo,
+		pages:         content,
+		Flex:          flex,
+		globalHotkeys: map[tcell.EventKey]Hotkey{},
 	}
 
 	flex.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
@@ -58,12 +61,38 @@ func NewRootPage() *RootPage {
 				return nil
 			}
 		}
+
+		if len(a.openModalNames) == 0 {
+			hotkey, ok, waiting := a.globalKeys.dispatch(event)
+			if ok {
+				return hotkey.Handler(event)
+			}
+			if waiting {
+				return nil
+			}
+		}
 		return event
 	})
 
 	return a
 }
 
+// SetGlobalHotkey registers a hotkey that is available on every page.
+func (a *RootPage) SetGlobalHotkey(key tcell.EventKey, hotkey Hotkey) {
+	a.globalHotkeys[key] = hotkey
+	a.globalKeys.bindings = ActiveKeymap.Bindings(a.globalHotkeys)
+	if page := a.currentPage(); page != nil {
+		a.hotkeyInfo.Update(page.content, a.globalHotkeys)
+	}
+}
+
+func (a *RootPage) currentPage() *Page {
+	if len(a.pageStask) == 0 {
+		return nil
+	}
+	return a.pageStask[len(a.pageStask)-1]
+}
+
 func (a *RootPage) Modal(p ModalBuilder) {
 	name := "modal_" + strconv.FormatInt(time.Now().UnixNano(), 16)
 	a.openModalNames = append(a.openModalNames, name)
@@ -125,7 +154,7 @@ func (a *RootPage) openPage(page *Page) {
 		a.SetError(err)
 	}
 
-	a.hotkeyInfo.Update(page.content)
+	a.hotkeyInfo.Update(page.content, a.globalHotkeys)
 	a.profileInfo.UpdateContext(page.content.Context())
 }
 
@@ -134,6 +163,7 @@ func (a *RootPage) closePage() {
 		return
 	}
 
+	a.currentPage().closeContent()
 	a.pageStask = a.pageStask[:len(a.pageStask)-1]
 
 	a.openPage(a.pageStask[len(a.pageStask)-1])
@@ -16,9 +16,13 @@ func NewSimulatedApp(page PageContent, loaders ...s3lib.ConnectorLoader) Simulat
 	app := &App{
 		root:        root,
 		Application: tview.NewApplication(),
+		transfers:   NewTransferManager(transferConcurrency()),
+		clipboard:   NewClipboard(),
 	}
+	app.setupTransfers()
 
 	screen := tcell.NewSimulationScreen("")
+	app.screen = screen
 	app.SetScreen(screen)
 
 	if page == nil {
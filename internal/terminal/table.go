@@ -1,10 +1,19 @@
 package terminal
 
+import (
+	"cmp"
+	"slices"
+)
+
 type ColumnFiller[TItem any] func(item TItem) string
 
+// ColumnComparator orders the items of a sortable column like cmp.Compare.
+type ColumnComparator[TItem any] func(a, b TItem) int
+
 type column[TItem any] struct {
-	name   string
-	filler ColumnFiller[TItem]
+	name    string
+	filler  ColumnFiller[TItem]
+	compare ColumnComparator[TItem]
 }
 
 type Table[TItem any] struct {
@@ -14,6 +23,12 @@ type Table[TItem any] struct {
 	filteredRows []int
 	filter       string
 	highlighted  map[int]struct{}
+
+	// sortColumn is the name of the column the rows are sorted by, empty for
+	// the insertion order. It is kept when the columns are rebuilt.
+	sortColumn     string
+	sortDescending bool
+	pinned         func(item TItem) bool
 }
 
 func NewTable[TItem any]() *Table[TItem] {
@@ -27,6 +42,22 @@ func (t *Table[TItem]) AddColumn(name string, filler ColumnFiller[TItem]) {
 	})
 }
 
+// AddSortableColumn adds a column whose rows can be sorted by compare.
+func (t *Table[TItem]) AddSortableColumn(name string, filler ColumnFiller[TItem], compare ColumnComparator[TItem]) {
+	t.columns = append(t.columns, column[TItem]{
+		name:    name,
+		filler:  filler,
+		compare: compare,
+	})
+	t.sortRows()
+}
+
+// ClearColumns removes all columns together with the rows built from them.
+func (t *Table[TItem]) ClearColumns() {
+	t.columns = nil
+	t.Clear()
+}
+
 func (t *Table[TItem]) Add(item TItem) {
 	t.allItems = append(t.allItems, item)
 
@@ -36,9 +67,18 @@ func (t *Table[TItem]) Add(item TItem) {
 	}
 
 	t.allRows = append(t.allRows, row)
-	if matchAnyItems(t.filter, row) {
-		t.filteredRows = append(t.filteredRows, len(t.allRows)-1)
+	if !matchAnyItems(t.filter, row) {
+		return
 	}
+
+	rowIndex := len(t.allRows) - 1
+	compare := t.rowComparator()
+	if compare == nil {
+		t.filteredRows = append(t.filteredRows, rowIndex)
+		return
+	}
+	position, _ := slices.BinarySearchFunc(t.filteredRows, rowIndex, compare)
+	t.filteredRows = slices.Insert(t.filteredRows, position, rowIndex)
 }
 
 func (t *Table[TItem]) Rows() [][]string {
@@ -77,12 +117,110 @@ func (t *Table[TItem]) SetFilter(filter string) {
 			t.filteredRows = append(t.filteredRows, i)
 		}
 	}
+	t.sortRows()
+}
+
+// SetSort sorts the rows by the sortable column name. An empty name restores
+// the insertion order.
+func (t *Table[TItem]) SetSort(name string, descending bool) {
+	t.sortColumn, t.sortDescending = name, descending
+	t.sortRows()
+}
+
+// Sort returns the column the rows are sorted by, empty if they are not
+// sorted.
+func (t *Table[TItem]) Sort() (name string, descending bool) {
+	if t.sortComparator() == nil {
+		return "", false
+	}
+	return t.sortColumn, t.sortDescending
+}
+
+// CycleSort sorts by the next step of: each sortable column ascending, then
+// descending, then the insertion order again.
+func (t *Table[TItem]) CycleSort() {
+	var sortable []string
+	for _, col := range t.columns {
+		if col.compare != nil {
+			sortable = append(sortable, col.name)
+		}
+	}
+
+	name, descending := t.Sort()
+	switch index := slices.Index(sortable, name); {
+	case name != "" && !descending:
+		t.SetSort(name, true)
+	case index+1 < len(sortable):
+		t.SetSort(sortable[index+1], false)
+	default:
+		t.SetSort("", false)
+	}
+}
+
+// SetPinnedFunc keeps the rows of items for which pinned returns true in
+// front of the others when sorting, like the parent directory of a listing.
+func (t *Table[TItem]) SetPinnedFunc(pinned func(item TItem) bool) {
+	t.pinned = pinned
+	t.sortRows()
+}
+
+func (t *Table[TItem]) sortComparator() ColumnComparator[TItem] {
+	if t.sortColumn == "" {
+		return nil
+	}
+	for _, col := range t.columns {
+		if col.name == t.sortColumn {
+			return col.compare
+		}
+	}
+	return nil
+}
+
+// rowComparator orders indices of allRows by the sort column, or returns nil
+// if the rows are not sorted. Equal rows keep their insertion order.
+func (t *Table[TItem]) rowComparator() func(a, b int) int {
+	compare := t.sortComparator()
+	if compare == nil {
+		return nil
+	}
+
+	return func(a, b int) int {
+		itemA, itemB := t.allItems[a], t.allItems[b]
+		if t.pinned != nil {
+			if pinnedA, pinnedB := t.pinned(itemA), t.pinned(itemB); pinnedA != pinnedB {
+				if pinnedA {
+					return -1
+				}
+				return 1
+			}
+		}
+
+		result := compare(itemA, itemB)
+		if t.sortDescending {
+			result = -result
+		}
+		if result != 0 {
+			return result
+		}
+		return cmp.Compare(a, b)
+	}
+}
+
+func (t *Table[TItem]) sortRows() {
+	compare := t.rowComparator()
+	if compare == nil {
+		slices.Sort(t.filteredRows)
+		return
+	}
+	slices.SortFunc(t.filteredRows, compare)
 }
 
 func (t *Table[TItem]) Clear() {
 	t.allRows = t.allRows[:0]
 	t.allItems = t.allItems[:0]
 	t.filteredRows = t.filteredRows[:0]
+	// highlights refer to row indices and would select other items afterwards
+	t.highlighted = nil
 }
 
 func (t *Table[TItem]) ToggleHighlight(rowIndex int) {
@@ -1,7 +1,10 @@
 package terminal
 
 import (
+	"cmp"
+	"fmt"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -66,3 +69,82 @@ func TestTableMatch(t *testing.T) {
 	_, ok = table.GetRowItem(2)
 	assert.False(t, ok)
 }
+
+func sortedTestTable() *Table[testTableItem] {
+	table := NewTable[testTableItem]()
+	table.AddColumn("Name", func(i testTableItem) string { return i.Name })
+	table.AddSortableColumn("Age", func(i testTableItem) string { return strconv.Itoa(i.Age) },
+		func(a, b testTableItem) int { return cmp.Compare(a.Age, b.Age) })
+	table.AddSortableColumn("City", func(i testTableItem) string { return i.City },
+		func(a, b testTableItem) int { return strings.Compare(a.City, b.City) })
+	return table
+}
+
+func tableNames(table *Table[testTableItem]) []string {
+	var names []string
+	for _, row := range table.Rows() {
+		names = append(names, row[0])
+	}
+	return names
+}
+
+func TestTableSort(t *testing.T) {
+	table := sortedTestTable()
+	table.SetSort("Age", false)
+	table.Add(testTableItem{Name: "Alice", Age: 30, City: "New York"})
+	table.Add(testTableItem{Name: "Bob", Age: 25, City: "Los Angeles"})
+	table.Add(testTableItem{Name: "Charlie", Age: 100, City: "Chicago"})
+	table.Add(testTableItem{Name: "Diana", Age: 25, City: "New Orleans"})
+
+	// numeric order, equal ages keep the insertion order
+	assert.Equal(t, []string{"Bob", "Diana", "Alice", "Charlie"}, tableNames(table))
+	item, ok := table.GetRowItem(3)
+	assert.True(t, ok)
+	assert.Equal(t, "Charlie", item.Name)
+
+	table.SetSort("Age", true)
+	assert.Equal(t, []string{"Charlie", "Alice", "Bob", "Diana"}, tableNames(table))
+
+	table.SetFilter("New")
+	assert.Equal(t, []string{"Alice", "Diana"}, tableNames(table))
+
+	table.SetFilter("")
+	table.SetSort("", false)
+	assert.Equal(t, []string{"Alice", "Bob", "Charlie", "Diana"}, tableNames(table))
+}
+
+func TestTableCycleSort(t *testing.T) {
+	table := sortedTestTable()
+
+	var orders []string
+	for range 5 {
+		table.CycleSort()
+		name, descending := table.Sort()
+		orders = append(orders, fmt.Sprintf("%s %t", name, descending))
+	}
+	assert.Equal(t, []string{"Age false", "Age true", "City false", "City true", " false"}, orders)
+
+	// the sort column survives rebuilding the columns
+	table.SetSort("City", true)
+	table.ClearColumns()
+	name, _ := table.Sort()
+	assert.Empty(t, name)
+	table.AddSortableColumn("City", func(i testTableItem) string { return i.City },
+		func(a, b testTableItem) int { return strings.Compare(a.City, b.City) })
+	name, descending := table.Sort()
+	assert.Equal(t, "City", name)
+	assert.True(t, descending)
+}
+
+func TestTablePinnedRows(t *testing.T) {
+	table := sortedTestTable()
+	table.SetPinnedFunc(func(i testTableItem) bool { return i.Name == "Parent" })
+	table.Add(testTableItem{Name: "Alice", Age: 30})
+	table.Add(testTableItem{Name: "Parent", Age: 50})
+	table.Add(testTableItem{Name: "Bob", Age: 60})
+
+	table.SetSort("Age", false)
+	assert.Equal(t, []string{"Parent", "Alice", "Bob"}, tableNames(table))
+	table.SetSort("Age", true)
+	assert.Equal(t, []string{"Parent", "Bob", "Alice"}, tableNames(table))
+}
@@ -0,0 +1,197 @@
+package terminal
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/rivo/tview"
+	"github.com/schidstorm/s3tool/internal/s3lib"
+)
+
+// parseKeyValues parses comma separated key=value pairs, as entered in the tag
+// and metadata forms. Values may contain "=", keys may not be empty. A
+// backslash escapes the next character, so \, is a comma in a key or value.
+func parseKeyValues(text string) (map[string]string, error) {
+	values := map[string]string{}
+	for _, pair := range splitEscaped(text) {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid pair %q, expected key=value", pair)
+		}
+		values[key] = strings.TrimSpace(value)
+	}
+	return values, nil
+}
+
+// formatKeyValues formats values sorted by key, so that parseKeyValues reads
+// them back.
+func formatKeyValues(values map[string]string) string {
+	escape := strings.NewReplacer(`\`, `\\`, ",", `\,`).Replace
+	pairs := make([]string, 0, len(values))
+	for _, key := range slices.Sorted(maps.Keys(values)) {
+		pairs = append(pairs, escape(key)+"="+escape(values[key]))
+	}
+	return strings.Join(pairs, ", ")
+}
+
+// parseKeys parses a comma separated list of keys, escaped like the pairs of
+// parseKeyValues.
+func parseKeys(text string) []string {
+	var keys []string
+	for _, key := range splitEscaped(text) {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// splitEscaped splits text at commas not escaped by a backslash and removes
+// the escaping backslashes.
+func splitEscaped(text string) []string {
+	var parts []string
+	var part strings.Builder
+	escaped := false
+	for _, r := range text {
+		switch {
+		case escaped:
+			part.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == ',':
+			parts = append(parts, part.String())
+			part.Reset()
+		default:
+			part.WriteRune(r)
+		}
+	}
+	return append(parts, part.String())
+}
+
+// editTagsForm lets the user change the tags of the object of c and replaces
+// them in the background.
+func editTagsForm(c Context, tags map[string]string, done func(err error)) {
+	c.Modal(func(close func()) tview.Primitive {
+		return NewModal().
+			SetTitle("Edit Tags").
+			SetText(`key=value pairs separated by commas, write \, for a comma`).
+			AddInput().SetLabel("Tags").SetText(formatKeyValues(tags)).
+			AddButtons([]string{"Save", "Cancel"}).
+			SetDoneFunc(func(buttonLabel string, values map[string]string) {
+				close()
+				if buttonLabel != "Save" {
+					return
+				}
+
+				newTags, err := parseKeyValues(values["Tags"])
+				if err != nil {
+					c.SetError(err)
+					return
+				}
+				putObjectTags(c, newTags, done)
+			})
+	})
+}
+
+// editPropertiesForm lets the user change the headers and user metadata of
+// the object of c and replaces them in the background.
+func editPropertiesForm(c Context, obj s3lib.ObjectMetadata, done func(err error)) {
+	c.Modal(func(close func()) tview.Primitive {
+		return NewModal().
+			SetTitle("Edit Metadata").
+			SetText(`Metadata as key=value pairs separated by commas, write \, for a comma`).
+			AddInput().SetLabel("Content-Type").SetText(aws.ToString(obj.Type)).
+			AddInput().SetLabel("Cache-Control").SetText(aws.ToString(obj.CacheControl)).
+			AddInput().SetLabel("Content-Disposition").SetText(aws.ToString(obj.ContentDisposition)).
+			AddInput().SetLabel("Metadata").SetText(formatKeyValues(obj.Metadata)).
+			AddButtons([]string{"Save", "Cancel"}).
+			SetDoneFunc(func(buttonLabel string, values map[string]string) {
+				close()
+				if buttonLabel != "Save" {
+					return
+				}
+
+				metadata, err := parseKeyValues(values["Metadata"])
+				if err != nil {
+					c.SetError(err)
+					return
+				}
+				setObjectProperties(c, s3lib.ObjectProperties{
+					ContentType:        strings.TrimSpace(values["Content-Type"]),
+					CacheControl:       strings.TrimSpace(values["Cache-Control"]),
+					ContentDisposition: strings.TrimSpace(values["Content-Disposition"]),
+					Metadata:           metadata,
+				}, done)
+			})
+	})
+}
+
+// putObjectTags replaces the tags of the object of c in the background.
+func putObjectTags(c Context, tags map[string]string, done func(err error)) {
+	client, bucket, key := c.S3Client(), c.Bucket(), c.ObjectKey()
+
+	c.Transfers().Submit(NewTransfer(TransferTag, bucket, key,
+		func(ctx context.Context, progress func(transferred, total int64)) error {
+			return client.PutObjectTags(ctx, bucket, key, tags)
+		},
+		transferDone(c, done),
+	))
+}
+
+// setObjectProperties replaces the headers and user metadata of the object of
+// c in the background.
+func setObjectProperties(c Context, props s3lib.ObjectProperties, done func(err error)) {
+	client, bucket, key := c.S3Client(), c.Bucket(), c.ObjectKey()
+
+	c.Transfers().Submit(NewTransfer(TransferMetadata, bucket, key,
+		func(ctx context.Context, progress func(transferred, total int64)) error {
+			return client.SetObjectProperties(ctx, bucket, key, props)
+		},
+		transferDone(c, done),
+	))
+}
+
+// tagObjects adds the tags add to objects and removes the tag keys remove
+// from them in the background. Other tags of the objects are kept. label
+// names the objects in the transfers page.
+func tagObjects(c Context, label string, objects []s3lib.Object, add map[string]string, remove []string, done func(err error)) {
+	client, bucket, keys := c.S3Client(), c.Bucket(), objectKeys(objects)
+
+	c.Transfers().Submit(NewTransfer(TransferTag, bucket, label,
+		func(ctx context.Context, progress func(transferred, total int64)) error {
+			for _, key := range keys {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+
+				tags, err := client.GetObjectTags(ctx, bucket, key)
+				if err != nil {
+					return fmt.Errorf("%s: %w", key, err)
+				}
+				if tags == nil {
+					tags = map[string]string{}
+				}
+				for _, tagKey := range remove {
+					delete(tags, tagKey)
+				}
+				maps.Copy(tags, add)
+				if err := client.PutObjectTags(ctx, bucket, key, tags); err != nil {
+					return fmt.Errorf("%s: %w", key, err)
+				}
+			}
+			return nil
+		},
+		transferDone(c, done),
+	))
+}
@@ -0,0 +1,116 @@
+package terminal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/gdamore/tcell/v2"
+	"github.com/schidstorm/s3tool/internal/s3lib"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseKeyValues(t *testing.T) {
+	values, err := parseKeyValues(" env = prod, query=a=b,, team=")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"env": "prod", "query": "a=b", "team": ""}, values)
+	assert.Equal(t, "env=prod, query=a=b, team=", formatKeyValues(values))
+
+	values, err = parseKeyValues("")
+	assert.NoError(t, err)
+	assert.Empty(t, values)
+
+	_, err = parseKeyValues("env")
+	assert.Error(t, err)
+	_, err = parseKeyValues("=prod")
+	assert.Error(t, err)
+
+	assert.Equal(t, []string{"a", "b"}, parseKeys(" a,,b "))
+
+	values = map[string]string{"filter": "a,b", "path": `C:\dir`}
+	assert.Equal(t, `filter=a\,b, path=C:\\dir`, formatKeyValues(values))
+	parsed, err := parseKeyValues(formatKeyValues(values))
+	assert.NoError(t, err)
+	assert.Equal(t, values, parsed)
+	assert.Equal(t, []string{"a,b", "c"}, parseKeys(`a\,b, c`))
+}
+
+// formTestContext returns a context on the object a.txt which submits every
+// form with button and values.
+func formTestContext(t *testing.T, client s3lib.Client, button string, values map[string]string) Context {
+	return NewContext().
+		WithClient(client).
+		WithBucket("bucket").
+		WithObjectKey("a.txt").
+		WithErrorFunc(func(err error) { t.Error(err) }).
+		WithModalFunc(func(build ModalBuilder) {
+			build(func() {}).(*Modal).done(button, values)
+		})
+}
+
+func TestObjectPageEditTagsAndMetadata(t *testing.T) {
+	client := s3lib.NewMemoryClientFactory().
+		WithBucket("bucket", "eu-central-1", time.Now()).
+		WithObject("bucket", "a.txt", 1, time.Now(), "etag", "STANDARD", []byte("a")).
+		Build()
+	ctx := context.Background()
+
+	page := NewObjectPage(formTestContext(t, client, "Save", map[string]string{"Tags": "env=prod, team=data"}))
+	assert.NoError(t, page.Load())
+	pressHotkey(t, page, tcell.NewEventKey(tcell.KeyRune, 't', 0))
+
+	tags, err := client.GetObjectTags(ctx, "bucket", "a.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"env": "prod", "team": "data"}, tags)
+	assert.Equal(t, tags, page.object.Tags)
+
+	page = NewObjectPage(formTestContext(t, client, "Save", map[string]string{
+		"Content-Type":        "text/plain",
+		"Cache-Control":       "no-cache",
+		"Content-Disposition": "",
+		"Metadata":            `owner=team-a, filter=a\, b`,
+	}))
+	assert.NoError(t, page.Load())
+	pressHotkey(t, page, tcell.NewEventKey(tcell.KeyRune, 'm', 0))
+
+	obj, err := client.GetObject(ctx, "bucket", "a.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "text/plain", aws.ToString(obj.Type))
+	assert.Equal(t, "no-cache", aws.ToString(obj.CacheControl))
+	assert.Nil(t, obj.ContentDisposition)
+	assert.Equal(t, map[string]string{"owner": "team-a", "filter": "a, b"}, obj.Metadata)
+	assert.Equal(t, map[string]string{"env": "prod", "team": "data"}, obj.Tags)
+	assert.Contains(t, getTableRows(page.Table), []string{"CacheControl", "no-cache"})
+}
+
+func TestObjectsPageTagObjects(t *testing.T) {
+	client := s3lib.NewMemoryClientFactory().
+		WithBucket("bucket", "eu-central-1", time.Now()).
+		WithObject("bucket", "a.txt", 1, time.Now(), "etag", "STANDARD", []byte("a")).
+		WithObject("bucket", "dir/b.txt", 1, time.Now(), "etag", "STANDARD", []byte("b")).
+		WithObject("bucket", "c.txt", 1, time.Now(), "etag", "STANDARD", []byte("c")).
+		Build()
+	ctx := context.Background()
+
+	page := NewObjectsPage(formTestContext(t, client, "OK", map[string]string{
+		"Add":    "project=s3tool",
+		"Remove": "Environment",
+	}).WithObjectKey(""))
+	assert.NoError(t, page.Load())
+
+	page.tagObjectsForm([]s3lib.Object{
+		s3lib.NewObjectDirectory("dir/"),
+		s3lib.NewObjectFile(types.Object{Key: aws.String("a.txt")}),
+	})
+
+	for _, key := range []string{"a.txt", "dir/b.txt"} {
+		tags, err := client.GetObjectTags(ctx, "bucket", key)
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"project": "s3tool"}, tags, key)
+	}
+	tags, err := client.GetObjectTags(ctx, "bucket", "c.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"Environment": "Test"}, tags)
+}
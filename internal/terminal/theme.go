@@ -1,6 +1,15 @@
 package terminal
 
-import "github.com/gdamore/tcell/v2"
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"gopkg.in/yaml.v3"
+)
 
 type Theme struct {
 	// reworked colors
@@ -11,20 +20,261 @@ type Theme struct {
 	ErrorColor          tcell.Color
 	InfoColor           tcell.Color
 	BorderColor         tcell.Color
+	BackgroundColor     tcell.Color
+	ModalColor          tcell.Color
 	HighlightStyle      tcell.Style
 	MultiHighlightStyle tcell.Style
+	// NoColor is set by NoColorTheme, content like images is not rendered
+	// with its own colors then.
+	NoColor bool
+}
+
+// Themes are the built-in themes, selected by their name with --theme.
+var Themes = map[string]Theme{
+	"dark": {
+		PrimaryColor:        tcell.ColorWhite,
+		SecondaryColor:      tcell.ColorGray,
+		KeyColor:            tcell.ColorNavy,
+		LabelColor:          tcell.ColorOrange,
+		ErrorColor:          tcell.ColorRed,
+		InfoColor:           tcell.ColorGreen,
+		BorderColor:         tcell.ColorNavy,
+		BackgroundColor:     tcell.ColorBlack,
+		ModalColor:          tcell.ColorBlue,
+		HighlightStyle:      tcell.StyleDefault.Foreground(tcell.ColorBlack).Background(tcell.ColorLightBlue),
+		MultiHighlightStyle: tcell.StyleDefault.Foreground(tcell.ColorYellow),
+	},
+	"light": {
+		PrimaryColor:        tcell.ColorBlack,
+		SecondaryColor:      tcell.ColorDimGray,
+		KeyColor:            tcell.ColorBlue,
+		LabelColor:          tcell.ColorDarkOrange,
+		ErrorColor:          tcell.ColorRed,
+		InfoColor:           tcell.ColorGreen,
+		BorderColor:         tcell.ColorBlue,
+		BackgroundColor:     tcell.ColorWhite,
+		ModalColor:          tcell.ColorLightGray,
+		HighlightStyle:      tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorBlue),
+		MultiHighlightStyle: tcell.StyleDefault.Foreground(tcell.ColorDarkMagenta),
+	},
+	"high-contrast": {
+		PrimaryColor:        tcell.ColorWhite,
+		SecondaryColor:      tcell.ColorSilver,
+		KeyColor:            tcell.ColorYellow,
+		LabelColor:          tcell.ColorAqua,
+		ErrorColor:          tcell.ColorRed,
+		InfoColor:           tcell.ColorLime,
+		BorderColor:         tcell.ColorWhite,
+		BackgroundColor:     tcell.ColorBlack,
+		ModalColor:          tcell.ColorNavy,
+		HighlightStyle:      tcell.StyleDefault.Foreground(tcell.ColorBlack).Background(tcell.ColorYellow).Bold(true),
+		MultiHighlightStyle: tcell.StyleDefault.Foreground(tcell.ColorAqua).Bold(true).Underline(true),
+	},
+	"solarized": {
+		PrimaryColor:        tcell.NewHexColor(0x93a1a1),
+		SecondaryColor:      tcell.NewHexColor(0x657b83),
+		KeyColor:            tcell.NewHexColor(0x268bd2),
+		LabelColor:          tcell.NewHexColor(0xcb4b16),
+		ErrorColor:          tcell.NewHexColor(0xdc322f),
+		InfoColor:           tcell.NewHexColor(0x859900),
+		BorderColor:         tcell.NewHexColor(0x268bd2),
+		BackgroundColor:     tcell.NewHexColor(0x002b36),
+		ModalColor:          tcell.NewHexColor(0x073642),
+		HighlightStyle:      tcell.StyleDefault.Foreground(tcell.NewHexColor(0x002b36)).Background(tcell.NewHexColor(0x2aa198)),
+		MultiHighlightStyle: tcell.StyleDefault.Foreground(tcell.NewHexColor(0xb58900)),
+	},
+}
+
+var DefaultTheme = Themes["dark"]
+
+var DefaultStyle = tcell.StyleDefault.Foreground(DefaultTheme.PrimaryColor).Background(DefaultTheme.BackgroundColor)
+
+// SetTheme makes theme the theme of all pages and of the tview primitives.
+// It has to be called before the first page is created.
+func SetTheme(theme Theme) {
+	DefaultTheme = theme
+	DefaultStyle = tcell.StyleDefault.Foreground(theme.PrimaryColor).Background(theme.BackgroundColor)
+	listTableRowStyle = DefaultStyle.Foreground(theme.PrimaryColor)
+	listTableMultiHighlightedRowStyle = theme.MultiHighlightStyle
+
+	tview.Styles.PrimitiveBackgroundColor = theme.BackgroundColor
+	tview.Styles.ContrastBackgroundColor = theme.ModalColor
+	tview.Styles.MoreContrastBackgroundColor = theme.BackgroundColor
+	tview.Styles.BorderColor = theme.BorderColor
+	tview.Styles.TitleColor = theme.PrimaryColor
+	tview.Styles.GraphicsColor = theme.BorderColor
+	tview.Styles.PrimaryTextColor = theme.PrimaryColor
+	tview.Styles.SecondaryTextColor = theme.LabelColor
+	tview.Styles.TertiaryTextColor = theme.InfoColor
+	tview.Styles.InverseTextColor = theme.KeyColor
+	tview.Styles.ContrastSecondaryTextColor = theme.SecondaryColor
+}
+
+// NoColorTheme returns the theme for terminals without colors, as requested
+// by NO_COLOR. Selections are shown reversed instead of with colors.
+func NoColorTheme() Theme {
+	return Theme{
+		PrimaryColor:        tcell.ColorDefault,
+		SecondaryColor:      tcell.ColorDefault,
+		KeyColor:            tcell.ColorDefault,
+		LabelColor:          tcell.ColorDefault,
+		ErrorColor:          tcell.ColorDefault,
+		InfoColor:           tcell.ColorDefault,
+		BorderColor:         tcell.ColorDefault,
+		BackgroundColor:     tcell.ColorDefault,
+		ModalColor:          tcell.ColorDefault,
+		HighlightStyle:      tcell.StyleDefault.Reverse(true),
+		MultiHighlightStyle: tcell.StyleDefault.Bold(true).Underline(true),
+		NoColor:             true,
+	}
+}
+
+// themeFile is a theme in YAML. Fields that are left out keep the colors of
+// the base theme.
+type themeFile struct {
+	Base           string      `yaml:"base"`
+	Primary        string      `yaml:"primary"`
+	Secondary      string      `yaml:"secondary"`
+	Key            string      `yaml:"key"`
+	Label          string      `yaml:"label"`
+	Error          string      `yaml:"error"`
+	Info           string      `yaml:"info"`
+	Border         string      `yaml:"border"`
+	Background     string      `yaml:"background"`
+	Modal          string      `yaml:"modal"`
+	Highlight      *themeStyle `yaml:"highlight"`
+	MultiHighlight *themeStyle `yaml:"multi_highlight"`
 }
 
-var DefaultTheme = Theme{
-	PrimaryColor:        tcell.ColorWhite,
-	SecondaryColor:      tcell.ColorGray,
-	KeyColor:            tcell.ColorNavy,
-	LabelColor:          tcell.ColorOrange,
-	ErrorColor:          tcell.ColorRed,
-	InfoColor:           tcell.ColorGreen,
-	BorderColor:         tcell.ColorNavy,
-	HighlightStyle:      tcell.StyleDefault.Foreground(tcell.ColorBlack).Background(tcell.ColorLightBlue),
-	MultiHighlightStyle: tcell.StyleDefault.Foreground(tcell.ColorYellow),
+type themeStyle struct {
+	Foreground string `yaml:"foreground"`
+	Background string `yaml:"background"`
+	Bold       bool   `yaml:"bold"`
+	Underline  bool   `yaml:"underline"`
+	Reverse    bool   `yaml:"reverse"`
 }
 
-var DefaultStyle = tcell.StyleDefault.Foreground(DefaultTheme.PrimaryColor).Background(tcell.ColorBlack)
+// LoadTheme returns the built-in theme name, or reads the theme file at the
+// path name.
+func LoadTheme(name string) (Theme, error) {
+	if theme, ok := Themes[name]; ok {
+		return theme, nil
+	}
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return Theme{}, fmt.Errorf("theme %s is neither a built-in theme nor a file", name)
+		}
+		return Theme{}, err
+	}
+
+	var file themeFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return Theme{}, fmt.Errorf("reading theme %s: %w", name, err)
+	}
+	theme, err := file.theme()
+	if err != nil {
+		return Theme{}, fmt.Errorf("theme %s: %w", name, err)
+	}
+	return theme, nil
+}
+
+func (f themeFile) theme() (Theme, error) {
+	base := f.Base
+	if base == "" {
+		base = "dark"
+	}
+	theme, ok := Themes[base]
+	if !ok {
+		return Theme{}, fmt.Errorf("unknown base theme %q", base)
+	}
+
+	colors := []struct {
+		value string
+		color *tcell.Color
+	}{
+		{f.Primary, &theme.PrimaryColor},
+		{f.Secondary, &theme.SecondaryColor},
+		{f.Key, &theme.KeyColor},
+		{f.Label, &theme.LabelColor},
+		{f.Error, &theme.ErrorColor},
+		{f.Info, &theme.InfoColor},
+		{f.Border, &theme.BorderColor},
+		{f.Background, &theme.BackgroundColor},
+		{f.Modal, &theme.ModalColor},
+	}
+	for _, c := range colors {
+		if c.value == "" {
+			continue
+		}
+		color, err := ParseColor(c.value)
+		if err != nil {
+			return Theme{}, err
+		}
+		*c.color = color
+	}
+
+	styles := []struct {
+		value *themeStyle
+		style *tcell.Style
+	}{
+		{f.Highlight, &theme.HighlightStyle},
+		{f.MultiHighlight, &theme.MultiHighlightStyle},
+	}
+	for _, s := range styles {
+		if s.value == nil {
+			continue
+		}
+		style, err := s.value.style()
+		if err != nil {
+			return Theme{}, err
+		}
+		*s.style = style
+	}
+	return theme, nil
+}
+
+func (s themeStyle) style() (tcell.Style, error) {
+	foreground, err := ParseColor(s.Foreground)
+	if err != nil {
+		return tcell.Style{}, err
+	}
+	background, err := ParseColor(s.Background)
+	if err != nil {
+		return tcell.Style{}, err
+	}
+	return tcell.StyleDefault.
+		Foreground(foreground).
+		Background(background).
+		Bold(s.Bold).
+		Underline(s.Underline).
+		Reverse(s.Reverse), nil
+}
+
+// ParseColor parses a color name like "navy", a hex value like "#268bd2", a
+// number of the 256 color palette or "default" for the color of the terminal.
+func ParseColor(s string) (tcell.Color, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	if name == "" || name == "default" {
+		return tcell.ColorDefault, nil
+	}
+	if hex, ok := strings.CutPrefix(name, "#"); ok {
+		if len(hex) == 6 {
+			if value, err := strconv.ParseInt(hex, 16, 32); err == nil {
+				return tcell.NewHexColor(int32(value)), nil
+			}
+		}
+		return tcell.ColorDefault, fmt.Errorf("invalid hex color %q", s)
+	}
+	if number, err := strconv.Atoi(name); err == nil {
+		if number < 0 || number > 255 {
+			return tcell.ColorDefault, fmt.Errorf("color %d is not in the 256 color palette", number)
+		}
+		return tcell.PaletteColor(number), nil
+	}
+	if color, ok := tcell.ColorNames[name]; ok {
+		return color, nil
+	}
+	return tcell.ColorDefault, fmt.Errorf("unknown color %q", s)
+}
@@ -0,0 +1,77 @@
+package terminal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseColor(t *testing.T) {
+	tests := map[string]tcell.Color{
+		"":        tcell.ColorDefault,
+		"default": tcell.ColorDefault,
+		"Navy":    tcell.ColorNavy,
+		"#268bd2": tcell.NewHexColor(0x268bd2),
+		"208":     tcell.PaletteColor(208),
+	}
+	for value, expected := range tests {
+		color, err := ParseColor(value)
+		assert.NoError(t, err, value)
+		assert.Equal(t, expected, color, value)
+	}
+
+	for _, value := range []string{"#12345", "#gggggg", "256", "blurple"} {
+		_, err := ParseColor(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestLoadTheme(t *testing.T) {
+	theme, err := LoadTheme("solarized")
+	assert.NoError(t, err)
+	assert.Equal(t, Themes["solarized"], theme)
+
+	_, err = LoadTheme(filepath.Join(t.TempDir(), "missing.yaml"))
+	assert.ErrorContains(t, err, "neither a built-in theme nor a file")
+
+	path := filepath.Join(t.TempDir(), "theme.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(`base: light
+key: "#ff0000"
+background: default
+highlight:
+  foreground: white
+  background: 25
+  bold: true
+`), 0o644))
+	theme, err = LoadTheme(path)
+	assert.NoError(t, err)
+	assert.Equal(t, tcell.NewHexColor(0xff0000), theme.KeyColor)
+	assert.Equal(t, tcell.ColorDefault, theme.BackgroundColor)
+	assert.Equal(t, Themes["light"].PrimaryColor, theme.PrimaryColor)
+	assert.Equal(t, tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.PaletteColor(25)).Bold(true), theme.HighlightStyle)
+
+	assert.NoError(t, os.WriteFile(path, []byte("label: blurple\n"), 0o644))
+	_, err = LoadTheme(path)
+	assert.ErrorContains(t, err, `unknown color "blurple"`)
+}
+
+func TestSetTheme(t *testing.T) {
+	previous, previousStyles := DefaultTheme, tview.Styles
+	defer func() {
+		SetTheme(previous)
+		tview.Styles = previousStyles
+	}()
+
+	SetTheme(Themes["light"])
+	assert.Equal(t, tcell.StyleDefault.Foreground(tcell.ColorBlack).Background(tcell.ColorWhite), DefaultStyle)
+	assert.Equal(t, tcell.ColorWhite, tview.Styles.PrimitiveBackgroundColor)
+	assert.Equal(t, Themes["light"].MultiHighlightStyle, listTableMultiHighlightedRowStyle)
+
+	SetTheme(NoColorTheme())
+	assert.Equal(t, tcell.StyleDefault, DefaultStyle)
+	assert.Equal(t, "images are not previewed without colors", renderPreview("a.png", nil, false, 10, 10))
+}
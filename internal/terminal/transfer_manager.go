@@ -0,0 +1,320 @@
+package terminal
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+type TransferKind string
+
+const (
+	TransferUpload   TransferKind = "Upload"
+	TransferDownload TransferKind = "Download"
+	TransferDelete   TransferKind = "Delete"
+	TransferRestore  TransferKind = "Restore"
+	TransferCopy     TransferKind = "Copy"
+	TransferMove     TransferKind = "Move"
+	TransferSync     TransferKind = "Sync"
+	TransferTag      TransferKind = "Tag"
+	TransferMetadata TransferKind = "Metadata"
+)
+
+type TransferState int
+
+const (
+	TransferQueued TransferState = iota
+	TransferRunning
+	TransferDone
+	TransferFailed
+	TransferCancelled
+)
+
+func (s TransferState) String() string {
+	switch s {
+	case TransferQueued:
+		return "Queued"
+	case TransferRunning:
+		return "Running"
+	case TransferDone:
+		return "Done"
+	case TransferFailed:
+		return "Failed"
+	case TransferCancelled:
+		return "Cancelled"
+	}
+	return ""
+}
+
+// TransferFunc performs the work of a transfer. It should report its progress
+// in bytes and stop as soon as ctx is cancelled.
+type TransferFunc func(ctx context.Context, progress func(transferred, total int64)) error
+
+type Transfer struct {
+	Kind   TransferKind
+	Bucket string
+	Key    string
+	// VersionID is the object version the transfer works on, if any.
+	VersionID string
+
+	run  TransferFunc
+	done func(err error)
+
+	mu          sync.Mutex
+	state       TransferState
+	transferred int64
+	total       int64
+	started     time.Time
+	finished    time.Time
+	err         error
+	cancel      context.CancelFunc
+}
+
+// NewTransfer creates a transfer running f. done is called on the UI goroutine
+// every time the transfer finishes, including after retries.
+func NewTransfer(kind TransferKind, bucket, key string, f TransferFunc, done func(err error)) *Transfer {
+	return &Transfer{
+		Kind:   kind,
+		Bucket: bucket,
+		Key:    key,
+		run:    f,
+		done:   done,
+	}
+}
+
+type TransferSnapshot struct {
+	State       TransferState
+	Transferred int64
+	Total       int64
+	Err         error
+	// Rate is the average transfer rate in bytes per second.
+	Rate float64
+	// ETA is the estimated remaining time, zero if unknown.
+	ETA time.Duration
+}
+
+func (t *Transfer) Snapshot() TransferSnapshot {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	snapshot := TransferSnapshot{
+		State:       t.state,
+		Transferred: t.transferred,
+		Total:       t.total,
+		Err:         t.err,
+	}
+
+	end := t.finished
+	if t.state == TransferRunning {
+		end = time.Now()
+	}
+	if elapsed := end.Sub(t.started).Seconds(); !t.started.IsZero() && elapsed > 0 {
+		snapshot.Rate = float64(t.transferred) / elapsed
+	}
+	if t.state == TransferRunning && snapshot.Rate > 0 && t.total > t.transferred {
+		snapshot.ETA = time.Duration(float64(t.total-t.transferred) / snapshot.Rate * float64(time.Second))
+	}
+
+	return snapshot
+}
+
+func (t *Transfer) finishedState() bool {
+	return t.state == TransferDone || t.state == TransferFailed || t.state == TransferCancelled
+}
+
+const transferProgressInterval = 200 * time.Millisecond
+
+// TransferManager runs transfers in the background with a limited number of
+// transfers active at once. A nil *TransferManager runs transfers
+// synchronously on the calling goroutine.
+type TransferManager struct {
+	mu          sync.Mutex
+	transfers   []*Transfer
+	slots       chan struct{}
+	listeners   []func()
+	queueUpdate func(func())
+	lastNotify  time.Time
+}
+
+func NewTransferManager(concurrency int) *TransferManager {
+	return &TransferManager{
+		slots: make(chan struct{}, max(concurrency, 1)),
+	}
+}
+
+// SetQueueUpdateFunc sets the function used to run callbacks on the UI
+// goroutine. Without it callbacks are called directly.
+func (m *TransferManager) SetQueueUpdateFunc(f func(func())) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.queueUpdate = f
+}
+
+// OnChange registers f to be called on the UI goroutine whenever a transfer
+// changes its state or makes progress.
+func (m *TransferManager) OnChange(f func()) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.listeners = append(m.listeners, f)
+}
+
+func (m *TransferManager) Transfers() []*Transfer {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*Transfer(nil), m.transfers...)
+}
+
+func (m *TransferManager) Submit(t *Transfer) {
+	if m == nil {
+		t.state = TransferRunning
+		t.started = time.Now()
+		err := t.run(context.Background(), func(transferred, total int64) {})
+		t.finish(err)
+		if t.done != nil {
+			t.done(err)
+		}
+		return
+	}
+
+	m.mu.Lock()
+	m.transfers = append(m.transfers, t)
+	m.mu.Unlock()
+
+	m.start(t)
+}
+
+func (m *TransferManager) start(t *Transfer) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	t.mu.Lock()
+	t.state = TransferQueued
+	t.transferred, t.total = 0, 0
+	t.started, t.finished = time.Time{}, time.Time{}
+	t.err = nil
+	t.cancel = cancel
+	t.mu.Unlock()
+	m.notify(true)
+
+	go func() {
+		defer cancel()
+
+		select {
+		case m.slots <- struct{}{}:
+		case <-ctx.Done():
+			m.complete(t, ctx.Err())
+			return
+		}
+		defer func() { <-m.slots }()
+
+		t.mu.Lock()
+		t.state = TransferRunning
+		t.started = time.Now()
+		t.mu.Unlock()
+		m.notify(true)
+
+		err := t.run(ctx, func(transferred, total int64) {
+			t.mu.Lock()
+			t.transferred, t.total = transferred, total
+			t.mu.Unlock()
+			m.notify(false)
+		})
+		m.complete(t, err)
+	}()
+}
+
+func (m *TransferManager) complete(t *Transfer, err error) {
+	t.finish(err)
+	m.notify(true)
+	if t.done != nil {
+		m.queue(func() { t.done(err) })
+	}
+}
+
+func (t *Transfer) finish(err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.finished = time.Now()
+	t.err = err
+	switch {
+	case err == nil:
+		t.state = TransferDone
+		t.transferred = t.total
+	case errors.Is(err, context.Canceled):
+		t.state = TransferCancelled
+	default:
+		t.state = TransferFailed
+	}
+}
+
+func (m *TransferManager) Cancel(t *Transfer) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.cancel != nil && !t.finishedState() {
+		t.cancel()
+	}
+}
+
+// Retry restarts a failed or cancelled transfer.
+func (m *TransferManager) Retry(t *Transfer) {
+	t.mu.Lock()
+	retry := t.state == TransferFailed || t.state == TransferCancelled
+	t.mu.Unlock()
+
+	if retry && m != nil {
+		m.start(t)
+	}
+}
+
+// ClearFinished removes all finished transfers from the list.
+func (m *TransferManager) ClearFinished() {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	var active []*Transfer
+	for _, t := range m.transfers {
+		t.mu.Lock()
+		if !t.finishedState() {
+			active = append(active, t)
+		}
+		t.mu.Unlock()
+	}
+	m.transfers = active
+	m.mu.Unlock()
+
+	m.notify(true)
+}
+
+// notify informs the listeners about a change. Progress updates are throttled
+// to avoid redrawing the screen for every chunk written.
+func (m *TransferManager) notify(force bool) {
+	m.mu.Lock()
+	if !force && time.Since(m.lastNotify) < transferProgressInterval {
+		m.mu.Unlock()
+		return
+	}
+	m.lastNotify = time.Now()
+	listeners := append([]func(){}, m.listeners...)
+	m.mu.Unlock()
+
+	m.queue(func() {
+		for _, listener := range listeners {
+			listener()
+		}
+	})
+}
+
+func (m *TransferManager) queue(f func()) {
+	m.mu.Lock()
+	queueUpdate := m.queueUpdate
+	m.mu.Unlock()
+
+	if queueUpdate != nil {
+		queueUpdate(f)
+		return
+	}
+	f()
+}
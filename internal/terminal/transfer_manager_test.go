@@ -0,0 +1,147 @@
+package terminal
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func waitForTransferState(t *testing.T, transfer *Transfer, state TransferState) TransferSnapshot {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		snapshot := transfer.Snapshot()
+		if snapshot.State == state {
+			return snapshot
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("transfer did not reach state %s, is %s", state, transfer.Snapshot().State)
+	return TransferSnapshot{}
+}
+
+func TestTransferManagerRunsTransferInBackground(t *testing.T) {
+	manager := NewTransferManager(1)
+	release := make(chan struct{})
+	done := make(chan error, 1)
+
+	transfer := NewTransfer(TransferUpload, "bucket", "key", func(ctx context.Context, progress func(transferred, total int64)) error {
+		progress(5, 10)
+		<-release
+		return nil
+	}, func(err error) { done <- err })
+
+	manager.Submit(transfer)
+	waitForTransferState(t, transfer, TransferRunning)
+	assert.Len(t, manager.Transfers(), 1)
+
+	close(release)
+	assert.NoError(t, <-done)
+
+	snapshot := waitForTransferState(t, transfer, TransferDone)
+	assert.Equal(t, int64(10), snapshot.Transferred)
+	assert.Equal(t, int64(10), snapshot.Total)
+}
+
+func TestTransferManagerLimitsConcurrency(t *testing.T) {
+	manager := NewTransferManager(1)
+	release := make(chan struct{})
+
+	blocking := func(ctx context.Context, progress func(transferred, total int64)) error {
+		<-release
+		return nil
+	}
+	first := NewTransfer(TransferUpload, "bucket", "first", blocking, nil)
+	second := NewTransfer(TransferUpload, "bucket", "second", blocking, nil)
+
+	manager.Submit(first)
+	waitForTransferState(t, first, TransferRunning)
+	manager.Submit(second)
+	assert.Equal(t, TransferQueued, second.Snapshot().State)
+
+	close(release)
+	waitForTransferState(t, first, TransferDone)
+	waitForTransferState(t, second, TransferDone)
+}
+
+func TestTransferManagerCancelAndRetry(t *testing.T) {
+	manager := NewTransferManager(1)
+	attempts := 0
+	done := make(chan error, 2)
+
+	transfer := NewTransfer(TransferDownload, "bucket", "key", func(ctx context.Context, progress func(transferred, total int64)) error {
+		attempts++
+		if attempts == 1 {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		return nil
+	}, func(err error) { done <- err })
+
+	manager.Submit(transfer)
+	waitForTransferState(t, transfer, TransferRunning)
+	manager.Cancel(transfer)
+	assert.ErrorIs(t, <-done, context.Canceled)
+	waitForTransferState(t, transfer, TransferCancelled)
+
+	manager.Retry(transfer)
+	assert.NoError(t, <-done)
+	waitForTransferState(t, transfer, TransferDone)
+	assert.Equal(t, 2, attempts)
+
+	manager.ClearFinished()
+	assert.Empty(t, manager.Transfers())
+}
+
+func TestTransferManagerFailedTransfer(t *testing.T) {
+	manager := NewTransferManager(1)
+	changes := make(chan struct{}, 10)
+	manager.OnChange(func() { changes <- struct{}{} })
+
+	transfer := NewTransfer(TransferDelete, "bucket", "key", func(ctx context.Context, progress func(transferred, total int64)) error {
+		return errors.New("boom")
+	}, nil)
+	manager.Submit(transfer)
+
+	snapshot := waitForTransferState(t, transfer, TransferFailed)
+	assert.EqualError(t, snapshot.Err, "boom")
+	assert.NotEmpty(t, changes)
+}
+
+func TestNilTransferManagerRunsSynchronously(t *testing.T) {
+	var manager *TransferManager
+	var doneErr error
+	called := false
+
+	transfer := NewTransfer(TransferUpload, "bucket", "key", func(ctx context.Context, progress func(transferred, total int64)) error {
+		return errors.New("failed")
+	}, func(err error) {
+		called = true
+		doneErr = err
+	})
+	manager.Submit(transfer)
+
+	assert.True(t, called)
+	assert.EqualError(t, doneErr, "failed")
+	assert.Equal(t, TransferFailed, transfer.Snapshot().State)
+}
+
+func TestTransfersPageRows(t *testing.T) {
+	manager := NewTransferManager(1)
+	transfer := NewTransfer(TransferUpload, "bucket", "dir/key.txt", func(ctx context.Context, progress func(transferred, total int64)) error {
+		progress(1024, 1024)
+		return nil
+	}, nil)
+	manager.Submit(transfer)
+	waitForTransferState(t, transfer, TransferDone)
+
+	page := NewTransfersPage(NewContext().WithTransfers(manager))
+	assert.NoError(t, page.Load())
+
+	rows := getTableRows(page.tviewTable)
+	assert.Equal(t, []string{"Type", "Object", "Status", "Progress", "Rate", "ETA"}, rows[0])
+	assert.Equal(t, []string{"Upload", "bucket/dir/key.txt", "Done", "1 KiB / 1 KiB (100%)"}, rows[1][:4])
+}
@@ -0,0 +1,117 @@
+package terminal
+
+import (
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+type TransfersPage struct {
+	*ListPage[*Transfer]
+
+	context Context
+}
+
+func NewTransfersPage(context Context) *TransfersPage {
+	listPage := NewListPage[*Transfer]()
+	listPage.SetMultiSelect(false)
+	listPage.AddColumn("Type", func(item *Transfer) string { return string(item.Kind) })
+	listPage.AddColumn("Object", func(item *Transfer) string {
+		if item.VersionID != "" {
+			return path.Join(item.Bucket, item.Key) + " (" + item.VersionID + ")"
+		}
+		return path.Join(item.Bucket, item.Key)
+	})
+	listPage.AddColumn("Status", func(item *Transfer) string {
+		snapshot := item.Snapshot()
+		if snapshot.State == TransferFailed && snapshot.Err != nil {
+			return snapshot.State.String() + ": " + snapshot.Err.Error()
+		}
+		return snapshot.State.String()
+	})
+	listPage.AddColumn("Progress", func(item *Transfer) string {
+		snapshot := item.Snapshot()
+		if snapshot.Total <= 0 {
+			return ""
+		}
+		return fmt.Sprintf("%s / %s (%d%%)",
+			humanizeSize(&snapshot.Transferred),
+			humanizeSize(&snapshot.Total),
+			snapshot.Transferred*100/snapshot.Total,
+		)
+	})
+	listPage.AddColumn("Rate", func(item *Transfer) string {
+		snapshot := item.Snapshot()
+		if snapshot.Rate <= 0 {
+			return ""
+		}
+		rate := int64(snapshot.Rate)
+		return humanizeSize(&rate) + "/s"
+	})
+	listPage.AddColumn("ETA", func(item *Transfer) string {
+		snapshot := item.Snapshot()
+		if snapshot.ETA <= 0 {
+			return ""
+		}
+		return snapshot.ETA.Round(time.Second).String()
+	})
+
+	page := &TransfersPage{
+		ListPage: listPage,
+		context:  context,
+	}
+
+	if transfers := context.Transfers(); transfers != nil {
+		transfers.OnChange(func() {
+			_ = page.Load()
+		})
+	}
+
+	return page
+}
+
+func (b *TransfersPage) Title() string {
+	return "Transfers"
+}
+
+func (b *TransfersPage) Context() Context {
+	return b.context
+}
+
+// transfersHotkeyKeys are the keys of the hotkeys of TransfersPage.
+var transfersHotkeyKeys = hotkeyKeys{
+	"Cancel Transfer": EventKey(tcell.KeyRune, 'c', 0),
+	"Retry Transfer":  EventKey(tcell.KeyRune, 'r', 0),
+	"Clear Finished":  EventKey(tcell.KeyRune, 'x', 0),
+}
+
+func (b *TransfersPage) Hotkeys() map[tcell.EventKey]Hotkey {
+	return transfersHotkeyKeys.bind(map[string]func(*tcell.EventKey) *tcell.EventKey{
+		"Cancel Transfer": func(event *tcell.EventKey) *tcell.EventKey {
+			if transfer, ok := b.GetSelectedRow(); ok {
+				b.context.Transfers().Cancel(transfer)
+			}
+			return nil
+		},
+		"Retry Transfer": func(event *tcell.EventKey) *tcell.EventKey {
+			if transfer, ok := b.GetSelectedRow(); ok {
+				b.context.Transfers().Retry(transfer)
+			}
+			return nil
+		},
+		"Clear Finished": func(event *tcell.EventKey) *tcell.EventKey {
+			b.context.Transfers().ClearFinished()
+			return nil
+		},
+	})
+}
+
+func (b *TransfersPage) Load() error {
+	b.ClearRows()
+	if transfers := b.context.Transfers(); transfers != nil {
+		b.AddAll(transfers.Transfers())
+	}
+	return nil
+}